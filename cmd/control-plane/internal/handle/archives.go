@@ -0,0 +1,52 @@
+package handle
+
+//go:generate mockgen -source=archives.go -destination=mocks/mock_archives.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/store"
+)
+
+type ArchiveService interface {
+	Manifest(ctx context.Context, day time.Time) (store.Manifest, error)
+	Reconcile(ctx context.Context, day time.Time) (store.ArchiveReport, error)
+}
+
+// GetArchive reports what the store archived on a day (YYYY-MM-DD), in the
+// archive of the tenant of the request. With ?verify=true every object is also
+// checked against the bucket.
+func GetArchive(archiveService ArchiveService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /archives/{date}", func(w http.ResponseWriter, r *http.Request) {
+		day, err := time.Parse(time.DateOnly, r.PathValue("date"))
+		if err != nil {
+			http.Error(w, "date must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+
+		var report store.ArchiveReport
+		if r.URL.Query().Get("verify") == "true" {
+			report, err = archiveService.Reconcile(r.Context(), day)
+		} else {
+			var manifest store.Manifest
+			manifest, err = archiveService.Manifest(r.Context(), day)
+			report = manifest.Report()
+		}
+		switch {
+		case errors.Is(err, store.ErrManifestNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case errors.Is(err, store.ErrUnknownTenant):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,101 @@
+package handle
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetArchive(t *testing.T) {
+	day := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+	object := store.ArchivedObject{Key: "user:123", Date: "2025-01-14", Path: "audits/user:123/2025-01-14.json", Records: 3, Size: 120}
+
+	tests := []struct {
+		name            string
+		url             string
+		setupMock       func(m *mocks.MockArchiveService)
+		expectedStatus  int
+		expectedRecords int
+	}{
+		{
+			name: "success - returns the manifest report",
+			url:  "/archives/2025-01-14",
+			setupMock: func(m *mocks.MockArchiveService) {
+				m.EXPECT().Manifest(gomock.Any(), day).Return(store.Manifest{Date: "2025-01-14", Objects: []store.ArchivedObject{object}}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedRecords: 3,
+		},
+		{
+			name: "success - verify reconciles with the bucket",
+			url:  "/archives/2025-01-14?verify=true",
+			setupMock: func(m *mocks.MockArchiveService) {
+				m.EXPECT().Reconcile(gomock.Any(), day).Return(store.ArchiveReport{Date: "2025-01-14", Records: 3, Verified: true}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedRecords: 3,
+		},
+		{
+			name:           "error - invalid date returns 400",
+			url:            "/archives/2025-1-14",
+			setupMock:      func(m *mocks.MockArchiveService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - day not archived returns 404",
+			url:  "/archives/2025-01-14",
+			setupMock: func(m *mocks.MockArchiveService) {
+				m.EXPECT().Manifest(gomock.Any(), day).Return(store.Manifest{}, store.ErrManifestNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "error - bucket fails returns 500",
+			url:  "/archives/2025-01-14?verify=true",
+			setupMock: func(m *mocks.MockArchiveService) {
+				m.EXPECT().Reconcile(gomock.Any(), day).Return(store.ArchiveReport{}, errors.New("access denied"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockArchiveService(ctrl)
+			tt.setupMock(mockService)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc(GetArchive(mockService))
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var report store.ArchiveReport
+			if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			if report.Records != tt.expectedRecords {
+				t.Errorf("expected %d records, got %d", tt.expectedRecords, report.Records)
+			}
+		})
+	}
+}
@@ -5,18 +5,76 @@ package handle
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/IsaacDSC/auditory/internal/audit"
 	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type AuditStoreService interface {
 	Save(ctx context.Context, input audit.DataAudit) (string, error)
 }
 
-func AuditStore(auditStoreService AuditStoreService) (string, func(w http.ResponseWriter, r *http.Request)) {
+var ErrInvalidAudit = errors.New("invalid audit")
+
+// SaveAudit is the ingestion path shared by POST /audit and the gRPC API:
+// metadata validation, then the idempotent save. The audit records the ids of
+// its ingestion span and the tenant of ctx, and a missing correlation id falls
+// back to the trace id.
+func SaveAudit(ctx context.Context, auditStoreService AuditStoreService, input audit.DataAudit) (_ string, err error) {
+	ctx, span := telemetry.Start(ctx, "audit.ingest",
+		attribute.String("audit.key", input.Metadata.Key),
+		attribute.String("audit.event_name", input.Metadata.EventName),
+	)
+	defer func() { telemetry.End(span, err) }()
+	defer func() { metrics.ObserveAudit(input.Metadata.Key, auditResult(err)) }()
+
+	input.Metadata.TraceID, input.Metadata.SpanID = telemetry.IDs(ctx)
+	if input.Metadata.CorrelationID == "" {
+		input.Metadata.CorrelationID = input.Metadata.TraceID
+	}
+	// the tenant comes from the caller, never from the body
+	input.Metadata.Tenant, _ = ctxkey.Tenant(ctx)
+
+	ctx = ctxkey.SetAuditKey(ctx, input.Metadata.Key)
+	ctx = ctxkey.SetRequestID(ctx, input.Metadata.RequestID)
+	ctx = ctxkey.SetCorrelationID(ctx, input.Metadata.CorrelationID)
+
+	// validate all fields are not empty
+	if err := input.Metadata.Validate(); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidAudit, err)
+	}
+
+	return auditStoreService.Save(ctx, input)
+}
+
+func auditResult(err error) string {
+	switch {
+	case err == nil:
+		return metrics.ResultAccepted
+	case errors.Is(err, ErrInvalidAudit):
+		return metrics.ResultRejected
+	case errors.Is(err, backup.ErrIdempotencyKeyAlreadyExists):
+		return metrics.ResultDuplicate
+	case store.Throttled(err):
+		return metrics.ResultThrottled
+	default:
+		return metrics.ResultFailed
+	}
+}
+
+// AuditStore answers 429 with Retry-After set to retryAfter while the local
+// store is under pressure or out of quota.
+func AuditStore(auditStoreService AuditStoreService, retryAfter time.Duration) (string, func(w http.ResponseWriter, r *http.Request)) {
 	return "POST /audit", func(w http.ResponseWriter, r *http.Request) {
 		var input audit.DataAudit
 		err := json.NewDecoder(r.Body).Decode(&input)
@@ -28,18 +86,20 @@ func AuditStore(auditStoreService AuditStoreService) (string, func(w http.Respon
 		input.Metadata.RequestID = r.Header.Get("X-Request-ID")
 		input.Metadata.CorrelationID = r.Header.Get("X-Correlation-ID")
 
-		// validate all fields are not empty
-		if err := input.Metadata.Validate(); err != nil {
+		ctx := telemetry.Extract(r.Context(), r.Header)
+		idepotency_key, err := SaveAudit(ctx, auditStoreService, input)
+		switch {
+		case errors.Is(err, ErrInvalidAudit):
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
-		}
-
-		idepotency_key, err := auditStoreService.Save(r.Context(), input)
-		switch err {
-		case backup.ErrIdempotencyKeyAlreadyExists:
+		case errors.Is(err, backup.ErrIdempotencyKeyAlreadyExists):
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
-		case nil:
+		case store.Throttled(err):
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		case err == nil:
 			w.WriteHeader(http.StatusCreated)
 			_, _ = w.Write([]byte(fmt.Sprintf(`{"idempotency_key": "%s" , "ttl": "5min"}`, idepotency_key)))
 		default:
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,6 +14,8 @@ import (
 	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
 	"github.com/IsaacDSC/auditory/internal/audit"
 	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
 	"go.uber.org/mock/gomock"
 )
 
@@ -99,6 +102,18 @@ func TestAuditStore(t *testing.T) {
 			},
 			expectedStatus: http.StatusConflict,
 		},
+		{
+			name:          "error - local store under pressure returns 429",
+			body:          validInput,
+			requestID:     "req-123",
+			correlationID: "corr-456",
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().
+					Save(gomock.Any(), gomock.Any()).
+					Return("", fmt.Errorf("failed to save data: %w", store.ErrBackpressure))
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
 		{
 			name:          "error - internal server error returns 500",
 			body:          validInput,
@@ -121,7 +136,7 @@ func TestAuditStore(t *testing.T) {
 			mockService := mocks.NewMockAuditStoreService(ctrl)
 			tt.setupMock(mockService)
 
-			_, handler := AuditStore(mockService)
+			_, handler := AuditStore(mockService, 30*time.Second)
 
 			var bodyBytes []byte
 			switch v := tt.body.(type) {
@@ -152,6 +167,115 @@ func TestAuditStore(t *testing.T) {
 			if tt.expectedBody != "" && rr.Body.String() != tt.expectedBody {
 				t.Errorf("expected body %q, got %q", tt.expectedBody, rr.Body.String())
 			}
+
+			if tt.expectedStatus == http.StatusTooManyRequests && rr.Header().Get("Retry-After") != "30" {
+				t.Errorf("expected Retry-After 30, got %q", rr.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestAuditStore_TraceContext(t *testing.T) {
+	const (
+		traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	)
+
+	tests := []struct {
+		name                  string
+		traceparent           string
+		correlationID         string
+		expectedCorrelationID string
+		expectedTraceID       string
+		expectedStatus        int
+	}{
+		{
+			name:                  "success - correlation id falls back to trace id",
+			traceparent:           traceparent,
+			expectedCorrelationID: traceID,
+			expectedTraceID:       traceID,
+			expectedStatus:        http.StatusCreated,
+		},
+		{
+			name:                  "success - correlation header wins over trace id",
+			traceparent:           traceparent,
+			correlationID:         "corr-456",
+			expectedCorrelationID: "corr-456",
+			expectedTraceID:       traceID,
+			expectedStatus:        http.StatusCreated,
+		},
+		{
+			name:           "error - malformed traceparent gives no fallback",
+			traceparent:    "00-not-a-trace-01",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuditStoreService(ctrl)
+			if tt.expectedStatus == http.StatusCreated {
+				mockService.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) (string, error) {
+					if input.Metadata.CorrelationID != tt.expectedCorrelationID {
+						t.Errorf("expected correlation id %q, got %q", tt.expectedCorrelationID, input.Metadata.CorrelationID)
+					}
+					if input.Metadata.TraceID != tt.expectedTraceID || input.Metadata.SpanID == "" {
+						t.Errorf("expected trace %q with a span id, got %+v", tt.expectedTraceID, input.Metadata)
+					}
+					return "key", nil
+				})
+			}
+
+			_, handler := AuditStore(mockService, 30*time.Second)
+
+			body, _ := json.Marshal(audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.created"}})
+			req := httptest.NewRequest(http.MethodPost, "/audit", bytes.NewReader(body))
+			req.Header.Set("X-Request-ID", "req-123")
+			req.Header.Set("X-Correlation-ID", tt.correlationID)
+			req.Header.Set("traceparent", tt.traceparent)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestSaveAudit_Tenant(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		bodyTenant     string
+		expectedTenant string
+	}{
+		{name: "success - tenant of the caller", ctx: ctxkey.SetTenant(context.Background(), "acme"), expectedTenant: "acme"},
+		{name: "success - body cannot pick a tenant", ctx: context.Background(), bodyTenant: "acme"},
+		{name: "success - body cannot override the caller", ctx: ctxkey.SetTenant(context.Background(), "acme"), bodyTenant: "globex", expectedTenant: "acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuditStoreService(ctrl)
+			mockService.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) (string, error) {
+				if input.Metadata.Tenant != tt.expectedTenant {
+					t.Errorf("expected tenant %q, got %q", tt.expectedTenant, input.Metadata.Tenant)
+				}
+				return "key", nil
+			})
+
+			input := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.created", RequestID: "req-1", CorrelationID: "corr-1", Tenant: tt.bodyTenant}}
+			if _, err := SaveAudit(tt.ctx, mockService, input); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
 		})
 	}
 }
@@ -0,0 +1,122 @@
+package handle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/auth"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+func TestRequireToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "success - valid token reaches handler", authorization: "Bearer secret", expectedStatus: http.StatusNoContent},
+		{name: "error - missing token returns 401", expectedStatus: http.StatusUnauthorized},
+		{name: "error - invalid token returns 401", authorization: "Bearer nope", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, handler := RequireToken(auth.NewTokenAuthenticator([]string{"secret"}))("POST /audit", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+			if pattern != "POST /audit" {
+				t.Fatalf("expected pattern to be kept, got %s", pattern)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/audit", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestRejectTenantTokens(t *testing.T) {
+	resolver := auth.NewTenantResolver([]string{"acme"}, map[string]string{"acme-token": "acme"})
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "success - unbound token reaches handler", authorization: "Bearer secret", expectedStatus: http.StatusNoContent},
+		{name: "error - token of a tenant returns 403", authorization: "Bearer acme-token", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, handler := RejectTenantTokens(resolver)("POST /backup", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/backup", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestResolveTenant(t *testing.T) {
+	resolver := auth.NewTenantResolver([]string{"acme", "globex"}, map[string]string{"acme-token": "acme"})
+
+	tests := []struct {
+		name           string
+		authorization  string
+		tenantID       string
+		expectedStatus int
+		expectedTenant string
+	}{
+		{name: "success - shared namespace", expectedStatus: http.StatusNoContent},
+		{name: "success - tenant of the token", authorization: "Bearer acme-token", expectedStatus: http.StatusNoContent, expectedTenant: "acme"},
+		{name: "success - tenant header", tenantID: "globex", expectedStatus: http.StatusNoContent, expectedTenant: "globex"},
+		{name: "error - token of another tenant returns 403", authorization: "Bearer acme-token", tenantID: "globex", expectedStatus: http.StatusForbidden},
+		{name: "error - unknown tenant returns 400", tenantID: "initech", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tenant string
+			_, handler := ResolveTenant(resolver)("POST /audit", func(w http.ResponseWriter, r *http.Request) {
+				tenant, _ = ctxkey.Tenant(r.Context())
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/audit", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.tenantID != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenantID)
+			}
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tenant != tt.expectedTenant {
+				t.Errorf("expected tenant %q, got %q", tt.expectedTenant, tenant)
+			}
+		})
+	}
+}
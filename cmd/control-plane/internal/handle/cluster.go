@@ -0,0 +1,79 @@
+package handle
+
+//go:generate mockgen -source=cluster.go -destination=mocks/mock_cluster.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/cluster"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+)
+
+type HandoffMerger interface {
+	Merge(ctx context.Context, key store.Key, data store.Data) error
+}
+
+// ClusterAudit saves an audit forwarded by the node that received it. It was
+// validated and counted there, so it goes straight to the local service.
+func ClusterAudit(auditStoreService AuditStoreService, secret string) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "POST /cluster/audits", func(w http.ResponseWriter, r *http.Request) {
+		if !cluster.Authorized(r, secret) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var input audit.DataAudit
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := telemetry.Extract(r.Context(), r.Header)
+		idempotencyKey, err := auditStoreService.Save(ctx, input)
+		switch {
+		case errors.Is(err, backup.ErrIdempotencyKeyAlreadyExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(cluster.SaveResponse{IdempotencyKey: idempotencyKey})
+	}
+}
+
+// ClusterHandoff merges the events another node held for a key this node owns.
+func ClusterHandoff(merger HandoffMerger, secret string) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "POST /cluster/handoff", func(w http.ResponseWriter, r *http.Request) {
+		if !cluster.Authorized(r, secret) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var handoff cluster.Handoff
+		if err := json.NewDecoder(r.Body).Decode(&handoff); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if handoff.Key == "" {
+			http.Error(w, "key is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := merger.Merge(r.Context(), store.Key(handoff.Key), handoff.Data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
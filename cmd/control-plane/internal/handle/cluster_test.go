@@ -0,0 +1,152 @@
+package handle
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/cluster"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClusterAudit(t *testing.T) {
+	tests := []struct {
+		name           string
+		secret         string
+		body           string
+		setupMock      func(m *mocks.MockAuditStoreService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "success - saves forwarded audit locally",
+			secret: "s3cret",
+			body:   `{"metadata":{"key":"user:123","event_name":"user.created"},"data":{}}`,
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).Return("user:123-user.created--", nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"idempotency_key":"user:123-user.created--"}`,
+		},
+		{
+			name:           "error - missing secret returns 401",
+			secret:         "",
+			body:           `{}`,
+			setupMock:      func(m *mocks.MockAuditStoreService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "error - duplicate returns 409",
+			secret: "s3cret",
+			body:   `{"metadata":{"key":"user:123"}}`,
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", backup.ErrIdempotencyKeyAlreadyExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "error - save fails returns 500",
+			secret: "s3cret",
+			body:   `{"metadata":{"key":"user:123"}}`,
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", errors.New("disk full"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuditStoreService(ctrl)
+			tt.setupMock(mockService)
+
+			_, handler := ClusterAudit(mockService, "s3cret")
+
+			req := httptest.NewRequest(http.MethodPost, "/cluster/audits", strings.NewReader(tt.body))
+			req.Header.Set(cluster.SecretHeader, tt.secret)
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedBody != "" && strings.TrimSpace(rr.Body.String()) != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestClusterHandoff(t *testing.T) {
+	tests := []struct {
+		name           string
+		secret         string
+		body           string
+		setupMock      func(m *mocks.MockHandoffMerger)
+		expectedStatus int
+	}{
+		{
+			name:   "success - merges handed off key",
+			secret: "s3cret",
+			body:   `{"key":"user:123","data":{"2025-1-15":[{"metadata":{"key":"user:123"}}]}}`,
+			setupMock: func(m *mocks.MockHandoffMerger) {
+				m.EXPECT().Merge(gomock.Any(), store.Key("user:123"), gomock.Len(1)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "error - wrong secret returns 401",
+			secret:         "guess",
+			body:           `{"key":"user:123"}`,
+			setupMock:      func(m *mocks.MockHandoffMerger) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "error - missing key returns 400",
+			secret:         "s3cret",
+			body:           `{"data":{}}`,
+			setupMock:      func(m *mocks.MockHandoffMerger) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "error - merge fails returns 500",
+			secret: "s3cret",
+			body:   `{"key":"user:123","data":{}}`,
+			setupMock: func(m *mocks.MockHandoffMerger) {
+				m.EXPECT().Merge(gomock.Any(), store.Key("user:123"), gomock.Any()).Return(errors.New("disk full"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMerger := mocks.NewMockHandoffMerger(ctrl)
+			tt.setupMock(mockMerger)
+
+			_, handler := ClusterHandoff(mockMerger, "s3cret")
+
+			req := httptest.NewRequest(http.MethodPost, "/cluster/handoff", strings.NewReader(tt.body))
+			req.Header.Set(cluster.SecretHeader, tt.secret)
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: archives.go
+//
+// Generated by this command:
+//
+//	mockgen -source=archives.go -destination=mocks/mock_archives.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	store "github.com/IsaacDSC/auditory/internal/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchiveService is a mock of ArchiveService interface.
+type MockArchiveService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockArchiveServiceMockRecorder is the mock recorder for MockArchiveService.
+type MockArchiveServiceMockRecorder struct {
+	mock *MockArchiveService
+}
+
+// NewMockArchiveService creates a new mock instance.
+func NewMockArchiveService(ctrl *gomock.Controller) *MockArchiveService {
+	mock := &MockArchiveService{ctrl: ctrl}
+	mock.recorder = &MockArchiveServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchiveService) EXPECT() *MockArchiveServiceMockRecorder {
+	return m.recorder
+}
+
+// Manifest mocks base method.
+func (m *MockArchiveService) Manifest(ctx context.Context, day time.Time) (store.Manifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Manifest", ctx, day)
+	ret0, _ := ret[0].(store.Manifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Manifest indicates an expected call of Manifest.
+func (mr *MockArchiveServiceMockRecorder) Manifest(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Manifest", reflect.TypeOf((*MockArchiveService)(nil).Manifest), ctx, day)
+}
+
+// Reconcile mocks base method.
+func (m *MockArchiveService) Reconcile(ctx context.Context, day time.Time) (store.ArchiveReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, day)
+	ret0, _ := ret[0].(store.ArchiveReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockArchiveServiceMockRecorder) Reconcile(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockArchiveService)(nil).Reconcile), ctx, day)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/control-plane/internal/handle/cluster.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cmd/control-plane/internal/handle/cluster.go -destination=cmd/control-plane/internal/handle/mocks/mock_cluster.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	store "github.com/IsaacDSC/auditory/internal/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHandoffMerger is a mock of HandoffMerger interface.
+type MockHandoffMerger struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandoffMergerMockRecorder
+	isgomock struct{}
+}
+
+// MockHandoffMergerMockRecorder is the mock recorder for MockHandoffMerger.
+type MockHandoffMergerMockRecorder struct {
+	mock *MockHandoffMerger
+}
+
+// NewMockHandoffMerger creates a new mock instance.
+func NewMockHandoffMerger(ctrl *gomock.Controller) *MockHandoffMerger {
+	mock := &MockHandoffMerger{ctrl: ctrl}
+	mock.recorder = &MockHandoffMergerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandoffMerger) EXPECT() *MockHandoffMergerMockRecorder {
+	return m.recorder
+}
+
+// Merge mocks base method.
+func (m *MockHandoffMerger) Merge(ctx context.Context, key store.Key, data store.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockHandoffMergerMockRecorder) Merge(ctx, key, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockHandoffMerger)(nil).Merge), ctx, key, data)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: restore.go
+//
+// Generated by this command:
+//
+//	mockgen -source=restore.go -destination=mocks/mock_restore.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	backup "github.com/IsaacDSC/auditory/internal/backup"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRestoreService is a mock of RestoreService interface.
+type MockRestoreService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRestoreServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRestoreServiceMockRecorder is the mock recorder for MockRestoreService.
+type MockRestoreServiceMockRecorder struct {
+	mock *MockRestoreService
+}
+
+// NewMockRestoreService creates a new mock instance.
+func NewMockRestoreService(ctrl *gomock.Controller) *MockRestoreService {
+	mock := &MockRestoreService{ctrl: ctrl}
+	mock.recorder = &MockRestoreServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRestoreService) EXPECT() *MockRestoreServiceMockRecorder {
+	return m.recorder
+}
+
+// ReplayStatus mocks base method.
+func (m *MockRestoreService) ReplayStatus(ctx context.Context) backup.ReplayStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayStatus", ctx)
+	ret0, _ := ret[0].(backup.ReplayStatus)
+	return ret0
+}
+
+// ReplayStatus indicates an expected call of ReplayStatus.
+func (mr *MockRestoreServiceMockRecorder) ReplayStatus(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayStatus", reflect.TypeOf((*MockRestoreService)(nil).ReplayStatus), ctx)
+}
+
+// Restore mocks base method.
+func (m *MockRestoreService) Restore(ctx context.Context, req backup.RestoreRequest) (backup.RestoreResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, req)
+	ret0, _ := ret[0].(backup.RestoreResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRestoreServiceMockRecorder) Restore(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRestoreService)(nil).Restore), ctx, req)
+}
+
+// StartReplay mocks base method.
+func (m *MockRestoreService) StartReplay(ctx context.Context, req backup.ReplayRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartReplay", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartReplay indicates an expected call of StartReplay.
+func (mr *MockRestoreServiceMockRecorder) StartReplay(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartReplay", reflect.TypeOf((*MockRestoreService)(nil).StartReplay), ctx, req)
+}
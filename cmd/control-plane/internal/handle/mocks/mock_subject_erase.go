@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/control-plane/internal/handle/subject_erase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cmd/control-plane/internal/handle/subject_erase.go -destination=cmd/control-plane/internal/handle/mocks/mock_subject_erase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSubjectEraseService is a mock of SubjectEraseService interface.
+type MockSubjectEraseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubjectEraseServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSubjectEraseServiceMockRecorder is the mock recorder for MockSubjectEraseService.
+type MockSubjectEraseServiceMockRecorder struct {
+	mock *MockSubjectEraseService
+}
+
+// NewMockSubjectEraseService creates a new mock instance.
+func NewMockSubjectEraseService(ctrl *gomock.Controller) *MockSubjectEraseService {
+	mock := &MockSubjectEraseService{ctrl: ctrl}
+	mock.recorder = &MockSubjectEraseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubjectEraseService) EXPECT() *MockSubjectEraseServiceMockRecorder {
+	return m.recorder
+}
+
+// Erase mocks base method.
+func (m *MockSubjectEraseService) Erase(ctx context.Context, metadata audit.MetadataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", ctx, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Erase indicates an expected call of Erase.
+func (mr *MockSubjectEraseServiceMockRecorder) Erase(ctx, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockSubjectEraseService)(nil).Erase), ctx, metadata)
+}
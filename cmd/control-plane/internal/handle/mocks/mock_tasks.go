@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/control-plane/internal/handle/tasks.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cmd/control-plane/internal/handle/tasks.go -destination=cmd/control-plane/internal/handle/mocks/mock_tasks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	scheduler "github.com/IsaacDSC/auditory/internal/scheduler"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTaskLister is a mock of TaskLister interface.
+type MockTaskLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskListerMockRecorder
+	isgomock struct{}
+}
+
+// MockTaskListerMockRecorder is the mock recorder for MockTaskLister.
+type MockTaskListerMockRecorder struct {
+	mock *MockTaskLister
+}
+
+// NewMockTaskLister creates a new mock instance.
+func NewMockTaskLister(ctrl *gomock.Controller) *MockTaskLister {
+	mock := &MockTaskLister{ctrl: ctrl}
+	mock.recorder = &MockTaskListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskLister) EXPECT() *MockTaskListerMockRecorder {
+	return m.recorder
+}
+
+// Tasks mocks base method.
+func (m *MockTaskLister) Tasks() []scheduler.TaskStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tasks")
+	ret0, _ := ret[0].([]scheduler.TaskStatus)
+	return ret0
+}
+
+// Tasks indicates an expected call of Tasks.
+func (mr *MockTaskListerMockRecorder) Tasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tasks", reflect.TypeOf((*MockTaskLister)(nil).Tasks))
+}
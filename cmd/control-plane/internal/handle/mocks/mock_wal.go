@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: wal.go
+//
+// Generated by this command:
+//
+//	mockgen -source=wal.go -destination=mocks/mock_wal.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWALApplier is a mock of WALApplier interface.
+type MockWALApplier struct {
+	ctrl     *gomock.Controller
+	recorder *MockWALApplierMockRecorder
+	isgomock struct{}
+}
+
+// MockWALApplierMockRecorder is the mock recorder for MockWALApplier.
+type MockWALApplierMockRecorder struct {
+	mock *MockWALApplier
+}
+
+// NewMockWALApplier creates a new mock instance.
+func NewMockWALApplier(ctrl *gomock.Controller) *MockWALApplier {
+	mock := &MockWALApplier{ctrl: ctrl}
+	mock.recorder = &MockWALApplierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWALApplier) EXPECT() *MockWALApplierMockRecorder {
+	return m.recorder
+}
+
+// ApplyAudit mocks base method.
+func (m *MockWALApplier) ApplyAudit(ctx context.Context, input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyAudit", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyAudit indicates an expected call of ApplyAudit.
+func (mr *MockWALApplierMockRecorder) ApplyAudit(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyAudit", reflect.TypeOf((*MockWALApplier)(nil).ApplyAudit), ctx, input)
+}
@@ -0,0 +1,119 @@
+package handle
+
+//go:generate mockgen -source=restore.go -destination=mocks/mock_restore.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/store"
+)
+
+type RestoreService interface {
+	Restore(ctx context.Context, req backup.RestoreRequest) (backup.RestoreResult, error)
+	StartReplay(ctx context.Context, req backup.ReplayRequest) error
+	ReplayStatus(ctx context.Context) backup.ReplayStatus
+}
+
+// restoreInput takes the days as YYYY-MM-DD; to defaults to from.
+type restoreInput struct {
+	Keys   []string `json:"keys"`
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Source string   `json:"source"`
+	Sinks  []string `json:"sinks"`
+	Rate   float64  `json:"rate"`
+}
+
+func (ri restoreInput) request() (backup.RestoreRequest, error) {
+	from, err := time.Parse(time.DateOnly, ri.From)
+	if err != nil {
+		return backup.RestoreRequest{}, fmt.Errorf("%w: from must be YYYY-MM-DD", backup.ErrInvalidRestore)
+	}
+	to := from
+	if ri.To != "" {
+		if to, err = time.Parse(time.DateOnly, ri.To); err != nil {
+			return backup.RestoreRequest{}, fmt.Errorf("%w: to must be YYYY-MM-DD", backup.ErrInvalidRestore)
+		}
+	}
+
+	source := ri.Source
+	if source == "" {
+		source = backup.SourceObjects
+	}
+
+	return backup.RestoreRequest{Keys: ri.Keys, From: from, To: to, Source: source}, nil
+}
+
+func Restore(restoreService RestoreService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "POST /restore", func(w http.ResponseWriter, r *http.Request) {
+		var input restoreInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		req, err := input.request()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := restoreService.Restore(r.Context(), req)
+		switch {
+		case errors.Is(err, backup.ErrInvalidRestore), errors.Is(err, store.ErrUnknownTenant):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// StartReplay answers once the replay started; GET /replay follows it.
+func StartReplay(restoreService RestoreService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "POST /replay", func(w http.ResponseWriter, r *http.Request) {
+		var input restoreInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		req, err := input.request()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// the replay outlives the request
+		ctx := context.WithoutCancel(r.Context())
+		err = restoreService.StartReplay(ctx, backup.ReplayRequest{RestoreRequest: req, Sinks: input.Sinks, Rate: input.Rate})
+		switch {
+		case errors.Is(err, backup.ErrInvalidRestore), errors.Is(err, store.ErrUnknownTenant):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, backup.ErrReplayRunning):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusAccepted, restoreService.ReplayStatus(r.Context()))
+	}
+}
+
+func GetReplay(restoreService RestoreService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /replay", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, restoreService.ReplayStatus(r.Context()))
+	}
+}
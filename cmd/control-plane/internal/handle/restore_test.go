@@ -0,0 +1,142 @@
+package handle
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRestore(t *testing.T) {
+	day := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(m *mocks.MockRestoreService)
+		expectedStatus int
+	}{
+		{
+			name: "success - to and source default to from and objects",
+			body: `{"keys":["user:123"],"from":"2025-01-14"}`,
+			setupMock: func(m *mocks.MockRestoreService) {
+				m.EXPECT().Restore(gomock.Any(), backup.RestoreRequest{Keys: []string{"user:123"}, From: day, To: day, Source: backup.SourceObjects}).
+					Return(backup.RestoreResult{Keys: 1, Events: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "error - invalid date returns 400",
+			body:           `{"from":"14/01/2025"}`,
+			setupMock:      func(m *mocks.MockRestoreService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - invalid request returns 400",
+			body: `{"from":"2025-01-14","source":"tape"}`,
+			setupMock: func(m *mocks.MockRestoreService) {
+				m.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(backup.RestoreResult{}, backup.ErrInvalidRestore)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - archive fails returns 500",
+			body: `{"from":"2025-01-14","to":"2025-01-15","source":"snapshots"}`,
+			setupMock: func(m *mocks.MockRestoreService) {
+				m.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(backup.RestoreResult{}, errors.New("access denied"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockRestoreService(ctrl)
+			tt.setupMock(mockService)
+
+			_, handler := Restore(mockService)
+			req := httptest.NewRequest(http.MethodPost, "/restore", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestStartReplay(t *testing.T) {
+	day := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(m *mocks.MockRestoreService)
+		expectedStatus int
+	}{
+		{
+			name: "success - starts the replay",
+			body: `{"from":"2025-01-14","sinks":["sql"],"rate":50}`,
+			setupMock: func(m *mocks.MockRestoreService) {
+				m.EXPECT().StartReplay(gomock.Any(), backup.ReplayRequest{
+					RestoreRequest: backup.RestoreRequest{From: day, To: day, Source: backup.SourceObjects},
+					Sinks:          []string{"sql"},
+					Rate:           50,
+				}).Return(nil)
+				m.EXPECT().ReplayStatus(gomock.Any()).Return(backup.ReplayStatus{Running: true})
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "error - invalid body returns 400",
+			body:           `{`,
+			setupMock:      func(m *mocks.MockRestoreService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - unknown sink returns 400",
+			body: `{"from":"2025-01-14","sinks":["kafka"]}`,
+			setupMock: func(m *mocks.MockRestoreService) {
+				m.EXPECT().StartReplay(gomock.Any(), gomock.Any()).Return(backup.ErrInvalidRestore)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - replay running returns 409",
+			body: `{"from":"2025-01-14"}`,
+			setupMock: func(m *mocks.MockRestoreService) {
+				m.EXPECT().StartReplay(gomock.Any(), gomock.Any()).Return(backup.ErrReplayRunning)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockRestoreService(ctrl)
+			tt.setupMock(mockService)
+
+			_, handler := StartReplay(mockService)
+			req := httptest.NewRequest(http.MethodPost, "/replay", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
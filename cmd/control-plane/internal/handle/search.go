@@ -0,0 +1,55 @@
+package handle
+
+//go:generate mockgen -source=search.go -destination=mocks/mock_search.go -package=mocks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/search"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 1000
+)
+
+type SearchService interface {
+	Search(tenant, query string, limit int) ([]audit.DataAudit, error)
+}
+
+func Search(searchService SearchService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /search", func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultSearchLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 || parsed > maxSearchLimit {
+				http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		tenant, _ := ctxkey.Tenant(r.Context())
+		results, err := searchService.Search(tenant, r.URL.Query().Get("q"), limit)
+		switch {
+		case errors.Is(err, search.ErrEmptyQuery):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"total":   len(results),
+			"results": results,
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package handle
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/search"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		tenant         string
+		setupMock      func(m *mocks.MockSearchService)
+		expectedStatus int
+	}{
+		{
+			name: "success - returns 200 with results",
+			url:  "/search?q=key:order:42",
+			setupMock: func(m *mocks.MockSearchService) {
+				m.EXPECT().
+					Search("", "key:order:42", defaultSearchLimit).
+					Return([]audit.DataAudit{{Metadata: audit.MetadataAudit{Key: "order:42"}}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "success - custom limit",
+			url:  "/search?q=alice&limit=10",
+			setupMock: func(m *mocks.MockSearchService) {
+				m.EXPECT().Search("", "alice", 10).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "success - searches the tenant of the caller",
+			url:    "/search?q=alice",
+			tenant: "acme",
+			setupMock: func(m *mocks.MockSearchService) {
+				m.EXPECT().Search("acme", "alice", defaultSearchLimit).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "error - invalid limit returns 400",
+			url:            "/search?q=alice&limit=abc",
+			setupMock:      func(m *mocks.MockSearchService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - empty query returns 400",
+			url:  "/search",
+			setupMock: func(m *mocks.MockSearchService) {
+				m.EXPECT().Search("", "", defaultSearchLimit).Return(nil, search.ErrEmptyQuery)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - search fails returns 500",
+			url:  "/search?q=alice",
+			setupMock: func(m *mocks.MockSearchService) {
+				m.EXPECT().Search("", "alice", defaultSearchLimit).Return(nil, errors.New("index corrupted"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockSearchService(ctrl)
+			tt.setupMock(mockService)
+
+			_, handler := Search(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.tenant != "" {
+				req = req.WithContext(ctxkey.SetTenant(req.Context(), tt.tenant))
+			}
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
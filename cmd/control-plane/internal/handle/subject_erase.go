@@ -0,0 +1,48 @@
+package handle
+
+//go:generate mockgen -source=subject_erase.go -destination=mocks/mock_subject_erase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+type SubjectEraseService interface {
+	Erase(ctx context.Context, metadata audit.MetadataAudit) error
+}
+
+func SubjectErase(eraseService SubjectEraseService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "DELETE /subjects/{key}", func(w http.ResponseWriter, r *http.Request) {
+		metadata := audit.MetadataAudit{
+			Key:           r.PathValue("key"),
+			EventName:     backup.SubjectErasedEvent,
+			RequestID:     r.Header.Get("X-Request-ID"),
+			CorrelationID: r.Header.Get("X-Correlation-ID"),
+		}
+		metadata.Tenant, _ = ctxkey.Tenant(r.Context())
+
+		if err := metadata.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err := eraseService.Erase(r.Context(), metadata)
+		switch {
+		case errors.Is(err, store.ErrKeyNotFound), errors.Is(err, store.ErrKeyDestroyed):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("subject data erased"))
+	}
+}
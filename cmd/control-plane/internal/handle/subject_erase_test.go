@@ -0,0 +1,113 @@
+package handle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSubjectErase(t *testing.T) {
+	tests := []struct {
+		name           string
+		tenant         string
+		requestID      string
+		correlationID  string
+		setupMock      func(m *mocks.MockSubjectEraseService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "success - returns 200",
+			requestID:     "req-123",
+			correlationID: "corr-456",
+			setupMock: func(m *mocks.MockSubjectEraseService) {
+				m.EXPECT().Erase(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "subject data erased",
+		},
+		{
+			name:          "success - erases the subject of the caller tenant",
+			tenant:        "acme",
+			requestID:     "req-123",
+			correlationID: "corr-456",
+			setupMock: func(m *mocks.MockSubjectEraseService) {
+				m.EXPECT().Erase(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, metadata audit.MetadataAudit) error {
+					if metadata.Tenant != "acme" || metadata.Key != "user:123" {
+						t.Errorf("expected subject user:123 of acme, got %s of %q", metadata.Key, metadata.Tenant)
+					}
+					return nil
+				})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "error - missing request_id returns 400",
+			correlationID:  "corr-456",
+			setupMock:      func(m *mocks.MockSubjectEraseService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:          "error - unknown subject returns 404",
+			requestID:     "req-123",
+			correlationID: "corr-456",
+			setupMock: func(m *mocks.MockSubjectEraseService) {
+				m.EXPECT().Erase(gomock.Any(), gomock.Any()).Return(store.ErrKeyNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:          "error - erase fails returns 500",
+			requestID:     "req-123",
+			correlationID: "corr-456",
+			setupMock: func(m *mocks.MockSubjectEraseService) {
+				m.EXPECT().Erase(gomock.Any(), gomock.Any()).Return(errors.New("disk failure"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockSubjectEraseService(ctrl)
+			tt.setupMock(mockService)
+
+			pattern, handler := SubjectErase(mockService)
+			mux := http.NewServeMux()
+			mux.HandleFunc(pattern, handler)
+
+			req := httptest.NewRequest(http.MethodDelete, "/subjects/user:123", nil)
+			if tt.tenant != "" {
+				req = req.WithContext(ctxkey.SetTenant(req.Context(), tt.tenant))
+			}
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+			if tt.correlationID != "" {
+				req.Header.Set("X-Correlation-ID", tt.correlationID)
+			}
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedBody != "" && rr.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
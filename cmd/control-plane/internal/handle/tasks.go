@@ -0,0 +1,22 @@
+package handle
+
+//go:generate mockgen -source=tasks.go -destination=mocks/mock_tasks.go -package=mocks
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IsaacDSC/auditory/internal/scheduler"
+)
+
+type TaskLister interface {
+	Tasks() []scheduler.TaskStatus
+}
+
+func ListTasks(taskLister TaskLister) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /tasks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(taskLister.Tasks())
+	}
+}
@@ -0,0 +1,99 @@
+package handle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/scheduler"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListTasks(t *testing.T) {
+	nextRun := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	lastRun := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		setupMock     func(m *mocks.MockTaskLister)
+		expectedTasks []scheduler.TaskStatus
+	}{
+		{
+			name: "success - returns next and last run of every task",
+			setupMock: func(m *mocks.MockTaskLister) {
+				m.EXPECT().Tasks().Return([]scheduler.TaskStatus{
+					{
+						Name:     "store",
+						Schedule: "0 0 * * *",
+						NextRun:  nextRun,
+						RunState: scheduler.RunState{
+							LastRun:      lastRun,
+							LastOutcome:  scheduler.OutcomeError,
+							LastError:    "bucket unavailable",
+							LastDuration: "1s",
+						},
+					},
+				})
+			},
+			expectedTasks: []scheduler.TaskStatus{
+				{
+					Name:     "store",
+					Schedule: "0 0 * * *",
+					NextRun:  nextRun,
+					RunState: scheduler.RunState{
+						LastRun:      lastRun,
+						LastOutcome:  scheduler.OutcomeError,
+						LastError:    "bucket unavailable",
+						LastDuration: "1s",
+					},
+				},
+			},
+		},
+		{
+			name: "success - returns empty list without tasks",
+			setupMock: func(m *mocks.MockTaskLister) {
+				m.EXPECT().Tasks().Return([]scheduler.TaskStatus{})
+			},
+			expectedTasks: []scheduler.TaskStatus{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLister := mocks.NewMockTaskLister(ctrl)
+			tt.setupMock(mockLister)
+
+			_, handler := ListTasks(mockLister)
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+
+			var got []scheduler.TaskStatus
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if len(got) != len(tt.expectedTasks) {
+				t.Fatalf("expected %d tasks, got %d", len(tt.expectedTasks), len(got))
+			}
+			for i := range got {
+				want := tt.expectedTasks[i]
+				if got[i].Name != want.Name || !got[i].NextRun.Equal(want.NextRun) || !got[i].LastRun.Equal(want.LastRun) ||
+					got[i].LastOutcome != want.LastOutcome || got[i].LastError != want.LastError {
+					t.Errorf("expected task %+v, got %+v", want, got[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package handle
+
+//go:generate mockgen -source=wal.go -destination=mocks/mock_wal.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"github.com/IsaacDSC/auditory/internal/wal"
+	"github.com/hashicorp/raft"
+)
+
+type WALApplier interface {
+	ApplyAudit(ctx context.Context, input audit.DataAudit) error
+}
+
+// WALAudit appends an audit a follower forwarded to this node, the leader. It
+// answers once a majority persisted it; a node that lost the leadership in the
+// meantime answers 503 so the follower fails the request instead of looping.
+func WALAudit(applier WALApplier, secret string) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "POST /wal/audits", func(w http.ResponseWriter, r *http.Request) {
+		if !wal.Authorized(r, secret) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var input audit.DataAudit
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := telemetry.Extract(r.Context(), r.Header)
+		err := applier.ApplyAudit(ctx, input)
+		switch {
+		case errors.Is(err, raft.ErrNotLeader):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,88 @@
+package handle
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/wal"
+	"github.com/hashicorp/raft"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWALAudit(t *testing.T) {
+	tests := []struct {
+		name           string
+		secret         string
+		body           string
+		setupMock      func(m *mocks.MockWALApplier)
+		expectedStatus int
+	}{
+		{
+			name:   "success - appends forwarded audit",
+			secret: "s3cret",
+			body:   `{"metadata":{"key":"user:123","event_name":"user.created"},"data":{}}`,
+			setupMock: func(m *mocks.MockWALApplier) {
+				m.EXPECT().ApplyAudit(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "error - wrong secret returns 401",
+			secret:         "guess",
+			body:           `{}`,
+			setupMock:      func(m *mocks.MockWALApplier) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "error - invalid body returns 400",
+			secret:         "s3cret",
+			body:           `{`,
+			setupMock:      func(m *mocks.MockWALApplier) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "error - lost leadership returns 503",
+			secret: "s3cret",
+			body:   `{"metadata":{"key":"user:123"}}`,
+			setupMock: func(m *mocks.MockWALApplier) {
+				m.EXPECT().ApplyAudit(gomock.Any(), gomock.Any()).Return(raft.ErrNotLeader)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "error - no quorum returns 500",
+			secret: "s3cret",
+			body:   `{"metadata":{"key":"user:123"}}`,
+			setupMock: func(m *mocks.MockWALApplier) {
+				m.EXPECT().ApplyAudit(gomock.Any(), gomock.Any()).Return(errors.New("wal entry not committed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockApplier := mocks.NewMockWALApplier(ctrl)
+			tt.setupMock(mockApplier)
+
+			_, handler := WALAudit(mockApplier, "s3cret")
+
+			req := httptest.NewRequest(http.MethodPost, "/wal/audits", strings.NewReader(tt.body))
+			req.Header.Set(wal.SecretHeader, tt.secret)
+			rr := httptest.NewRecorder()
+
+			handler(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package handle
+
+//go:generate mockgen -source=webhooks.go -destination=mocks/mock_webhooks.go -package=mocks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/auditory/internal/webhook"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+const maskedSecret = "********"
+
+type WebhookService interface {
+	Create(sub webhook.Subscription) (webhook.Subscription, error)
+	Get(id string) (webhook.Subscription, error)
+	List() ([]webhook.Subscription, error)
+	Update(id string, sub webhook.Subscription) (webhook.Subscription, error)
+	Delete(id string) error
+}
+
+type DeadLetterService interface {
+	List() ([]webhook.DeadLetter, error)
+}
+
+// CreateWebhook returns the generated secret once; every other endpoint masks it.
+// The subscription belongs to the caller's tenant, whatever the body says.
+func CreateWebhook(webhookService WebhookService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "POST /webhooks", func(w http.ResponseWriter, r *http.Request) {
+		var input webhook.Subscription
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input.Tenant, _ = ctxkey.Tenant(r.Context())
+
+		sub, err := webhookService.Create(input)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, sub)
+	}
+}
+
+func ListWebhooks(webhookService WebhookService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /webhooks", func(w http.ResponseWriter, r *http.Request) {
+		subs, err := webhookService.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		tenant, _ := ctxkey.Tenant(r.Context())
+		output := make([]webhook.Subscription, 0, len(subs))
+		for _, sub := range subs {
+			if sub.Tenant == tenant {
+				sub.Secret = maskedSecret
+				output = append(output, sub)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, output)
+	}
+}
+
+func GetWebhook(webhookService WebhookService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		sub, err := tenantSubscription(webhookService, r)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+
+		sub.Secret = maskedSecret
+		writeJSON(w, http.StatusOK, sub)
+	}
+}
+
+func UpdateWebhook(webhookService WebhookService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "PUT /webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var input webhook.Subscription
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if _, err := tenantSubscription(webhookService, r); err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+
+		sub, err := webhookService.Update(r.PathValue("id"), input)
+		if err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+
+		sub.Secret = maskedSecret
+		writeJSON(w, http.StatusOK, sub)
+	}
+}
+
+func DeleteWebhook(webhookService WebhookService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "DELETE /webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := tenantSubscription(webhookService, r); err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+
+		if err := webhookService.Delete(r.PathValue("id")); err != nil {
+			writeWebhookError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func ListDeadLetters(deadLetterService DeadLetterService) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /webhooks/dead-letters", func(w http.ResponseWriter, r *http.Request) {
+		letters, err := deadLetterService.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		tenant, _ := ctxkey.Tenant(r.Context())
+		output := make([]webhook.DeadLetter, 0, len(letters))
+		for _, letter := range letters {
+			if letter.Audit.Metadata.Tenant == tenant {
+				output = append(output, letter)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, output)
+	}
+}
+
+// tenantSubscription is the subscription of the path id when it belongs to the
+// caller's tenant; the subscriptions of other tenants are not found.
+func tenantSubscription(webhookService WebhookService, r *http.Request) (webhook.Subscription, error) {
+	sub, err := webhookService.Get(r.PathValue("id"))
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+
+	if tenant, _ := ctxkey.Tenant(r.Context()); sub.Tenant != tenant {
+		return webhook.Subscription{}, webhook.ErrSubscriptionNotFound
+	}
+
+	return sub, nil
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrSubscriptionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, webhook.ErrInvalidSubscription):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
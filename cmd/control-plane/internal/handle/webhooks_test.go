@@ -0,0 +1,260 @@
+package handle
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/webhook"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhooks(t *testing.T) {
+	sub := webhook.Subscription{
+		ID:     "abc",
+		URL:    "https://hooks.example.com/audit",
+		Secret: "top-secret",
+		Rule: webhook.Rule{Conditions: []webhook.Condition{
+			{Field: "event_name", Op: webhook.OpEq, Value: "user.deleted"},
+		}},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		tenant         string
+		setupMock      func(m *mocks.MockWebhookService)
+		expectedStatus int
+		expectedSecret string
+		expectedIDs    []string
+	}{
+		{
+			name:   "success - create returns 201 with secret",
+			method: http.MethodPost,
+			url:    "/webhooks",
+			body:   `{"url":"https://hooks.example.com/audit","rule":{"conditions":[{"field":"event_name","op":"eq","value":"user.deleted"}]}}`,
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Create(gomock.Any()).Return(sub, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedSecret: "top-secret",
+		},
+		{
+			name:           "error - create with invalid body returns 400",
+			method:         http.MethodPost,
+			url:            "/webhooks",
+			body:           `{`,
+			setupMock:      func(m *mocks.MockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "error - create with invalid rule returns 400",
+			method: http.MethodPost,
+			url:    "/webhooks",
+			body:   `{"url":"https://hooks.example.com/audit","rule":{}}`,
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Create(gomock.Any()).Return(webhook.Subscription{}, webhook.ErrInvalidSubscription)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "success - get masks secret",
+			method: http.MethodGet,
+			url:    "/webhooks/abc",
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Get("abc").Return(sub, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedSecret: maskedSecret,
+		},
+		{
+			name:   "error - get unknown returns 404",
+			method: http.MethodGet,
+			url:    "/webhooks/missing",
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Get("missing").Return(webhook.Subscription{}, webhook.ErrSubscriptionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "success - update masks secret",
+			method: http.MethodPut,
+			url:    "/webhooks/abc",
+			body:   `{"url":"https://hooks.example.com/v2","rule":{"conditions":[{"field":"key","op":"exists"}]}}`,
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Get("abc").Return(sub, nil)
+				m.EXPECT().Update("abc", gomock.Any()).Return(sub, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedSecret: maskedSecret,
+		},
+		{
+			name:   "success - delete returns 204",
+			method: http.MethodDelete,
+			url:    "/webhooks/abc",
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Get("abc").Return(sub, nil)
+				m.EXPECT().Delete("abc").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "success - create belongs to the caller tenant",
+			method: http.MethodPost,
+			url:    "/webhooks",
+			tenant: "acme",
+			body:   `{"tenant":"globex","url":"https://hooks.example.com/audit","rule":{"conditions":[{"field":"event_name","op":"eq","value":"user.deleted"}]}}`,
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Create(gomock.Any()).DoAndReturn(func(input webhook.Subscription) (webhook.Subscription, error) {
+					if input.Tenant != "acme" {
+						t.Errorf("expected the subscription of acme, got %q", input.Tenant)
+					}
+					return input, nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "error - get of another tenant returns 404",
+			method: http.MethodGet,
+			url:    "/webhooks/abc",
+			tenant: "acme",
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Get("abc").Return(sub, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "error - update of another tenant returns 404",
+			method: http.MethodPut,
+			url:    "/webhooks/abc",
+			tenant: "acme",
+			body:   `{"url":"https://hooks.example.com/v2","rule":{"conditions":[{"field":"key","op":"exists"}]}}`,
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Get("abc").Return(sub, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "error - delete of another tenant returns 404",
+			method: http.MethodDelete,
+			url:    "/webhooks/abc",
+			tenant: "acme",
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().Get("abc").Return(sub, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "success - list returns the caller tenant subscriptions",
+			method: http.MethodGet,
+			url:    "/webhooks",
+			tenant: "acme",
+			setupMock: func(m *mocks.MockWebhookService) {
+				acme := sub
+				acme.ID, acme.Tenant = "def", "acme"
+				m.EXPECT().List().Return([]webhook.Subscription{sub, acme}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"def"},
+		},
+		{
+			name:   "error - list fails returns 500",
+			method: http.MethodGet,
+			url:    "/webhooks",
+			setupMock: func(m *mocks.MockWebhookService) {
+				m.EXPECT().List().Return(nil, errors.New("disk failure"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWebhookService(ctrl)
+			tt.setupMock(mockService)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc(CreateWebhook(mockService))
+			mux.HandleFunc(ListWebhooks(mockService))
+			mux.HandleFunc(GetWebhook(mockService))
+			mux.HandleFunc(UpdateWebhook(mockService))
+			mux.HandleFunc(DeleteWebhook(mockService))
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.tenant != "" {
+				req = req.WithContext(ctxkey.SetTenant(req.Context(), tt.tenant))
+			}
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedIDs != nil {
+				var got []webhook.Subscription
+				if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(got) != len(tt.expectedIDs) || got[0].ID != tt.expectedIDs[0] {
+					t.Errorf("expected subscriptions %v, got %+v", tt.expectedIDs, got)
+				}
+			}
+
+			if tt.expectedSecret != "" {
+				var got webhook.Subscription
+				if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if got.Secret != tt.expectedSecret {
+					t.Errorf("expected secret %q, got %q", tt.expectedSecret, got.Secret)
+				}
+			}
+		})
+	}
+}
+
+func TestListDeadLetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockDeadLetterService(ctrl)
+	mockService.EXPECT().List().Return([]webhook.DeadLetter{
+		{DeliveryID: "d1", Attempts: 5},
+		{DeliveryID: "d2", Attempts: 5, Audit: audit.DataAudit{Metadata: audit.MetadataAudit{Tenant: "acme"}}},
+	}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(ListDeadLetters(mockService))
+	mux.HandleFunc(GetWebhook(mocks.NewMockWebhookService(ctrl)))
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/dead-letters", nil)
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var got []webhook.DeadLetter
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got) != 1 || got[0].DeliveryID != "d1" {
+		t.Errorf("unexpected dead letters: %+v", got)
+	}
+}
@@ -0,0 +1,235 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle"
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/internal/stream"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"github.com/IsaacDSC/auditory/pkg/auditpb"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	requestIDMetadata     = "x-request-id"
+	correlationIDMetadata = "x-correlation-id"
+)
+
+type Broker interface {
+	Subscribe(filter stream.Filter, lastEventID uint64) (*stream.Subscription, []stream.Event)
+	Unsubscribe(sub *stream.Subscription)
+}
+
+// AuditServer implements auditpb.AuditService on top of the same ingestion
+// path as POST /audit and the same broker as GET /audits/stream.
+type AuditServer struct {
+	auditpb.UnimplementedAuditServiceServer
+
+	auditStoreService handle.AuditStoreService
+	broker            Broker
+}
+
+func NewAuditServer(auditStoreService handle.AuditStoreService, broker Broker) *AuditServer {
+	return &AuditServer{
+		auditStoreService: auditStoreService,
+		broker:            broker,
+	}
+}
+
+func (as *AuditServer) Save(ctx context.Context, req *auditpb.SaveRequest) (*auditpb.SaveResponse, error) {
+	idempotencyKey, err := as.save(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditpb.SaveResponse{IdempotencyKey: idempotencyKey}, nil
+}
+
+// SaveStream stops at the first audit that fails; the audits received before
+// it stay saved and the error says how many there were.
+func (as *AuditServer) SaveStream(srv grpc.ClientStreamingServer[auditpb.SaveRequest, auditpb.SaveStreamResponse]) error {
+	var resp auditpb.SaveStreamResponse
+	for {
+		req, err := srv.Recv()
+		if errors.Is(err, io.EOF) {
+			return srv.SendAndClose(&resp)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = as.save(srv.Context(), req)
+		switch {
+		case err == nil:
+			resp.Saved++
+		case status.Code(err) == codes.AlreadyExists:
+			resp.Duplicates++
+		default:
+			st := status.Convert(err)
+			return status.Errorf(st.Code(), "audit %d: %s", resp.Saved+resp.Duplicates, st.Message())
+		}
+	}
+}
+
+func (as *AuditServer) Tail(req *auditpb.TailRequest, srv grpc.ServerStreamingServer[auditpb.TailResponse]) error {
+	tenant, _ := ctxkey.Tenant(srv.Context())
+	filter := stream.Filter{Tenant: tenant, Key: req.GetKey(), EventName: req.GetEventName()}
+	sub, backlog := as.broker.Subscribe(filter, req.GetLastEventId())
+	defer as.broker.Unsubscribe(sub)
+
+	if sub.Gap() {
+		if err := srv.Send(&auditpb.TailResponse{Gap: true}); err != nil {
+			return err
+		}
+	}
+	for _, event := range backlog {
+		if err := sendEvent(srv, event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case event, ok := <-sub.C:
+			if !ok {
+				if sub.Lagged() {
+					return status.Error(codes.ResourceExhausted, "subscriber lagged behind; resume with last_event_id")
+				}
+				return nil
+			}
+			if err := sendEvent(srv, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (as *AuditServer) save(ctx context.Context, req *auditpb.SaveRequest) (string, error) {
+	input := toDataAudit(req.GetAudit())
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = telemetry.ExtractCarrier(ctx, metadataCarrier(md))
+		if values := md.Get(requestIDMetadata); len(values) > 0 && values[0] != "" {
+			input.Metadata.RequestID = values[0]
+		}
+		if values := md.Get(correlationIDMetadata); len(values) > 0 && values[0] != "" {
+			input.Metadata.CorrelationID = values[0]
+		}
+	}
+
+	idempotencyKey, err := handle.SaveAudit(ctx, as.auditStoreService, input)
+	switch {
+	case errors.Is(err, handle.ErrInvalidAudit):
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, backup.ErrIdempotencyKeyAlreadyExists):
+		return "", status.Error(codes.AlreadyExists, err.Error())
+	case store.Throttled(err):
+		return "", status.Error(codes.ResourceExhausted, err.Error())
+	case err != nil:
+		return "", status.Error(codes.Internal, err.Error())
+	}
+
+	return idempotencyKey, nil
+}
+
+func sendEvent(srv grpc.ServerStreamingServer[auditpb.TailResponse], event stream.Event) error {
+	msg, err := toProtoAudit(event.Audit)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return srv.Send(&auditpb.TailResponse{Id: event.ID, Audit: msg})
+}
+
+func toDataAudit(msg *auditpb.Audit) audit.DataAudit {
+	meta := msg.GetMetadata()
+
+	input := audit.DataAudit{
+		Metadata: audit.MetadataAudit{
+			Key:           meta.GetKey(),
+			EventName:     meta.GetEventName(),
+			RequestID:     meta.GetRequestId(),
+			CorrelationID: meta.GetCorrelationId(),
+		},
+	}
+	if meta.GetEventAt() != nil {
+		input.Metadata.EventAt = meta.GetEventAt().AsTime()
+	}
+	if msg.GetData() != nil {
+		input.Data = msg.GetData().AsInterface()
+	}
+
+	return input
+}
+
+func toProtoAudit(input audit.DataAudit) (*auditpb.Audit, error) {
+	// typed payloads (e.g. audit.HttpAudit) go through JSON to become
+	// the plain maps structpb accepts
+	payload, err := json.Marshal(input.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var plain any
+	if err := json.Unmarshal(payload, &plain); err != nil {
+		return nil, err
+	}
+
+	data, err := structpb.NewValue(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &auditpb.Audit{
+		Metadata: &auditpb.Metadata{
+			Key:           input.Metadata.Key,
+			EventName:     input.Metadata.EventName,
+			RequestId:     input.Metadata.RequestID,
+			CorrelationId: input.Metadata.CorrelationID,
+			TraceId:       input.Metadata.TraceID,
+			SpanId:        input.Metadata.SpanID,
+		},
+		Data: data,
+	}
+	if !input.Metadata.EventAt.IsZero() {
+		msg.Metadata.EventAt = timestamppb.New(input.Metadata.EventAt)
+	}
+
+	return msg, nil
+}
+
+// metadataCarrier lets the W3C propagator read traceparent from gRPC metadata,
+// whose keys are already lower case.
+type metadataCarrier metadata.MD
+
+func (mc metadataCarrier) Get(key string) string {
+	if values := metadata.MD(mc).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (mc metadataCarrier) Set(key, value string) {
+	metadata.MD(mc).Set(key, value)
+}
+
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc))
+	for key := range mc {
+		keys = append(keys, key)
+	}
+	return keys
+}
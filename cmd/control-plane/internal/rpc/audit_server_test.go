@@ -0,0 +1,295 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle/mocks"
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/auth"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/stream"
+	"github.com/IsaacDSC/auditory/pkg/auditpb"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newTestClient(t *testing.T, svc *mocks.MockAuditStoreService, broker Broker, tokens ...string) auditpb.AuditServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	resolver := auth.NewTenantResolver([]string{"acme", "globex"}, map[string]string{"acme-secret": "acme"})
+	server := NewServer(auth.NewTokenAuthenticator(tokens), resolver, NewAuditServer(svc, broker))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return auditpb.NewAuditServiceClient(conn)
+}
+
+func validRequest() *auditpb.SaveRequest {
+	data, _ := structpb.NewValue(map[string]any{"name": "alice"})
+	return &auditpb.SaveRequest{Audit: &auditpb.Audit{
+		Metadata: &auditpb.Metadata{Key: "user:42", EventName: "user.created", RequestId: "req-1", CorrelationId: "corr-1"},
+		Data:     data,
+	}}
+}
+
+func TestAuditServer_Save(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *auditpb.SaveRequest
+		md           metadata.MD
+		tokens       []string
+		setupMock    func(m *mocks.MockAuditStoreService)
+		expectedCode codes.Code
+	}{
+		{
+			name: "success - saves and returns idempotency key",
+			req:  validRequest(),
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) (string, error) {
+					if input.Data.(map[string]any)["name"] != "alice" {
+						t.Errorf("unexpected data %+v", input.Data)
+					}
+					return "user:42-user.created-req-1-corr-1", nil
+				})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "success - metadata overrides ids",
+			req:  validRequest(),
+			md:   metadata.Pairs("x-request-id", "req-md", "x-correlation-id", "corr-md"),
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) (string, error) {
+					if input.Metadata.RequestID != "req-md" || input.Metadata.CorrelationID != "corr-md" {
+						t.Errorf("expected ids from metadata, got %+v", input.Metadata)
+					}
+					return "key", nil
+				})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "error - missing metadata is invalid",
+			req:          &auditpb.SaveRequest{Audit: &auditpb.Audit{Metadata: &auditpb.Metadata{Key: "user:42"}}},
+			setupMock:    func(m *mocks.MockAuditStoreService) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "error - duplicate returns already exists",
+			req:  validRequest(),
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", backup.ErrIdempotencyKeyAlreadyExists)
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "error - store failure returns internal",
+			req:  validRequest(),
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", errors.New("disk full"))
+			},
+			expectedCode: codes.Internal,
+		},
+		{
+			name:         "error - missing token is unauthenticated",
+			req:          validRequest(),
+			tokens:       []string{"secret"},
+			setupMock:    func(m *mocks.MockAuditStoreService) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:   "success - valid token",
+			req:    validRequest(),
+			md:     metadata.Pairs("authorization", "Bearer secret"),
+			tokens: []string{"secret"},
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).Return("key", nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "success - tenant from metadata",
+			req:  validRequest(),
+			md:   metadata.Pairs("x-tenant-id", "globex"),
+			setupMock: func(m *mocks.MockAuditStoreService) {
+				m.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) (string, error) {
+					if input.Metadata.Tenant != "globex" {
+						t.Errorf("expected tenant globex, got %q", input.Metadata.Tenant)
+					}
+					return "key", nil
+				})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "error - unknown tenant is invalid",
+			req:          validRequest(),
+			md:           metadata.Pairs("x-tenant-id", "initech"),
+			setupMock:    func(m *mocks.MockAuditStoreService) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "error - token bound to another tenant is denied",
+			req:          validRequest(),
+			md:           metadata.Pairs("authorization", "Bearer acme-secret", "x-tenant-id", "globex"),
+			tokens:       []string{"acme-secret"},
+			setupMock:    func(m *mocks.MockAuditStoreService) {},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuditStoreService(ctrl)
+			tt.setupMock(mockService)
+
+			client := newTestClient(t, mockService, stream.NewBroker(0, 0), tt.tokens...)
+
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewOutgoingContext(ctx, tt.md)
+			}
+
+			_, err := client.Save(ctx, tt.req)
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Errorf("expected code %s, got %s (%v)", tt.expectedCode, code, err)
+			}
+		})
+	}
+}
+
+func TestAuditServer_SaveStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAuditStoreService(ctrl)
+	gomock.InOrder(
+		mockService.EXPECT().Save(gomock.Any(), gomock.Any()).Return("key", nil),
+		mockService.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", backup.ErrIdempotencyKeyAlreadyExists),
+		mockService.EXPECT().Save(gomock.Any(), gomock.Any()).Return("key", nil),
+	)
+
+	client := newTestClient(t, mockService, stream.NewBroker(0, 0))
+
+	srv, err := client.SaveStream(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	for range 3 {
+		if err := srv.Send(validRequest()); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+	}
+
+	resp, err := srv.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetSaved() != 2 || resp.GetDuplicates() != 1 {
+		t.Errorf("expected 2 saved and 1 duplicate, got %+v", resp)
+	}
+
+	// an invalid audit aborts the stream
+	srv, _ = client.SaveStream(context.Background())
+	_ = srv.Send(&auditpb.SaveRequest{Audit: &auditpb.Audit{}})
+	if _, err := srv.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestAuditServer_Tail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	broker := stream.NewBroker(0, 0)
+	seen := broker.Publish(audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.seen"}})
+	broker.Publish(audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.created"}})
+	broker.Publish(audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:7", EventName: "user.created"}})
+	broker.Publish(audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.invited", Tenant: "acme"}})
+	broker.Publish(audit.DataAudit{
+		Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.updated", EventAt: time.Now()},
+		Data:     map[string]any{"name": "bob"},
+	})
+
+	client := newTestClient(t, mocks.NewMockAuditStoreService(ctrl), broker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	srv, err := client.Tail(ctx, &auditpb.TailRequest{Key: "user:42", LastEventId: seen.ID})
+	if err != nil {
+		t.Fatalf("failed to tail: %v", err)
+	}
+
+	// backlog after last_event_id, filtered by key and tenant
+	for _, expected := range []string{"user.created", "user.updated"} {
+		resp, err := srv.Recv()
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+		if resp.GetAudit().GetMetadata().GetEventName() != expected {
+			t.Errorf("expected %s, got %s", expected, resp.GetAudit().GetMetadata().GetEventName())
+		}
+	}
+
+	// live events keep flowing
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		broker.Publish(audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.deleted"}})
+	}()
+
+	resp, err := srv.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive live event: %v", err)
+	}
+	if resp.GetAudit().GetMetadata().GetEventName() != "user.deleted" {
+		t.Errorf("expected user.deleted, got %s", resp.GetAudit().GetMetadata().GetEventName())
+	}
+
+	// a tenant only tails its own audits
+	tenantSrv, err := client.Tail(metadata.AppendToOutgoingContext(ctx, "x-tenant-id", "acme"), &auditpb.TailRequest{Key: "user:42", LastEventId: seen.ID})
+	if err != nil {
+		t.Fatalf("failed to tail: %v", err)
+	}
+	resp, err = tenantSrv.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+	if resp.GetAudit().GetMetadata().GetEventName() != "user.invited" {
+		t.Errorf("expected user.invited, got %s", resp.GetAudit().GetMetadata().GetEventName())
+	}
+
+	// a resume point from before a restart is announced with a gap
+	gapSrv, err := client.Tail(ctx, &auditpb.TailRequest{Key: "user:42", LastEventId: 100})
+	if err != nil {
+		t.Fatalf("failed to tail: %v", err)
+	}
+	if resp, err = gapSrv.Recv(); err != nil || !resp.GetGap() || resp.GetAudit() != nil {
+		t.Errorf("expected a gap message, got %+v %v", resp, err)
+	}
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle"
+	"github.com/IsaacDSC/auditory/internal/auth"
+	"github.com/IsaacDSC/auditory/pkg/auditpb"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tenantMetadata = "x-tenant-id"
+
+// NewServer registers the audit service behind the same token check and
+// tenant resolution as the HTTP ingestion route; credentials travel in the
+// "authorization" metadata and the tenant in "x-tenant-id".
+func NewServer(authenticator handle.Authenticator, resolver handle.TenantResolver, auditServer *AuditServer) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+			if err := authenticate(ctx, authenticator); err != nil {
+				return nil, err
+			}
+			ctx, err := resolveTenant(ctx, resolver)
+			if err != nil {
+				return nil, err
+			}
+			return next(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+			if err := authenticate(ss.Context(), authenticator); err != nil {
+				return err
+			}
+			ctx, err := resolveTenant(ss.Context(), resolver)
+			if err != nil {
+				return err
+			}
+			return next(srv, &tenantStream{ServerStream: ss, ctx: ctx})
+		}),
+	)
+
+	auditpb.RegisterAuditServiceServer(server, auditServer)
+	return server
+}
+
+func authenticate(ctx context.Context, authenticator handle.Authenticator) error {
+	if err := authenticator.Authenticate(incoming(ctx, "authorization")); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return nil
+}
+
+func resolveTenant(ctx context.Context, resolver handle.TenantResolver) (context.Context, error) {
+	tenant, err := resolver.Resolve(incoming(ctx, "authorization"), incoming(ctx, tenantMetadata))
+	switch {
+	case errors.Is(err, auth.ErrTenantMismatch):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return ctxkey.SetTenant(ctx, tenant), nil
+}
+
+func incoming(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// tenantStream hands the handler the context carrying the tenant.
+type tenantStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ts *tenantStream) Context() context.Context {
+	return ts.ctx
+}
@@ -0,0 +1,29 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/scheduler"
+)
+
+type Rebalancer interface {
+	Rebalance(ctx context.Context) (remaining int, err error)
+}
+
+// AfterHandoff runs job once every local key owned by another node has been
+// handed off, so a node never archives a partial file over its owner's.
+func AfterHandoff(rebalancer Rebalancer, job scheduler.Job) scheduler.Job {
+	run := job.Run
+	job.Run = func(ctx context.Context) error {
+		remaining, err := rebalancer.Rebalance(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to hand off keys: %w", err)
+		}
+		if remaining > 0 {
+			return fmt.Errorf("%d keys owned by other nodes could not be handed off", remaining)
+		}
+		return run(ctx)
+	}
+	return job
+}
@@ -2,27 +2,17 @@ package tasks
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"time"
+
+	"github.com/IsaacDSC/auditory/internal/scheduler"
 )
 
 type MemIdempotency interface {
 	Reset()
 }
 
-func IdempotencyClear(ctx context.Context, period time.Duration, idempotencyClearService MemIdempotency) {
-	ticker := time.NewTicker(period)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("idempotency clear task stopped")
-			return
-		case <-ticker.C:
-			fmt.Println("idempotency clear task running")
-			idempotencyClearService.Reset()
-		}
-	}
+func IdempotencyClear(schedule string, idempotencyClearService MemIdempotency) scheduler.Job {
+	return scheduler.Job{Name: "idempotency_clear", Schedule: schedule, Run: func(ctx context.Context) error {
+		idempotencyClearService.Reset()
+		return nil
+	}}
 }
@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/IsaacDSC/auditory/internal/scheduler"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+type Leadership interface {
+	Leading() (token uint64, ok bool)
+}
+
+// LeaderOnly skips job on followers and runs it on the leader with the lease
+// fencing token in the context, so archive writes record it.
+func LeaderOnly(leadership Leadership, job scheduler.Job) scheduler.Job {
+	run := job.Run
+	job.Run = func(ctx context.Context) error {
+		token, ok := leadership.Leading()
+		if !ok {
+			return scheduler.ErrSkipped
+		}
+		return run(ctxkey.SetFencingToken(ctx, token))
+	}
+	return job
+}
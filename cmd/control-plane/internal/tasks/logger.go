@@ -0,0 +1,5 @@
+package tasks
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("tasks")
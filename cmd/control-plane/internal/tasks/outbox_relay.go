@@ -0,0 +1,31 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/health"
+)
+
+type RelayService interface {
+	Flush(ctx context.Context) error
+}
+
+func OutboxRelay(ctx context.Context, period time.Duration, relayService RelayService) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	health.Tasks.Register("outbox_relay", period)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.InfoContext(ctx, "outbox relay task stopped")
+			return
+		case <-ticker.C:
+			health.Tasks.Beat("outbox_relay")
+			if err := relayService.Flush(ctx); err != nil {
+				logger.ErrorContext(ctx, "failed to relay outbox", "error", err)
+			}
+		}
+	}
+}
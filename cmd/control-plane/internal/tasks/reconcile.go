@@ -0,0 +1,15 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/IsaacDSC/auditory/internal/scheduler"
+)
+
+type ReconcileService interface {
+	Reconcile(ctx context.Context) error
+}
+
+func Reconcile(schedule string, reconcileService ReconcileService) scheduler.Job {
+	return scheduler.Job{Name: "reconcile", Schedule: schedule, Run: reconcileService.Reconcile}
+}
@@ -0,0 +1,29 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/scheduler"
+)
+
+type Archiver interface {
+	Archived(ctx context.Context) error
+}
+
+// RecordArchival tells the write-ahead log once job archived everything it
+// could, so every replica compacts its log. A failed job leaves the log
+// untouched: the entries of what was not archived are all that is left.
+func RecordArchival(archiver Archiver, job scheduler.Job) scheduler.Job {
+	run := job.Run
+	job.Run = func(ctx context.Context) error {
+		if err := run(ctx); err != nil {
+			return err
+		}
+		if err := archiver.Archived(ctx); err != nil {
+			return fmt.Errorf("failed to record archival in the wal: %w", err)
+		}
+		return nil
+	}
+	return job
+}
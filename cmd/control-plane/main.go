@@ -3,18 +3,41 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"maps"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/handle"
+	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/rpc"
 	"github.com/IsaacDSC/auditory/cmd/control-plane/internal/tasks"
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/auth"
 	"github.com/IsaacDSC/auditory/internal/backup"
 	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/cluster"
+	"github.com/IsaacDSC/auditory/internal/health"
+	"github.com/IsaacDSC/auditory/internal/leader"
+	"github.com/IsaacDSC/auditory/internal/logging"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/scheduler"
+	"github.com/IsaacDSC/auditory/internal/search"
+	"github.com/IsaacDSC/auditory/internal/shred"
+	"github.com/IsaacDSC/auditory/internal/sink"
+	"github.com/IsaacDSC/auditory/internal/source"
 	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/internal/stream"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"github.com/IsaacDSC/auditory/internal/wal"
+	"github.com/IsaacDSC/auditory/internal/webhook"
 )
 
 func init() {
@@ -23,40 +46,417 @@ func init() {
 
 func main() {
 	conf := cfg.GetConfig()
+	if err := logging.Setup(conf.LogConfig); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	bucketStore, err := store.NewS3BucketStore(ctx, store.S3Config{
-		Bucket:          conf.BucketConfig.Name,
-		Endpoint:        conf.BucketConfig.Endpoint,
-		AccessKeyID:     conf.BucketConfig.AccessKeyID,
-		SecretAccessKey: conf.BucketConfig.SecretAccessKey,
-		Region:          conf.BucketConfig.Region,
-		UsePathStyle:    conf.BucketConfig.UsePathStyle,
-	})
+	shutdownTracing, err := telemetry.Setup(ctx, conf.TelemetryConfig)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+
+	archiveStorage, err := store.NewObjectStorage(ctx, conf)
+	if err != nil {
+		log.Fatalf("failed to create archive storage: %v", err)
+	}
+
+	dataStore, err := store.NewDataFileStore().WithDir(conf.StorageConfig.DataDir)
 	if err != nil {
-		log.Fatalf("failed to create S3 client: %v", err)
+		log.Fatalf("failed to open local store: %v", err)
+	}
+	if dataStore, err = dataStore.WithQuota(conf.QuotaConfig); err != nil {
+		log.Fatalf("failed to apply local store quota: %v", err)
 	}
 
-	dataStore := store.NewDataFileStore()
+	//each tenant keeps its audits in a directory of its own, under its own quota
+	tenants := conf.StorageConfig.Tenants
+	if len(tenants) > 0 {
+		switch {
+		case conf.WALConfig.Enabled || conf.ClusterConfig.Enabled:
+			log.Fatalf("STORAGE_TENANTS cannot run in wal or cluster mode: tenant directories are neither replicated nor handed off")
+		case conf.SQLConfig.Mode == "primary":
+			log.Fatalf("STORAGE_TENANTS cannot run with SQL_MODE=primary: audits are routed to the tenant directories")
+		case len(conf.AppConfig.APITokens) == 0 && len(conf.TenantConfig.Tokens) == 0:
+			log.Fatalf("STORAGE_TENANTS requires APP_API_TOKENS or TENANT_TOKENS: without tokens any caller acts for any tenant")
+		}
+	}
+	tenantStores, err := store.OpenTenantStores(dataStore, conf.StorageConfig, conf.QuotaConfig, conf.TenantConfig)
+	if err != nil {
+		log.Fatalf("failed to open tenant local stores: %v", err)
+	}
 	memIdempotency := store.NewMemIdempotency(conf.AppConfig.IdempotencyTTL)
-	backupService := backup.NewBackup(dataStore, bucketStore)
-	fileAuditService := backup.NewFileAudit(dataStore, memIdempotency)
+
+	checker := health.NewChecker(conf.HealthConfig.Timeout)
+	checker.AddLiveness(
+		health.Tasks.Check(),
+		health.SizeCheck("idempotency", func() (int64, error) { return int64(memIdempotency.Len()), nil }, 0),
+	)
+	checker.AddReadiness(health.DirCheck("tmp", dataStore.Dir(), conf.HealthConfig.MinFreeBytes))
+	if pinger, ok := archiveStorage.(health.Pinger); ok {
+		checker.AddReadiness(health.PingCheck("archive", pinger))
+	}
+
+	// cluster nodes each back up the keys they own
+	var backupNode string
+	if conf.ClusterConfig.Enabled {
+		backupNode = conf.ClusterConfig.NodeID
+	}
+
+	// manifests are read from every node, whatever the archive format
+	sharedArchives := store.NewArchiveStore(archiveStorage)
+	archives := store.NewTenantArchives(sharedArchives)
+	reconciliation := backup.NewReconciliation(sharedArchives, conf.TasksConfig.ReconcileDays)
+
+	archiveStore, err := newArchiveStore(conf.ArchiveConfig, archiveStorage, backupNode, 0)
+	if err != nil {
+		log.Fatalf("failed to create archive store: %v", err)
+	}
+
+	var auditStore backup.AuditStore = dataStore
+	if len(tenants) > 0 {
+		tenantRouter := store.NewTenantStore(dataStore)
+		for tenant, tenantStore := range tenantStores {
+			tenantRouter.Add(tenant, tenantStore)
+		}
+		auditStore = tenantRouter
+	}
+	var fileStore backup.FileStore = dataStore
+	var restoreStore backup.RestoreStore = dataStore
+
+	//in wal mode an audit is acknowledged once a majority of the nodes persisted it
+	var walNode *wal.Node
+	var walStore *wal.ReplicatedStore
+	if conf.WALConfig.Enabled {
+		walConf := conf.WALConfig
+		switch {
+		case walConf.NodeID == "" || walConf.Secret == "":
+			log.Fatalf("wal mode requires WAL_NODE_ID and WAL_SECRET")
+		case conf.SQLConfig.Mode == "primary":
+			log.Fatalf("wal mode replicates the local store and cannot run with SQL_MODE=primary")
+		case conf.ClusterConfig.Enabled:
+			log.Fatalf("wal mode replicates every key to every node and cannot run in cluster mode")
+		case conf.LeaderConfig.Backend != "" && conf.LeaderConfig.Backend != "none":
+			log.Fatalf("wal mode requires LEADER_BACKEND=none: the wal leader runs the archival tasks")
+		case conf.CryptoConfig.Enabled:
+			log.Fatalf("wal mode cannot run with CRYPTO_ENABLED: subject keys are neither replicated nor destroyed on the followers")
+		}
+
+		walNode, err = wal.NewNode(walConf, wal.NewFSM(dataStore))
+		if err != nil {
+			log.Fatalf("failed to start wal: %v", err)
+		}
+		go walNode.Run(ctx)
+
+		walStore = wal.NewReplicatedStore(walNode, wal.NewClient(walConf.PeerURLs, walConf.Secret, &http.Client{Timeout: walConf.ApplyTimeout}))
+		auditStore = walStore
+		replicatedFileStore := wal.NewReplicatedFileStore(walNode, dataStore)
+		fileStore = replicatedFileStore
+		restoreStore = replicatedFileStore
+		checker.AddReadiness(health.PingCheck("wal", walNode))
+	}
+
+	backupService := backup.NewBackup(fileStore, archiveStore).WithLayout(conf.ArchiveConfig.Layout)
+
+	//each tenant is archived from its own directory to its own bucket or prefix,
+	//and restored from there
+	archival := backup.NewTenantBackups(backupService)
+	restoreService := backup.NewTenantRestores(backup.NewRestore(archiveStorage, restoreStore, conf.ReplayConfig.Rate, conf.ReplayConfig.BatchSize))
+	for _, tenant := range tenants {
+		tenantStorage, err := store.NewTenantStorage(ctx, conf, tenant)
+		if err != nil {
+			log.Fatalf("failed to create archive storage of tenant %s: %v", tenant, err)
+		}
+		tenantArchive, err := newArchiveStore(conf.ArchiveConfig, tenantStorage, backupNode, conf.TenantConfig.RetentionDays[tenant])
+		if err != nil {
+			log.Fatalf("failed to create archive store of tenant %s: %v", tenant, err)
+		}
+		archival.Add(tenant, backup.NewBackup(tenantStores[tenant], tenantArchive).WithLayout(conf.ArchiveConfig.Layout))
+		restoreService.Add(tenant, backup.NewRestore(tenantStorage, tenantStores[tenant], conf.ReplayConfig.Rate, conf.ReplayConfig.BatchSize))
+		tenantArchives := store.NewArchiveStore(tenantStorage)
+		archives.Add(tenant, tenantArchives)
+		reconciliation.WithTenant(tenant, tenantArchives)
+	}
+
+	keyStore, err := store.NewFileKeyStore(conf.CryptoConfig.KeysDir)
+	if err != nil {
+		log.Fatalf("failed to create key store: %v", err)
+	}
+
+	if conf.SQLConfig.Mode != "" {
+		sqlStore, err := store.OpenSQLAuditStore(ctx, conf.SQLConfig.Dialect, conf.SQLConfig.DSN, conf.SQLConfig.BatchSize)
+		if err != nil {
+			log.Fatalf("failed to open sql audit store: %v", err)
+		}
+		defer sqlStore.Close()
+		restoreService.WithReplaySink(backup.SinkSQL, sqlStore)
+
+		switch conf.SQLConfig.Mode {
+		case "primary":
+			auditStore = sqlStore
+		case "secondary":
+			backupService.WithSinks(sqlStore)
+		default:
+			log.Fatalf("unknown sql mode: %s", conf.SQLConfig.Mode)
+		}
+	}
+
+	//shed load before the local store runs out of quota, the tenant router
+	//already sheds it per tenant
+	if len(tenants) == 0 {
+		auditStore = store.NewBackpressureStore(auditStore, dataStore)
+	}
+
+	var relay *sink.Relay
+	if conf.SinkConfig.Backend != "" {
+		brokerSink, err := sink.New(conf)
+		if err != nil {
+			log.Fatalf("failed to create broker sink: %v", err)
+		}
+		defer brokerSink.Close()
+
+		outbox, err := sink.NewOutbox(conf.SinkConfig.OutboxDir)
+		if err != nil {
+			log.Fatalf("failed to open outbox: %v", err)
+		}
+		defer outbox.Close()
+
+		auditStore = sink.NewOutboxStore(auditStore, outbox)
+		checker.AddReadiness(health.SizeCheck("outbox", outbox.Backlog, conf.HealthConfig.MaxOutboxBytes))
+		relay = sink.NewRelay(outbox, brokerSink, conf.SinkConfig.BatchSize)
+		restoreService.WithReplaySink(backup.SinkBroker, sink.NewOutboxSink(outbox))
+	}
+
+	var searchIndex *search.Index
+	if conf.SearchConfig.Enabled {
+		searchIndex, err = search.NewIndex(conf.SearchConfig.Dir)
+		if err != nil {
+			log.Fatalf("failed to open search index: %v", err)
+		}
+		defer searchIndex.Close()
+		searchIndex.WithRetention(conf.SearchConfig.RetentionDays)
+		go searchIndex.Run(ctx, time.Hour)
+
+		// indexed below the encryption so the index never holds clear data
+		// of encrypted subjects
+		auditStore = search.NewIndexedStore(auditStore, searchIndex)
+
+		if conf.SearchConfig.IndexArchives {
+			go func() {
+				indexed, err := search.IndexArchives(ctx, archiveStorage, searchIndex)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to index archives", "error", err)
+				}
+				slog.InfoContext(ctx, "indexed archived audits", "count", indexed)
+			}()
+		}
+	}
+
+	//the erasure event stays readable after the subject key is gone, every
+	//other audit is encrypted before it is stored; subscribers and webhooks
+	//sit above the encryption and see clear data
+	erasureStore := auditStore
+	if conf.CryptoConfig.Enabled {
+		auditStore = shred.NewEncryptedStore(auditStore, keyStore)
+	}
+
+	broker := stream.NewBroker(conf.StreamConfig.HistorySize, conf.StreamConfig.BufferSize)
+	auditStore = stream.NewPublishingStore(auditStore, broker)
+	erasureStore = stream.NewPublishingStore(erasureStore, broker)
+
+	subscriptionStore, err := webhook.NewFileSubscriptionStore(conf.WebhookConfig.Dir)
+	if err != nil {
+		log.Fatalf("failed to open webhook subscriptions: %v", err)
+	}
+	subscriptionStore.WithAllowedHosts(conf.WebhookConfig.AllowedHosts)
+
+	deadLetterStore, err := webhook.NewFileDeadLetterStore(conf.WebhookConfig.Dir)
+	if err != nil {
+		log.Fatalf("failed to open webhook dead letters: %v", err)
+	}
+
+	dispatcher := webhook.NewDispatcher(webhook.DispatcherConfig{
+		QueueSize:    conf.WebhookConfig.QueueSize,
+		MaxAttempts:  conf.WebhookConfig.MaxAttempts,
+		BaseBackoff:  conf.WebhookConfig.BaseBackoff,
+		Timeout:      conf.WebhookConfig.Timeout,
+		AllowedHosts: conf.WebhookConfig.AllowedHosts,
+	}, deadLetterStore)
+	dispatcher.Start(ctx, conf.WebhookConfig.Workers)
+	checker.AddReadiness(health.SizeCheck("webhook_queue", func() (int64, error) { return int64(dispatcher.Backlog()), nil }, conf.HealthConfig.MaxBacklog))
+	ruleEngine := webhook.NewEngine(subscriptionStore, dispatcher)
+	auditStore = webhook.NewNotifyingStore(auditStore, ruleEngine)
+	erasureStore = webhook.NewNotifyingStore(erasureStore, ruleEngine)
+	//archived audits are replayed to the rules as they were received
+	restoreService.WithReplaySink(backup.SinkWebhooks, backup.AuditSinkFunc(func(ctx context.Context, inputs []audit.DataAudit) error {
+		for _, input := range inputs {
+			if conf.CryptoConfig.Enabled {
+				opened, err := shred.Open(keyStore, input)
+				if err != nil {
+					return err
+				}
+				input = opened
+			}
+			if err := ruleEngine.Evaluate(input); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	subjectErasureService := backup.NewSubjectErasure(keyStore, erasureStore)
+
+	fileAuditService := backup.NewFileAudit(auditStore, memIdempotency)
+
+	//in cluster mode every audit is saved on the node owning its key
+	var auditService handle.AuditStoreService = fileAuditService
+	var rebalancer *cluster.Rebalancer
+	if conf.ClusterConfig.Enabled {
+		clusterConf := conf.ClusterConfig
+		if clusterConf.NodeID == "" || clusterConf.Secret == "" {
+			log.Fatalf("cluster mode requires CLUSTER_NODE_ID and CLUSTER_SECRET")
+		}
+		if conf.LeaderConfig.Backend != "" && conf.LeaderConfig.Backend != "none" {
+			log.Fatalf("cluster mode requires LEADER_BACKEND=none: every node archives the keys it owns")
+		}
+		if conf.CryptoConfig.Enabled {
+			log.Fatalf("cluster mode cannot run with CRYPTO_ENABLED: subject keys stay on the node that created them and erasures are not routed to their owner")
+		}
+
+		ring := cluster.NewRing(clusterConf.VirtualNodes)
+		peerClient := cluster.NewClient(clusterConf.Peers, clusterConf.Secret, &http.Client{Timeout: clusterConf.Timeout})
+		membership := cluster.NewMembership(clusterConf.NodeID, slices.Collect(maps.Keys(clusterConf.Peers)), ring, peerClient, clusterConf.FailureThreshold)
+		go membership.Run(ctx, clusterConf.ProbePeriod, clusterConf.Timeout)
+
+		rebalancer = cluster.NewRebalancer(clusterConf.NodeID, ring, dataStore, peerClient)
+		go rebalancer.Run(ctx, clusterConf.RebalancePeriod, membership.Changes())
+
+		auditService = cluster.NewRouter(clusterConf.NodeID, ring, peerClient, fileAuditService)
+	}
+
+	if conf.SourceConfig.Backend != "" {
+		brokerSource, err := source.New(ctx, conf)
+		if err != nil {
+			log.Fatalf("failed to create ingestion source: %v", err)
+		}
+		defer brokerSource.Close()
+
+		ingestor := source.NewIngestor(brokerSource, auditService, brokerSource, conf.SourceConfig.RetryBackoff)
+		go ingestor.Run(ctx)
+	}
+
+	//tokens bound to a tenant authenticate too
+	apiTokens := slices.AppendSeq(slices.Clone(conf.AppConfig.APITokens), maps.Keys(conf.TenantConfig.Tokens))
+	authenticator := auth.NewTokenAuthenticator(apiTokens)
+	requireToken := handle.RequireToken(authenticator)
+	tenantResolver := auth.NewTenantResolver(tenants, conf.TenantConfig.Tokens)
+	resolveTenant := handle.ResolveTenant(tenantResolver)
+	//routes acting on every tenant only take the tokens of APP_API_TOKENS
+	rejectTenantTokens := handle.RejectTenantTokens(tenantResolver)
+	requireAdmin := func(pattern string, next func(w http.ResponseWriter, r *http.Request)) (string, func(w http.ResponseWriter, r *http.Request)) {
+		return requireToken(rejectTenantTokens(pattern, next))
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc(handle.Health())
-	mux.HandleFunc(handle.ManualBackup(backupService))
-	mux.HandleFunc(handle.ManualStore(backupService))
-	mux.HandleFunc(handle.AuditStore(fileAuditService))
-
-	//task to reset idempotency keys
-	go tasks.IdempotencyClear(ctx, conf.TasksConfig.IdempotencyClearPeriod, memIdempotency)
+	mux.HandleFunc(checker.Liveness())
+	mux.HandleFunc(checker.Readiness())
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc(requireAdmin(handle.ManualBackup(archival)))
+	mux.HandleFunc(requireAdmin(handle.ManualStore(archival)))
+	mux.HandleFunc(requireAdmin(resolveTenant(handle.GetArchive(archives))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.Restore(restoreService))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.StartReplay(restoreService))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.GetReplay(restoreService))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.AuditStore(auditService, dataStore.RetryAfter()))))
+	if conf.ClusterConfig.Enabled {
+		mux.HandleFunc(handle.ClusterAudit(fileAuditService, conf.ClusterConfig.Secret))
+		mux.HandleFunc(handle.ClusterHandoff(dataStore, conf.ClusterConfig.Secret))
+	}
+	if walStore != nil {
+		mux.HandleFunc(handle.WALAudit(walStore, conf.WALConfig.Secret))
+	}
+	mux.HandleFunc(requireToken(resolveTenant(handle.SubjectErase(subjectErasureService))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.AuditStream(broker))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.AuditStreamWebSocket(broker))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.CreateWebhook(subscriptionStore))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.ListWebhooks(subscriptionStore))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.GetWebhook(subscriptionStore))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.UpdateWebhook(subscriptionStore))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.DeleteWebhook(subscriptionStore))))
+	mux.HandleFunc(requireToken(resolveTenant(handle.ListDeadLetters(deadLetterStore))))
+	if searchIndex != nil {
+		var searchService handle.SearchService = searchIndex
+		if conf.CryptoConfig.Enabled {
+			searchService = shred.NewDecryptingSearch(searchIndex, keyStore)
+		}
+		mux.HandleFunc(requireToken(resolveTenant(handle.Search(searchService))))
+	}
 
+	location, err := time.LoadLocation(conf.TasksConfig.Timezone)
+	if err != nil {
+		log.Fatalf("failed to load tasks timezone: %v", err)
+	}
+	taskStates, err := scheduler.NewFileStateStore(conf.TasksConfig.StateDir)
+	if err != nil {
+		log.Fatalf("failed to create task state store: %v", err)
+	}
 	//task to backup sent data to storage
-	go tasks.Backup(ctx, conf.TasksConfig.BackupPeriod, backupService)
-
+	backupJob := tasks.Backup(conf.TasksConfig.BackupSchedule, archival)
 	//task to save sent data to storage
-	go tasks.Store(ctx, conf.TasksConfig.StorePeriod, backupService)
+	storeJob := tasks.Store(conf.TasksConfig.StoreSchedule, archival)
+	if rebalancer != nil {
+		// a key is only archived complete, by its owner
+		storeJob = tasks.AfterHandoff(rebalancer, storeJob)
+	}
+	if walNode != nil {
+		// the log is compacted once what it holds is in the bucket
+		storeJob = tasks.RecordArchival(walNode, storeJob)
+	}
+	//task to check the archived days against the bucket
+	reconcileJob := tasks.Reconcile(conf.TasksConfig.ReconcileSchedule, reconciliation)
+	archivalJobs := []scheduler.Job{backupJob, storeJob, reconcileJob}
+
+	//only the leader replica archives, so replicas do not overwrite each other
+	var leadership tasks.Leadership
+	leaseBackend, err := leader.NewBackend(ctx, conf)
+	if err != nil {
+		log.Fatalf("failed to create leader lease: %v", err)
+	}
+	if leaseBackend != nil {
+		elector := leader.NewElector(leaseBackend, leader.HolderID(conf.LeaderConfig), conf.LeaderConfig.TTL)
+		go elector.Run(ctx)
+		leadership = elector
+	}
+	if walNode != nil {
+		leadership = walNode
+	}
+	if leadership != nil {
+		for i, job := range archivalJobs {
+			archivalJobs[i] = tasks.LeaderOnly(leadership, job)
+		}
+	}
+
+	taskScheduler := scheduler.New(scheduler.Config{Location: location, Jitter: conf.TasksConfig.Jitter}, taskStates)
+	for _, job := range append([]scheduler.Job{
+		//task to reset idempotency keys
+		tasks.IdempotencyClear(conf.TasksConfig.IdempotencyClearSchedule, memIdempotency),
+	}, archivalJobs...) {
+		if err := taskScheduler.Add(job); err != nil {
+			log.Fatalf("failed to schedule task: %v", err)
+		}
+	}
+	if err := taskScheduler.Start(ctx); err != nil {
+		log.Fatalf("failed to start scheduler: %v", err)
+	}
+	mux.HandleFunc(requireAdmin(handle.ListTasks(taskScheduler)))
+
+	//task to publish the outbox to the message broker
+	if relay != nil {
+		go tasks.OutboxRelay(ctx, conf.SinkConfig.FlushPeriod, relay)
+	}
 
 	server := &http.Server{
 		Addr:              conf.AppConfig.Port,
@@ -69,14 +469,27 @@ func main() {
 	}
 
 	go func() {
-		log.Println("server is running on port 8080")
+		slog.Info("server is running", "port", conf.AppConfig.Port)
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("failed to start server: %v", err)
 		}
 	}()
 
+	grpcServer := rpc.NewServer(authenticator, tenantResolver, rpc.NewAuditServer(auditService, broker))
+	go func() {
+		listener, err := net.Listen("tcp", conf.AppConfig.GRPCAddr)
+		if err != nil {
+			log.Fatalf("failed to listen for grpc: %v", err)
+		}
+		slog.Info("grpc server is running", "addr", conf.AppConfig.GRPCAddr)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatalf("failed to start grpc server: %v", err)
+		}
+	}()
+
 	<-ctx.Done()
-	log.Println("shutting down server...")
+	slog.Info("shutting down server")
+	grpcServer.GracefulStop()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
@@ -84,6 +497,41 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("server forced to shutdown: %v", err)
 	}
+	taskScheduler.Wait()
+	if walNode != nil {
+		if err := walNode.Shutdown(); err != nil {
+			slog.Error("failed to stop wal", "error", err)
+		}
+	}
 
-	log.Println("server exited gracefully")
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush spans", "error", err)
+	}
+
+	slog.Info("server exited gracefully")
+}
+
+// newArchiveStore writes to storage in the configured format and layout.
+func newArchiveStore(conf cfg.ArchiveConfig, storage store.ObjectStorage, node string, storeDays int) (backup.S3Store, error) {
+	switch conf.Layout {
+	case "", store.LayoutDaily:
+	case store.LayoutHourly:
+		if conf.Format != "" && conf.Format != "json" {
+			return nil, errors.New("ARCHIVE_LAYOUT=hourly requires ARCHIVE_FORMAT=json")
+		}
+		return store.NewPartitionedArchiveStore(storage).WithNode(node).WithRetention(storeDays), nil
+	default:
+		return nil, fmt.Errorf("unknown archive layout: %s", conf.Layout)
+	}
+
+	switch conf.Format {
+	case "", "json":
+		return store.NewArchiveStore(storage).WithNode(node).WithRetention(storeDays), nil
+	case "parquet":
+		return store.NewParquetArchiveStore(storage, false).WithNode(node).WithRetention(storeDays), nil
+	case "both":
+		return store.NewParquetArchiveStore(storage, true).WithNode(node).WithRetention(storeDays), nil
+	default:
+		return nil, fmt.Errorf("unknown archive format: %s", conf.Format)
+	}
 }
@@ -0,0 +1,19 @@
+package handle
+
+import (
+	"net/http"
+
+	"github.com/IsaacDSC/auditory/internal/stream"
+)
+
+func AuditStream(broker *stream.Broker) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /audits/stream", func(w http.ResponseWriter, r *http.Request) {
+		stream.ServeSSE(broker, w, r)
+	}
+}
+
+func AuditStreamWebSocket(broker *stream.Broker) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /audits/stream/ws", func(w http.ResponseWriter, r *http.Request) {
+		stream.ServeWebSocket(broker, w, r)
+	}
+}
@@ -0,0 +1,52 @@
+package handle
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/auditory/internal/auth"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+type Authenticator interface {
+	Authenticate(authorization string) error
+}
+
+type TenantResolver interface {
+	Resolve(authorization, requested string) (string, error)
+}
+
+// RequireToken wraps an admin route so it only runs for callers with an API
+// token or a token bound to a tenant.
+func RequireToken(authenticator Authenticator) func(string, func(w http.ResponseWriter, r *http.Request)) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return func(pattern string, next func(w http.ResponseWriter, r *http.Request)) (string, func(w http.ResponseWriter, r *http.Request)) {
+		return pattern, func(w http.ResponseWriter, r *http.Request) {
+			if err := authenticator.Authenticate(r.Header.Get("Authorization")); err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// ResolveTenant wraps an admin route so it runs for the tenant of the caller's
+// token or X-Tenant-ID header, set in the request context; see RequireToken.
+func ResolveTenant(resolver TenantResolver) func(string, func(w http.ResponseWriter, r *http.Request)) (string, func(w http.ResponseWriter, r *http.Request)) {
+	return func(pattern string, next func(w http.ResponseWriter, r *http.Request)) (string, func(w http.ResponseWriter, r *http.Request)) {
+		return pattern, func(w http.ResponseWriter, r *http.Request) {
+			tenant, err := resolver.Resolve(r.Header.Get("Authorization"), r.Header.Get("X-Tenant-ID"))
+			switch {
+			case errors.Is(err, auth.ErrTenantMismatch):
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			next(w, r.WithContext(ctxkey.SetTenant(r.Context(), tenant)))
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package proxy
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("proxy")
@@ -3,12 +3,18 @@ package proxy
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
-	"log"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"time"
+
+	"github.com/IsaacDSC/auditory/internal/auth"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type InputAudit struct {
@@ -23,11 +29,22 @@ type InputAudit struct {
 
 type AuditorFn func(ctx context.Context, input InputAudit) error
 
+// TenantTokenHeader carries the token an exchange proves its X-Tenant-ID with;
+// the client's Authorization belongs to the upstream.
+const TenantTokenHeader = "X-Tenant-Token"
+
+type TenantResolveFn func(authorization, requested string) (string, error)
+
 type AuditProxy struct {
 	proxy            *httputil.ReverseProxy
 	target           *url.URL
 	requestCallback  AuditorFn
 	responseCallback AuditorFn
+
+	pressure   func() error
+	retryAfter time.Duration
+
+	resolveTenant TenantResolveFn
 }
 
 func NewAuditProxy(target *url.URL, requestCallback AuditorFn, responseCallback AuditorFn) *AuditProxy {
@@ -42,12 +59,68 @@ func NewAuditProxy(target *url.URL, requestCallback AuditorFn, responseCallback
 
 	proxy.Director = ap.director
 	proxy.ModifyResponse = ap.modifyResponse
+	proxy.Transport = timedTransport{next: http.DefaultTransport}
+
+	return ap
+}
+
+// WithBackpressure answers 429 with Retry-After, without forwarding, while
+// pressure fails: the exchange could not be audited.
+func (ap *AuditProxy) WithBackpressure(pressure func() error, retryAfter time.Duration) *AuditProxy {
+	ap.pressure = pressure
+	ap.retryAfter = retryAfter
+	return ap
+}
 
+// WithTenants audits an exchange under the tenant resolve grants to its
+// X-Tenant-Token and X-Tenant-ID headers; exchanges it refuses are not
+// forwarded. The token is removed before the exchange is forwarded and audited.
+func (ap *AuditProxy) WithTenants(resolve TenantResolveFn) *AuditProxy {
+	ap.resolveTenant = resolve
 	return ap
 }
 
+// ServeHTTP continues the caller's trace from traceparent/tracestate so both
+// audit callbacks run inside the proxy span. The headers are forwarded as
+// received, since the audited request shares them.
 func (ap *AuditProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ap.proxy.ServeHTTP(w, r)
+	ctx := telemetry.Extract(r.Context(), r.Header)
+	ctx, span := telemetry.Start(ctx, "audit.proxy",
+		attribute.String("http.method", r.Method),
+		attribute.String("http.path", r.URL.Path),
+	)
+	defer span.End()
+
+	if ap.resolveTenant != nil {
+		token := r.Header.Get(TenantTokenHeader)
+		r.Header.Del(TenantTokenHeader)
+
+		tenant, err := ap.resolveTenant("Bearer "+token, r.Header.Get("X-Tenant-ID"))
+		switch {
+		case errors.Is(err, auth.ErrUnknownTenant):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			logger.WarnContext(ctx, "request refused for its tenant", "error", err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case tenant == "":
+			r.Header.Del("X-Tenant-ID")
+		default:
+			r.Header.Set("X-Tenant-ID", tenant)
+		}
+	}
+
+	if ap.pressure != nil {
+		if err := ap.pressure(); err != nil {
+			logger.WarnContext(ctx, "request refused under backpressure", "error", err)
+			w.Header().Set("Retry-After", strconv.Itoa(int(ap.retryAfter.Seconds())))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+	}
+
+	ap.proxy.ServeHTTP(w, r.WithContext(ctx))
 }
 
 func (ap *AuditProxy) director(r *http.Request) {
@@ -79,7 +152,7 @@ func (ap *AuditProxy) auditRequest(r *http.Request) {
 		Path:    r.URL.Path,
 		Query:   r.URL.RawQuery,
 	}); err != nil {
-		log.Printf("audit error: %v", err)
+		logger.ErrorContext(ctx, "failed to audit exchange", "error", err)
 	}
 }
 
@@ -97,8 +170,27 @@ func (ap *AuditProxy) modifyResponse(resp *http.Response) error {
 		StatusCode:     resp.StatusCode,
 		RequestHeaders: resp.Request.Header,
 	}); err != nil {
-		log.Printf("audit error: %v", err)
+		logger.ErrorContext(ctx, "failed to audit exchange", "error", err)
 	}
 
 	return nil
 }
+
+// timedTransport measures the upstream round trip alone, without the time
+// spent recording the audit.
+type timedTransport struct {
+	next http.RoundTripper
+}
+
+func (tt timedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := tt.next.RoundTrip(r)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.ProxyUpstreamDuration.WithLabelValues(r.Method, code).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
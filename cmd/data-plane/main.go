@@ -1,18 +1,59 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
 	"github.com/IsaacDSC/auditory/cmd/data-plane/internal/handle"
 	"github.com/IsaacDSC/auditory/cmd/data-plane/internal/proxy"
+	"github.com/IsaacDSC/auditory/internal/auth"
 	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/health"
+	"github.com/IsaacDSC/auditory/internal/logging"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/shred"
 	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/internal/stream"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"github.com/IsaacDSC/auditory/internal/webhook"
+	"github.com/ilyakaznacheev/cleanenv"
 )
 
 func main() {
+	// the data plane only shares these sections of the control plane config
+	var conf struct {
+		App struct {
+			APITokens []string `env:"API_TOKENS" env-separator:","`
+		} `env-prefix:"APP_"`
+		Log       cfg.LogConfig       `env-prefix:"LOG_"`
+		Health    cfg.HealthConfig    `env-prefix:"HEALTH_"`
+		Quota     cfg.QuotaConfig     `env-prefix:"QUOTA_"`
+		Storage   cfg.StorageConfig   `env-prefix:"STORAGE_"`
+		Tenant    cfg.TenantConfig    `env-prefix:"TENANT_"`
+		Crypto    cfg.CryptoConfig    `env-prefix:"CRYPTO_"`
+		Webhook   cfg.WebhookConfig   `env-prefix:"WEBHOOK_"`
+		Telemetry cfg.TelemetryConfig `env-prefix:"TELEMETRY_"`
+	}
+	//env-default only fills zero fields, so TELEMETRY_SERVICE_NAME still wins
+	conf.Telemetry.ServiceName = "auditory-data-plane"
+	if err := cleanenv.ReadEnv(&conf); err != nil {
+		log.Fatalf("failed to read config: %v", err)
+	}
+	if err := logging.Setup(conf.Log); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
 	targetURL := os.Getenv("TARGET_URL")
 	if targetURL == "" {
 		log.Fatal("TARGET_URL environment variable is required")
@@ -28,15 +69,133 @@ func main() {
 		log.Fatalf("invalid target URL: %v", err)
 	}
 
-	dataStore := store.NewDataFileStore()
-	onCallService := backup.NewHttpOnCallService(dataStore)
+	adminPort := os.Getenv("ADMIN_PORT")
+	if adminPort == "" {
+		adminPort = "9090"
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	shutdownTracing, err := telemetry.Setup(ctx, conf.Telemetry)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+
+	dataStore, err := store.NewDataFileStore().WithDir(conf.Storage.DataDir)
+	if err != nil {
+		log.Fatalf("failed to open local store: %v", err)
+	}
+	if dataStore, err = dataStore.WithQuota(conf.Quota); err != nil {
+		log.Fatalf("failed to apply local store quota: %v", err)
+	}
+
+	//exchanges carrying X-Tenant-ID are kept in the directory of their tenant
+	tenantStores, err := store.OpenTenantStores(dataStore, conf.Storage, conf.Quota, conf.Tenant)
+	if err != nil {
+		log.Fatalf("failed to open tenant local stores: %v", err)
+	}
+	tenantRouter := store.NewTenantStore(dataStore)
+	for tenant, tenantStore := range tenantStores {
+		tenantRouter.Add(tenant, tenantStore)
+	}
+	broker := stream.NewBroker(0, 0)
+
+	subscriptionStore, err := webhook.NewFileSubscriptionStore(conf.Webhook.Dir)
+	if err != nil {
+		log.Fatalf("failed to open webhook subscriptions: %v", err)
+	}
+
+	deadLetterStore, err := webhook.NewFileDeadLetterStore(conf.Webhook.Dir)
+	if err != nil {
+		log.Fatalf("failed to open webhook dead letters: %v", err)
+	}
+
+	dispatcher := webhook.NewDispatcher(webhook.DispatcherConfig{
+		QueueSize:    conf.Webhook.QueueSize,
+		MaxAttempts:  conf.Webhook.MaxAttempts,
+		BaseBackoff:  conf.Webhook.BaseBackoff,
+		Timeout:      conf.Webhook.Timeout,
+		AllowedHosts: conf.Webhook.AllowedHosts,
+	}, deadLetterStore)
+	dispatcher.Start(ctx, conf.Webhook.Workers)
+
+	//exchanges are encrypted before they are stored; subscribers and webhooks
+	//sit above the encryption and see clear data
+	var auditStore backup.HttpAuditStore = tenantRouter
+	if conf.Crypto.Enabled {
+		keyStore, err := store.NewFileKeyStore(conf.Crypto.KeysDir)
+		if err != nil {
+			log.Fatalf("failed to create key store: %v", err)
+		}
+		auditStore = shred.NewEncryptedStore(auditStore, keyStore)
+	}
+	auditStore = stream.NewPublishingStore(auditStore, broker)
+	auditStore = webhook.NewNotifyingStore(auditStore, webhook.NewEngine(subscriptionStore, dispatcher))
+
+	onCallService := backup.NewHttpOnCallService(auditStore).WithRedactions(conf.Tenant.Redactions)
 	requestHandler := handle.Request(onCallService)
 	responseHandler := handle.Response(onCallService)
 
-	proxy := proxy.NewAuditProxy(target, requestHandler, responseHandler)
+	//an exchange only gets the tenant its X-Tenant-Token is bound to
+	tenantResolver := auth.NewTenantResolver(conf.Storage.Tenants, conf.Tenant.Tokens)
+	proxy := proxy.NewAuditProxy(target, requestHandler, responseHandler).
+		WithBackpressure(dataStore.Pressure, dataStore.RetryAfter()).
+		WithTenants(tenantResolver.ResolveBound)
+
+	//tokens bound to a tenant authenticate too, and tenants cannot share an
+	//open admin listener
+	apiTokens := slices.AppendSeq(slices.Clone(conf.App.APITokens), maps.Keys(conf.Tenant.Tokens))
+	if len(conf.Storage.Tenants) > 0 && len(apiTokens) == 0 {
+		log.Fatalf("STORAGE_TENANTS requires APP_API_TOKENS or TENANT_TOKENS to protect the admin listener")
+	}
+	requireToken := handle.RequireToken(auth.NewTokenAuthenticator(apiTokens))
+	resolveTenant := handle.ResolveTenant(tenantResolver)
+
+	// every path on the proxy port is forwarded, so the plane's own endpoints
+	// live on a separate admin listener
+	adminMux := http.NewServeMux()
+	adminMux.HandleFunc(requireToken(resolveTenant(handle.AuditStream(broker))))
+	adminMux.HandleFunc(requireToken(resolveTenant(handle.AuditStreamWebSocket(broker))))
+	adminMux.Handle("GET /metrics", metrics.Handler())
+
+	checker := health.NewChecker(conf.Health.Timeout)
+	checker.AddReadiness(
+		health.DirCheck("tmp", dataStore.Dir(), conf.Health.MinFreeBytes),
+		health.SizeCheck("pending_requests", onCallService.Pending, conf.Health.MaxBacklog),
+		health.SizeCheck("webhook_queue", func() (int64, error) { return int64(dispatcher.Backlog()), nil }, conf.Health.MaxBacklog),
+	)
+	adminMux.HandleFunc(checker.Liveness())
+	adminMux.HandleFunc(checker.Readiness())
+
+	go func() {
+		slog.Info("starting admin server", "port", adminPort)
+		if err := http.ListenAndServe(":"+adminPort, adminMux); err != nil {
+			slog.Error("admin server error", "error", err)
+		}
+	}()
+
+	server := &http.Server{Addr: ":" + port, Handler: proxy}
+	go func() {
+		slog.Info("starting proxy server", "port", port, "target", targetURL)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server error: %v", err)
+		}
+	}()
 
-	log.Printf("Starting proxy server on :%s -> %s", port, targetURL)
-	if err := http.ListenAndServe(":"+port, proxy); err != nil {
-		log.Fatalf("server error: %v", err)
+	<-ctx.Done()
+	slog.Info("shutting down proxy server")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("proxy server forced to shutdown", "error", err)
 	}
+	//flush the spans of the exchanges the proxy finished while draining
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush spans", "error", err)
+	}
+
+	slog.Info("proxy server exited gracefully")
 }
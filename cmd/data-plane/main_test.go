@@ -18,8 +18,7 @@ import (
 )
 
 func TestDataPlaneIntegration(t *testing.T) {
-	// Limpar dados anteriores
-	os.Remove("tmp/test-client.json")
+	dataDir := t.TempDir()
 
 	// Setup config
 	cfg.SetConfig(&cfg.GeneralConfig{
@@ -56,6 +55,8 @@ func TestDataPlaneIntegration(t *testing.T) {
 	cmd.Env = append(os.Environ(),
 		"TARGET_URL="+targetServer.URL,
 		"PORT="+proxyPort,
+		"STORAGE_DATA_DIR="+dataDir,
+		"WEBHOOK_DIR="+t.TempDir(),
 	)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -94,7 +95,10 @@ func TestDataPlaneIntegration(t *testing.T) {
 
 	// Verificar dados auditados
 	time.Sleep(100 * time.Millisecond)
-	dataStore := store.NewDataFileStore()
+	dataStore, err := store.NewDataFileStore().WithDir(dataDir)
+	if err != nil {
+		t.Fatalf("failed to open data dir: %v", err)
+	}
 	data, err := dataStore.Get(t.Context(), store.Key(clientID))
 	if err != nil {
 		t.Fatalf("failed to get audit data: %v", err)
@@ -11,11 +11,15 @@ type DataAudit struct {
 }
 
 type MetadataAudit struct {
-	Key           string    `json:"key"` // example: "user:123"
+	ID            string    `json:"id,omitempty"` // unique per event, stamped at ingestion
+	Key           string    `json:"key"`          // example: "user:123"
 	EventName     string    `json:"event_name"`
 	RequestID     string    `json:"request_id"`
 	CorrelationID string    `json:"correlation_id"`
 	EventAt       time.Time `json:"event_at"`
+	TraceID       string    `json:"trace_id,omitempty"` // W3C trace of the ingestion
+	SpanID        string    `json:"span_id,omitempty"`
+	Tenant        string    `json:"tenant,omitempty"` // empty in the shared namespace
 }
 
 func (m MetadataAudit) Validate() error {
@@ -1,5 +1,8 @@
 package audit
 
+// HttpAuditEvent is the event name of exchanges recorded by the data plane.
+const HttpAuditEvent = "http_audit"
+
 type HttpAudit struct {
 	Request  RequestAudit  `json:"request"`
 	Response ResponseAudit `json:"response"`
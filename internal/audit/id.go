@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns a ULID: 26 Crockford base32 characters holding the
+// millisecond of clock.Now and 80 random bits, so ids sort by creation time
+// and two events never share one.
+func NewID() string {
+	var raw [16]byte
+	ms := uint64(clock.Now().UnixMilli())
+	binary.BigEndian.PutUint16(raw[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(raw[2:6], uint32(ms))
+	_, _ = rand.Read(raw[6:])
+
+	hi, lo := binary.BigEndian.Uint64(raw[:8]), binary.BigEndian.Uint64(raw[8:])
+	var id [26]byte
+	for i := 25; i >= 0; i-- {
+		id[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(id[:])
+}
+
+// EnsureID stamps a new ID on m unless it already has one, so an event keeps
+// the id it got at ingestion through forwarding, replication and handoffs.
+func (m *MetadataAudit) EnsureID() {
+	if m.ID == "" {
+		m.ID = NewID()
+	}
+}
+
+// IDTime is the millisecond a ULID made by NewID was created at.
+func IDTime(id string) (time.Time, bool) {
+	if len(id) != 26 || id[0] > '7' {
+		return time.Time{}, false
+	}
+
+	var ms uint64
+	for i := range 10 {
+		value := strings.IndexByte(crockford, id[i])
+		if value < 0 {
+			return time.Time{}, false
+		}
+		ms = ms<<5 | uint64(value)
+	}
+	return time.UnixMilli(int64(ms)).UTC(), true
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownTenant  = errors.New("unknown tenant")
+	ErrTenantMismatch = errors.New("tenant does not match the api token")
+	ErrTenantUnbound  = errors.New("tenant requires a token bound to it")
+)
+
+// TenantResolver finds the tenant a request acts for: the tenant its API
+// token is bound to, otherwise the one it asks for in X-Tenant-ID. An empty
+// tenant is the shared namespace.
+type TenantResolver struct {
+	known  map[string]bool
+	tokens map[string]string
+}
+
+func NewTenantResolver(tenants []string, tokens map[string]string) *TenantResolver {
+	tr := &TenantResolver{
+		known:  make(map[string]bool, len(tenants)),
+		tokens: make(map[string]string, len(tokens)),
+	}
+	for _, tenant := range tenants {
+		tr.known[tenant] = true
+	}
+	for token, tenant := range tokens {
+		tr.tokens[token] = tenant
+	}
+	return tr
+}
+
+// Resolve fails with ErrTenantMismatch when a token bound to a tenant asks for
+// another one, and with ErrUnknownTenant for a tenant not configured.
+func (tr *TenantResolver) Resolve(authorization, requested string) (string, error) {
+	tenant := requested
+	if bound, ok := tr.Bound(authorization); ok {
+		if requested != "" && requested != bound {
+			return "", ErrTenantMismatch
+		}
+		tenant = bound
+	}
+
+	if tenant != "" && !tr.known[tenant] {
+		return "", ErrUnknownTenant
+	}
+	return tenant, nil
+}
+
+// ResolveBound is Resolve for callers that are not otherwise authenticated:
+// a tenant is only granted to a token bound to it, so a requested tenant
+// without one fails with ErrTenantUnbound.
+func (tr *TenantResolver) ResolveBound(authorization, requested string) (string, error) {
+	if _, ok := tr.Bound(authorization); !ok && requested != "" {
+		return "", ErrTenantUnbound
+	}
+	return tr.Resolve(authorization, requested)
+}
+
+// Bound is the tenant the token of authorization is bound to, if any.
+func (tr *TenantResolver) Bound(authorization string) (string, bool) {
+	token, _ := strings.CutPrefix(authorization, "Bearer ")
+	tenant, ok := tr.tokens[token]
+	return tenant, ok && token != ""
+}
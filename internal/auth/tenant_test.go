@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenantResolver_Resolve(t *testing.T) {
+	resolver := NewTenantResolver([]string{"acme", "globex"}, map[string]string{"acme-token": "acme"})
+
+	tests := []struct {
+		name          string
+		authorization string
+		requested     string
+		expected      string
+		expectedError error
+	}{
+		{name: "success - shared namespace without tenant", authorization: "Bearer other"},
+		{name: "success - tenant of the token", authorization: "Bearer acme-token", expected: "acme"},
+		{name: "success - token repeats its tenant", authorization: "Bearer acme-token", requested: "acme", expected: "acme"},
+		{name: "success - requested tenant", authorization: "Bearer other", requested: "globex", expected: "globex"},
+		{name: "error - token asks for another tenant", authorization: "Bearer acme-token", requested: "globex", expectedError: ErrTenantMismatch},
+		{name: "error - unknown tenant", requested: "initech", expectedError: ErrUnknownTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := resolver.Resolve(tt.authorization, tt.requested)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if tenant != tt.expected {
+				t.Errorf("expected tenant %q, got %q", tt.expected, tenant)
+			}
+		})
+	}
+}
+
+func TestTenantResolver_ResolveBound(t *testing.T) {
+	resolver := NewTenantResolver([]string{"acme", "globex"}, map[string]string{"acme-token": "acme"})
+
+	tests := []struct {
+		name          string
+		authorization string
+		requested     string
+		expected      string
+		expectedError error
+	}{
+		{name: "success - shared namespace without tenant"},
+		{name: "success - tenant of the token", authorization: "Bearer acme-token", expected: "acme"},
+		{name: "success - token repeats its tenant", authorization: "Bearer acme-token", requested: "acme", expected: "acme"},
+		{name: "error - requested tenant without token", requested: "globex", expectedError: ErrTenantUnbound},
+		{name: "error - requested tenant with an unbound token", authorization: "Bearer other", requested: "acme", expectedError: ErrTenantUnbound},
+		{name: "error - token asks for another tenant", authorization: "Bearer acme-token", requested: "globex", expectedError: ErrTenantMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := resolver.ResolveBound(tt.authorization, tt.requested)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if tenant != tt.expected {
+				t.Errorf("expected tenant %q, got %q", tt.expected, tenant)
+			}
+		})
+	}
+}
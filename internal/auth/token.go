@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+)
+
+var ErrUnauthenticated = errors.New("missing or invalid api token")
+
+// TokenAuthenticator checks "Bearer <token>" credentials against a static
+// list. Without tokens every request is accepted, which keeps local setups
+// working as before.
+type TokenAuthenticator struct {
+	tokens [][]byte
+}
+
+func NewTokenAuthenticator(tokens []string) *TokenAuthenticator {
+	ta := &TokenAuthenticator{}
+	for _, token := range tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			ta.tokens = append(ta.tokens, []byte(token))
+		}
+	}
+	return ta
+}
+
+func (ta *TokenAuthenticator) Authenticate(authorization string) error {
+	if len(ta.tokens) == 0 {
+		return nil
+	}
+
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return ErrUnauthenticated
+	}
+
+	// compare against every token so timing does not reveal which one matched
+	matched := 0
+	for _, candidate := range ta.tokens {
+		matched |= subtle.ConstantTimeCompare(candidate, []byte(token))
+	}
+	if matched == 0 {
+		return ErrUnauthenticated
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTokenAuthenticator_Authenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        []string
+		authorization string
+		wantErr       bool
+	}{
+		{name: "success - disabled without tokens", authorization: ""},
+		{name: "success - valid token", tokens: []string{"a", "b"}, authorization: "Bearer b"},
+		{name: "error - missing header", tokens: []string{"a"}, authorization: "", wantErr: true},
+		{name: "error - wrong scheme", tokens: []string{"a"}, authorization: "Basic a", wantErr: true},
+		{name: "error - unknown token", tokens: []string{"a"}, authorization: "Bearer c", wantErr: true},
+		{name: "success - blank tokens are ignored", tokens: []string{" ", ""}, authorization: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewTokenAuthenticator(tt.tokens).Authenticate(tt.authorization)
+			if tt.wantErr && !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("expected ErrUnauthenticated, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package backup
+
+//go:generate mockgen -source=archive_reconciliation.go -destination=mocks/mock_archive_reconciliation.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+var ErrArchiveIncomplete = errors.New("archived objects missing or altered")
+
+type ArchiveAuditor interface {
+	Reconcile(ctx context.Context, day time.Time) (store.ArchiveReport, error)
+}
+
+// Reconciliation checks the manifests of the last days against the bucket, so
+// an object deleted or overwritten after it was archived is noticed while the
+// day can still be restored from a backup. The archives of every tenant are
+// checked after the shared one.
+type Reconciliation struct {
+	archive ArchiveAuditor
+	tenants map[string]ArchiveAuditor
+	days    int
+}
+
+func NewReconciliation(archive ArchiveAuditor, days int) *Reconciliation {
+	return &Reconciliation{archive: archive, tenants: make(map[string]ArchiveAuditor), days: days}
+}
+
+// WithTenant checks the archive of tenant as well.
+func (r *Reconciliation) WithTenant(tenant string, archive ArchiveAuditor) *Reconciliation {
+	r.tenants[tenant] = archive
+	return r
+}
+
+func (r *Reconciliation) Reconcile(ctx context.Context) error {
+	today, err := store.NewDate(clock.Now()).Time()
+	if err != nil {
+		return err
+	}
+
+	missing, altered, errs := r.reconcile(ctx, "", r.archive, today)
+	for _, tenant := range slices.Sorted(maps.Keys(r.tenants)) {
+		tenantMissing, tenantAltered, tenantErrs := r.reconcile(ctx, tenant, r.tenants[tenant], today)
+		missing += tenantMissing
+		altered += tenantAltered
+		for _, err := range tenantErrs {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant, err))
+		}
+	}
+
+	metrics.ArchiveReconcileIssues.WithLabelValues(metrics.IssueMissing).Set(float64(missing))
+	metrics.ArchiveReconcileIssues.WithLabelValues(metrics.IssueAltered).Set(float64(altered))
+
+	if missing > 0 || altered > 0 {
+		errs = append(errs, fmt.Errorf("%w: %d missing, %d altered", ErrArchiveIncomplete, missing, altered))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	metrics.TaskLastSuccess.WithLabelValues(metrics.TaskReconcile).Set(float64(clock.Now().Unix()))
+	return nil
+}
+
+// reconcile checks the last days of the archive of tenant, the empty tenant
+// being the shared archive.
+func (r *Reconciliation) reconcile(ctx context.Context, tenant string, archive ArchiveAuditor, today time.Time) (missing, altered int, errs []error) {
+	for i := 1; i <= r.days; i++ {
+		day := today.AddDate(0, 0, -i)
+
+		report, err := archive.Reconcile(ctx, day)
+		if errors.Is(err, store.ErrManifestNotFound) {
+			// nothing was archived that day
+			continue
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "failed to reconcile archive", "tenant", tenant, "date", day.Format(time.DateOnly), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", day.Format(time.DateOnly), err))
+			continue
+		}
+
+		for _, object := range report.Missing {
+			logger.ErrorContext(ctx, "ALERT: archived object missing", "tenant", tenant, "date", report.Date, "key", object.Key, "path", object.Path)
+		}
+		for _, object := range report.Altered {
+			logger.ErrorContext(ctx, "ALERT: archived object altered", "tenant", tenant, "date", report.Date, "key", object.Key, "path", object.Path, "sha256", object.SHA256)
+		}
+		missing += len(report.Missing)
+		altered += len(report.Altered)
+	}
+	return missing, altered, errs
+}
@@ -0,0 +1,112 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/backup/mocks"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReconciliation_Reconcile(t *testing.T) {
+	fixedTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+	twoDaysAgo := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+	clock.SetNow(fixedTime)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	object := store.ArchivedObject{Key: "user:123", Date: "2025-01-14", Path: "audits/user:123/2025-01-14.json"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(archive *mocks.MockArchiveAuditor)
+		expectedError error
+	}{
+		{
+			name: "success - every archived day is complete",
+			setupMocks: func(archive *mocks.MockArchiveAuditor) {
+				archive.EXPECT().Reconcile(gomock.Any(), yesterday).Return(store.ArchiveReport{Date: "2025-01-14", Verified: true}, nil)
+				archive.EXPECT().Reconcile(gomock.Any(), twoDaysAgo).Return(store.ArchiveReport{Date: "2025-01-13", Verified: true}, nil)
+			},
+		},
+		{
+			name: "success - days without a manifest are skipped",
+			setupMocks: func(archive *mocks.MockArchiveAuditor) {
+				archive.EXPECT().Reconcile(gomock.Any(), yesterday).Return(store.ArchiveReport{}, store.ErrManifestNotFound)
+				archive.EXPECT().Reconcile(gomock.Any(), twoDaysAgo).Return(store.ArchiveReport{}, store.ErrManifestNotFound)
+			},
+		},
+		{
+			name: "error - missing and altered objects are flagged",
+			setupMocks: func(archive *mocks.MockArchiveAuditor) {
+				archive.EXPECT().Reconcile(gomock.Any(), yesterday).Return(store.ArchiveReport{
+					Date:     "2025-01-14",
+					Verified: true,
+					Missing:  []store.ArchivedObject{object},
+				}, nil)
+				archive.EXPECT().Reconcile(gomock.Any(), twoDaysAgo).Return(store.ArchiveReport{
+					Date:     "2025-01-13",
+					Verified: true,
+					Altered:  []store.ArchivedObject{object},
+				}, nil)
+			},
+			expectedError: ErrArchiveIncomplete,
+		},
+		{
+			name: "error - bucket unreachable",
+			setupMocks: func(archive *mocks.MockArchiveAuditor) {
+				archive.EXPECT().Reconcile(gomock.Any(), yesterday).Return(store.ArchiveReport{}, errBucketDown)
+				archive.EXPECT().Reconcile(gomock.Any(), twoDaysAgo).Return(store.ArchiveReport{Date: "2025-01-13", Verified: true}, nil)
+			},
+			expectedError: errBucketDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			archive := mocks.NewMockArchiveAuditor(ctrl)
+			tt.setupMocks(archive)
+
+			err := NewReconciliation(archive, 2).Reconcile(context.Background())
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestReconciliation_Tenants(t *testing.T) {
+	clock.SetNow(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+	yesterday := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	shared := mocks.NewMockArchiveAuditor(ctrl)
+	shared.EXPECT().Reconcile(gomock.Any(), yesterday).Return(store.ArchiveReport{Date: "2025-01-14", Verified: true}, nil)
+	acme := mocks.NewMockArchiveAuditor(ctrl)
+	acme.EXPECT().Reconcile(gomock.Any(), yesterday).Return(store.ArchiveReport{
+		Date:     "2025-01-14",
+		Verified: true,
+		Missing:  []store.ArchivedObject{{Key: "user:1", Date: "2025-01-14"}},
+	}, nil)
+
+	err := NewReconciliation(shared, 1).WithTenant("acme", acme).Reconcile(context.Background())
+	if !errors.Is(err, ErrArchiveIncomplete) {
+		t.Errorf("expected the missing object of acme to be flagged, got %v", err)
+	}
+}
+
+var errBucketDown = errors.New("bucket unreachable")
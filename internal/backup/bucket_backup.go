@@ -5,26 +5,40 @@ package backup
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/metrics"
 	"github.com/IsaacDSC/auditory/internal/store"
 	"github.com/IsaacDSC/auditory/pkg/clock"
 )
 
 type FileStore interface {
 	GetAll(ctx context.Context) (map[string]store.Data, error)
-	DeleteAfterDay(ctx context.Context, timeNow time.Time) error
+	Remove(ctx context.Context, key store.Key, sealed store.Data) error
 }
 
 type S3Store interface {
 	Backup(ctx context.Context, timeNow time.Time, data []byte) error
-	Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) error
+	Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) ([]store.ArchivedObject, error)
+	RecordManifest(ctx context.Context, day time.Time, objects []store.ArchivedObject) error
+}
+
+// AuditSink is a secondary destination (e.g. SQL) fed with every key shipped
+// by Store, before its events are removed locally. A key a sink refuses stays
+// local and is archived and fed again by the next run; both skip the events
+// they already have.
+type AuditSink interface {
+	InsertBatch(ctx context.Context, input []audit.DataAudit) error
 }
 
 type Backup struct {
 	fileStore FileStore
 	s3Store   S3Store
+	sinks     []AuditSink
+	layout    string
 }
 
 func NewBackup(fileStore FileStore, s3Store S3Store) *Backup {
@@ -34,70 +48,201 @@ func NewBackup(fileStore FileStore, s3Store S3Store) *Backup {
 	}
 }
 
+func (b *Backup) WithSinks(sinks ...AuditSink) *Backup {
+	b.sinks = append(b.sinks, sinks...)
+	return b
+}
+
+// WithLayout matches what Store seals to the archive layout: with
+// store.LayoutHourly every event of a closed hour is archived, instead of
+// waiting for its day to close.
+func (b *Backup) WithLayout(layout string) *Backup {
+	b.layout = layout
+	return b
+}
+
 func (b *Backup) Backup(ctx context.Context) error {
 	now := clock.Now()
 	data, err := b.fileStore.GetAll(ctx)
 	if err != nil {
-		log.Printf("failed to get all data: %v", err)
+		logger.ErrorContext(ctx, "failed to get all data", "error", err)
 		return err
 	}
 
 	payload, err := json.Marshal(data)
 	if err != nil {
-		log.Printf("failed to marshal data: %v", err)
+		logger.ErrorContext(ctx, "failed to marshal data", "error", err)
 		return err
 	}
 
 	err = b.s3Store.Backup(ctx, now, payload)
 	if err != nil {
-		log.Printf("failed to save data to storage: %v", err)
+		logger.ErrorContext(ctx, "failed to save data to storage", "error", err)
 		return err
 	}
 
+	metrics.TaskLastSuccess.WithLabelValues(metrics.TaskBackup).Set(float64(clock.Now().Unix()))
 	return nil
 }
 
+// Store archives every closed day, or hour, of every key in two phases. Each
+// key's closed events are sealed as read now, uploaded and verified; then the
+// manifest of each day an object landed on is recorded and only after that the
+// sealed events are removed locally. Events arriving meanwhile, those still
+// open and those of a key that failed anywhere stay for the next run.
 func (b *Backup) Store(ctx context.Context) error {
 	now := clock.Now()
-	last24Hours := now.Add(-24 * time.Hour)
 
 	data, err := b.fileStore.GetAll(ctx)
 	if err != nil {
-		log.Printf("failed to get data: %v", err)
+		logger.ErrorContext(ctx, "failed to get data", "error", err)
 		return err
 	}
 
-	counter := 0
+	var errs []error
+	sealed := make(map[string]store.Data, len(data))
+	// the days each key's objects landed on, and their objects by day
+	shippedDays := make(map[string][]string, len(data))
+	manifests := make(map[string][]store.ArchivedObject)
 	for key, value := range data {
+		keySealed := b.seal(value, now)
+		if len(keySealed) == 0 {
+			continue
+		}
 
-		payload, err := json.Marshal(value)
+		objects, err := b.ship(ctx, key, keySealed)
 		if err != nil {
-			log.Printf("failed to marshal data: %v", err)
+			logger.ErrorContext(ctx, "failed to save data to storage", "key", key, "error", err)
+			errs = append(errs, fmt.Errorf("key %s: %w", key, err))
 			continue
 		}
 
-		// save data to storage
-		err = b.s3Store.Save(ctx, key, last24Hours, payload)
+		sealed[key] = keySealed
+		for _, object := range objects {
+			shippedDays[key] = append(shippedDays[key], object.Date)
+			manifests[object.Date] = append(manifests[object.Date], object)
+		}
+	}
+
+	recorded := make(map[string]bool, len(manifests))
+	for date, objects := range manifests {
+		day, err := time.Parse(time.DateOnly, date)
+		if err == nil {
+			err = b.s3Store.RecordManifest(ctx, day, objects)
+		}
 		if err != nil {
-			log.Printf("failed to save data to storage: %v", err)
+			logger.ErrorContext(ctx, "failed to record manifest", "date", date, "error", err)
+			errs = append(errs, fmt.Errorf("manifest %s: %w", date, err))
 			continue
 		}
+		recorded[date] = true
+	}
 
-		counter++
+	for key, keySealed := range sealed {
+		if !allRecorded(shippedDays[key], recorded) {
+			continue
+		}
+
+		if err := b.feedSinks(ctx, key, keySealed); err != nil {
+			errs = append(errs, fmt.Errorf("key %s: %w", key, err))
+			continue
+		}
+
+		if err := b.fileStore.Remove(ctx, store.Key(key), keySealed); err != nil {
+			logger.ErrorContext(ctx, "failed to delete data", "key", key, "error", err)
+			errs = append(errs, fmt.Errorf("key %s: %w", key, err))
+		}
 	}
 
-	if counter < len(data) {
+	if len(errs) > 0 {
 		// ALERT
-		log.Printf("ALERT: failed to save data to storage: %v", err)
-		return err
+		logger.ErrorContext(ctx, "ALERT: failed to save data to storage", "failed", len(errs), "total", len(data))
+		return errors.Join(errs...)
 	}
 
-	// delete tmp data last 24 hours
-	err = b.fileStore.DeleteAfterDay(ctx, last24Hours)
-	if err != nil {
-		log.Printf("failed to delete data: %v", err)
-		return err
+	metrics.TaskLastSuccess.WithLabelValues(metrics.TaskStore).Set(float64(clock.Now().Unix()))
+	return nil
+}
+
+// ship uploads the sealed events of key one day at a time and returns the
+// verified objects written.
+func (b *Backup) ship(ctx context.Context, key string, sealed store.Data) ([]store.ArchivedObject, error) {
+	var objects []store.ArchivedObject
+	for date, events := range sealed {
+		day, err := date.Time()
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", date, err)
+		}
+
+		payload, err := json.Marshal(store.Data{date: events})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+
+		dateObjects, err := b.s3Store.Save(ctx, key, day, payload)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range dateObjects {
+			if object.Date == "" {
+				object.Date = day.Format(time.DateOnly)
+			}
+			objects = append(objects, object)
+		}
 	}
 
+	return objects, nil
+}
+
+// seal copies the events of value that are over: the days before today, or
+// with the hourly layout the events stored before the current UTC hour. The
+// others are still arriving and wait for the next run.
+func (b *Backup) seal(value store.Data, now time.Time) store.Data {
+	today := store.NewDate(now)
+	currentHour := now.UTC().Truncate(time.Hour)
+
+	sealed := make(store.Data, len(value))
+	for date, events := range value {
+		if b.layout != store.LayoutHourly {
+			if date != today && len(events) > 0 {
+				sealed[date] = append([]audit.DataAudit(nil), events...)
+			}
+			continue
+		}
+
+		for _, event := range events {
+			if store.StoredHour(date, event).Before(currentHour) {
+				sealed[date] = append(sealed[date], event)
+			}
+		}
+	}
+	return sealed
+}
+
+func allRecorded(days []string, recorded map[string]bool) bool {
+	for _, day := range days {
+		if !recorded[day] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Backup) feedSinks(ctx context.Context, key string, value store.Data) error {
+	if len(b.sinks) == 0 {
+		return nil
+	}
+
+	var audits []audit.DataAudit
+	for _, events := range value {
+		audits = append(audits, events...)
+	}
+
+	for _, sink := range b.sinks {
+		if err := sink.InsertBatch(ctx, audits); err != nil {
+			logger.ErrorContext(ctx, "failed to feed sink", "key", key, "error", err)
+			return fmt.Errorf("failed to feed sink: %w", err)
+		}
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package backup
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -108,14 +109,33 @@ func TestBackup_Backup(t *testing.T) {
 	}
 }
 
+func testEvent(key string, eventAt time.Time) audit.DataAudit {
+	return audit.DataAudit{
+		Metadata: audit.MetadataAudit{
+			Key:           key,
+			EventName:     "user.created",
+			RequestID:     "req-" + key,
+			CorrelationID: "corr-" + key,
+			EventAt:       eventAt,
+		},
+		Data: map[string]string{"name": "John"},
+	}
+}
+
 func TestBackup_Store(t *testing.T) {
 	fixedTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	day := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
 	last24Hours := fixedTime.Add(-24 * time.Hour)
 	clock.SetNow(fixedTime)
 	defer func() {
 		clock.Now = func() time.Time { return time.Now().UTC() }
 	}()
 
+	userData := store.Data{store.Date("2025-1-14"): {testEvent("user:123", last24Hours)}}
+	orderData := store.Data{store.Date("2025-1-14"): {testEvent("order:456", last24Hours)}}
+	userObject := store.ArchivedObject{Key: "user:123", Date: "2025-01-14", Path: "audits/user:123/2025-01-14.json", Records: 1}
+	orderObject := store.ArchivedObject{Key: "order:456", Date: "2025-01-14", Path: "audits/order:456/2025-01-14.json", Records: 1}
+
 	tests := []struct {
 		name          string
 		setupMocks    func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store)
@@ -124,64 +144,22 @@ func TestBackup_Store(t *testing.T) {
 		{
 			name: "success - store single item",
 			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
-				data := map[string]store.Data{
-					"user:123": {
-						store.Date("2025-1-14"): []audit.DataAudit{
-							{
-								Metadata: audit.MetadataAudit{
-									Key:           "user:123",
-									EventName:     "user.created",
-									RequestID:     "req-123",
-									CorrelationID: "corr-123",
-									EventAt:       last24Hours,
-								},
-								Data: map[string]string{"name": "John"},
-							},
-						},
-					},
-				}
-				fileStore.EXPECT().GetAll(gomock.Any()).Return(data, nil)
-				s3Store.EXPECT().Save(gomock.Any(), "user:123", last24Hours, gomock.Any()).Return(nil)
-				fileStore.EXPECT().DeleteAfterDay(gomock.Any(), last24Hours).Return(nil)
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{"user:123": userData}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{userObject}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, []store.ArchivedObject{userObject}).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("user:123"), userData).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "success - store multiple items",
 			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
-				data := map[string]store.Data{
-					"user:123": {
-						store.Date("2025-1-14"): []audit.DataAudit{
-							{
-								Metadata: audit.MetadataAudit{
-									Key:           "user:123",
-									EventName:     "user.created",
-									RequestID:     "req-123",
-									CorrelationID: "corr-123",
-									EventAt:       last24Hours,
-								},
-								Data: map[string]string{"name": "John"},
-							},
-						},
-					},
-					"order:456": {
-						store.Date("2025-1-14"): []audit.DataAudit{
-							{
-								Metadata: audit.MetadataAudit{
-									Key:           "order:456",
-									EventName:     "order.created",
-									RequestID:     "req-456",
-									CorrelationID: "corr-456",
-									EventAt:       last24Hours,
-								},
-								Data: map[string]string{"total": "100.00"},
-							},
-						},
-					},
-				}
-				fileStore.EXPECT().GetAll(gomock.Any()).Return(data, nil)
-				s3Store.EXPECT().Save(gomock.Any(), gomock.Any(), last24Hours, gomock.Any()).Return(nil).Times(2)
-				fileStore.EXPECT().DeleteAfterDay(gomock.Any(), last24Hours).Return(nil)
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{"user:123": userData, "order:456": orderData}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{userObject}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "order:456", day, gomock.Any()).Return([]store.ArchivedObject{orderObject}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, gomock.Len(2)).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("user:123"), userData).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("order:456"), orderData).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -190,7 +168,22 @@ func TestBackup_Store(t *testing.T) {
 			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
 				data := map[string]store.Data{}
 				fileStore.EXPECT().GetAll(gomock.Any()).Return(data, nil)
-				fileStore.EXPECT().DeleteAfterDay(gomock.Any(), last24Hours).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "success - today's events are not sealed",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{
+					"user:123": {
+						store.Date("2025-1-14"): userData[store.Date("2025-1-14")],
+						store.Date("2025-1-15"): {testEvent("user:123", fixedTime)},
+					},
+					"order:456": {store.Date("2025-1-15"): {testEvent("order:456", fixedTime)}},
+				}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{userObject}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, []store.ArchivedObject{userObject}).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("user:123"), userData).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -202,29 +195,34 @@ func TestBackup_Store(t *testing.T) {
 			expectedError: errors.New("failed to get data"),
 		},
 		{
-			name: "error - fileStore.DeleteAfterDay fails",
+			name: "error - failed key is kept and the others are archived",
 			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
-				data := map[string]store.Data{
-					"user:123": {
-						store.Date("2025-1-14"): []audit.DataAudit{
-							{
-								Metadata: audit.MetadataAudit{
-									Key:           "user:123",
-									EventName:     "user.created",
-									RequestID:     "req-123",
-									CorrelationID: "corr-123",
-									EventAt:       last24Hours,
-								},
-								Data: map[string]string{"name": "John"},
-							},
-						},
-					},
-				}
-				fileStore.EXPECT().GetAll(gomock.Any()).Return(data, nil)
-				s3Store.EXPECT().Save(gomock.Any(), "user:123", last24Hours, gomock.Any()).Return(nil)
-				fileStore.EXPECT().DeleteAfterDay(gomock.Any(), last24Hours).Return(errors.New("failed to delete"))
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{"user:123": userData, "order:456": orderData}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return(nil, errors.New("upload failed"))
+				s3Store.EXPECT().Save(gomock.Any(), "order:456", day, gomock.Any()).Return([]store.ArchivedObject{orderObject}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, []store.ArchivedObject{orderObject}).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("order:456"), orderData).Return(nil)
+			},
+			expectedError: errors.New("key user:123: upload failed"),
+		},
+		{
+			name: "error - nothing is deleted without the manifest",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{"user:123": userData}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{userObject}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, gomock.Any()).Return(errors.New("access denied"))
+			},
+			expectedError: errors.New("manifest 2025-01-14: access denied"),
+		},
+		{
+			name: "error - fileStore.Remove fails",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{"user:123": userData}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{userObject}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, gomock.Any()).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("user:123"), userData).Return(errors.New("failed to delete"))
 			},
-			expectedError: errors.New("failed to delete"),
+			expectedError: errors.New("key user:123: failed to delete"),
 		},
 	}
 
@@ -257,3 +255,147 @@ func TestBackup_Store(t *testing.T) {
 		})
 	}
 }
+
+func TestBackup_StoreHourly(t *testing.T) {
+	fixedTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	clock.SetNow(fixedTime)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	closed := testEvent("user:123", fixedTime.Add(-90*time.Minute))
+	// stored in the current hour, whatever event at the client sent
+	open := testEvent("user:123", fixedTime.Add(-3*time.Hour))
+	open.Metadata.ID = audit.NewID()
+	data := map[string]store.Data{"user:123": {store.Date("2025-1-15"): {closed, open}}}
+	sealed := store.Data{store.Date("2025-1-15"): {closed}}
+	part := store.ArchivedObject{Key: "user:123", Date: "2025-01-15", Path: "audits/key=user:123/date=2025-01-15/hour=08/part-0.jsonl", Records: 1}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store)
+		expectedError error
+	}{
+		{
+			name: "success - closed hours of today are archived",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(data, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, _ time.Time, payload []byte) ([]store.ArchivedObject, error) {
+						var shipped store.Data
+						if err := json.Unmarshal(payload, &shipped); err != nil || shipped.Len() != 1 {
+							t.Errorf("expected only the closed hour to be shipped, got %s", payload)
+						}
+						return []store.ArchivedObject{part}, nil
+					})
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, []store.ArchivedObject{part}).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("user:123"), sealed).Return(nil)
+			},
+		},
+		{
+			name: "success - events already in the partition are removed",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(data, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return(nil, nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("user:123"), sealed).Return(nil)
+			},
+		},
+		{
+			name: "error - nothing is deleted without the manifest",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(data, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{part}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, gomock.Any()).Return(errors.New("access denied"))
+			},
+			expectedError: errors.New("manifest 2025-01-15: access denied"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFileStore := mocks.NewMockFileStore(ctrl)
+			mockS3Store := mocks.NewMockS3Store(ctrl)
+			tt.setupMocks(mockFileStore, mockS3Store)
+
+			err := NewBackup(mockFileStore, mockS3Store).WithLayout(store.LayoutHourly).Store(context.Background())
+			if (err != nil) != (tt.expectedError != nil) || (err != nil && err.Error() != tt.expectedError.Error()) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestBackup_StoreFeedsSinks(t *testing.T) {
+	fixedTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	day := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+	last24Hours := fixedTime.Add(-24 * time.Hour)
+	clock.SetNow(fixedTime)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	event := audit.DataAudit{
+		Metadata: audit.MetadataAudit{
+			Key:           "user:123",
+			EventName:     "user.created",
+			RequestID:     "req-123",
+			CorrelationID: "corr-123",
+			EventAt:       last24Hours,
+		},
+		Data: map[string]string{"name": "John"},
+	}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store, sink *mocks.MockAuditSink)
+		expectedError bool
+	}{
+		{
+			name: "success - archived key is sent to sink",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store, sink *mocks.MockAuditSink) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{
+					"user:123": {store.Date("2025-1-14"): []audit.DataAudit{event}},
+				}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{{Path: "audits/user:123/2025-01-14.json"}}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, gomock.Any()).Return(nil)
+				fileStore.EXPECT().Remove(gomock.Any(), store.Key("user:123"), gomock.Any()).Return(nil)
+				sink.EXPECT().InsertBatch(gomock.Any(), []audit.DataAudit{event}).Return(nil)
+			},
+		},
+		{
+			name: "error - key the sink refuses stays local",
+			setupMocks: func(fileStore *mocks.MockFileStore, s3Store *mocks.MockS3Store, sink *mocks.MockAuditSink) {
+				fileStore.EXPECT().GetAll(gomock.Any()).Return(map[string]store.Data{
+					"user:123": {store.Date("2025-1-14"): []audit.DataAudit{event}},
+				}, nil)
+				s3Store.EXPECT().Save(gomock.Any(), "user:123", day, gomock.Any()).Return([]store.ArchivedObject{{Path: "audits/user:123/2025-01-14.json"}}, nil)
+				s3Store.EXPECT().RecordManifest(gomock.Any(), day, gomock.Any()).Return(nil)
+				sink.EXPECT().InsertBatch(gomock.Any(), gomock.Any()).Return(errors.New("database down"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFileStore := mocks.NewMockFileStore(ctrl)
+			mockS3Store := mocks.NewMockS3Store(ctrl)
+			mockSink := mocks.NewMockAuditSink(ctrl)
+
+			tt.setupMocks(mockFileStore, mockS3Store, mockSink)
+
+			backup := NewBackup(mockFileStore, mockS3Store).WithSinks(mockSink)
+			err := backup.Store(context.Background())
+			if (err != nil) != tt.expectedError {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -35,10 +35,15 @@ var ErrIdempotencyKeyAlreadyExists = fmt.Errorf("idempotency key already exists"
 
 func (fa *FileAudit) Save(ctx context.Context, input audit.DataAudit) (string, error) {
 	idepotency_key := fmt.Sprintf("%s-%s-%s-%s", input.Metadata.Key, input.Metadata.EventName, input.Metadata.RequestID, input.Metadata.CorrelationID)
+	// tenants may reuse the same keys and request ids
+	if input.Metadata.Tenant != "" {
+		idepotency_key = input.Metadata.Tenant + "/" + idepotency_key
+	}
 	if _, ok := fa.idempotencyStore.Get(idepotency_key); ok {
 		return "", ErrIdempotencyKeyAlreadyExists
 	}
 
+	input.Metadata.EnsureID()
 	if err := fa.auditStore.Upsert(ctx, input); err != nil {
 		return "", fmt.Errorf("failed to save data: %w", err)
 	}
@@ -35,7 +35,12 @@ func TestFileAudit_Save(t *testing.T) {
 			},
 			setupMocks: func(auditStore *mocks.MockAuditStore, idempotencyStore *mocks.MockIdempotencyStore) {
 				idempotencyStore.EXPECT().Get("user:123-user.created-req-123-corr-123").Return(time.Time{}, false)
-				auditStore.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+				auditStore.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) error {
+					if len(input.Metadata.ID) != 26 {
+						t.Errorf("expected the event to be stamped with an id, got %q", input.Metadata.ID)
+					}
+					return nil
+				})
 				idempotencyStore.EXPECT().Set("user:123-user.created-req-123-corr-123")
 			},
 			expectedIdempotency: "user:123-user.created-req-123-corr-123",
@@ -61,6 +66,27 @@ func TestFileAudit_Save(t *testing.T) {
 			expectedIdempotency: "order:456-order.completed-req-456-corr-456",
 			expectedError:       nil,
 		},
+		{
+			name: "success - tenant namespaces the idempotency key",
+			input: audit.DataAudit{
+				Metadata: audit.MetadataAudit{
+					Key:           "user:123",
+					EventName:     "user.created",
+					RequestID:     "req-123",
+					CorrelationID: "corr-123",
+					EventAt:       fixedTime,
+					Tenant:        "acme",
+				},
+				Data: map[string]string{"name": "John"},
+			},
+			setupMocks: func(auditStore *mocks.MockAuditStore, idempotencyStore *mocks.MockIdempotencyStore) {
+				idempotencyStore.EXPECT().Get("acme/user:123-user.created-req-123-corr-123").Return(time.Time{}, false)
+				auditStore.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+				idempotencyStore.EXPECT().Set("acme/user:123-user.created-req-123-corr-123")
+			},
+			expectedIdempotency: "acme/user:123-user.created-req-123-corr-123",
+			expectedError:       nil,
+		},
 		{
 			name: "error - idempotency key already exists",
 			input: audit.DataAudit{
@@ -3,13 +3,15 @@ package backup
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/url"
 	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/IsaacDSC/auditory/internal/audit"
 	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
 	"github.com/IsaacDSC/auditory/pkg/ctxkey"
 	"github.com/IsaacDSC/auditory/pkg/mu"
 )
@@ -18,6 +20,7 @@ const (
 	XRequestID     = "X-Request-ID"
 	XCorrelationID = "X-Correlation-ID"
 	XClientID      = "X-Client-ID"
+	XTenantID      = "X-Tenant-ID"
 )
 
 type HttpAuditStore interface {
@@ -28,6 +31,8 @@ type HttpOnCallService struct {
 	store         HttpAuditStore
 	memEventStore map[string]audit.RequestAudit
 	mu            mu.MutexByKey
+	pending       atomic.Int64
+	redactions    map[string][]string
 }
 
 func NewHttpOnCallService(store HttpAuditStore) *HttpOnCallService {
@@ -38,6 +43,16 @@ func NewHttpOnCallService(store HttpAuditStore) *HttpOnCallService {
 	}
 }
 
+// WithRedactions masks, for the exchanges of a tenant, its own headers and
+// query parameters ("|" separated) instead of AppConfig.ReplacedAudit.
+func (h *HttpOnCallService) WithRedactions(redactions map[string]string) *HttpOnCallService {
+	h.redactions = make(map[string][]string, len(redactions))
+	for tenant, names := range redactions {
+		h.redactions[tenant] = strings.Split(strings.ToLower(names), "|")
+	}
+	return h
+}
+
 func (h *HttpOnCallService) EnqueueRequest(ctx context.Context, input audit.RequestAudit) error {
 	clientID, err := getValue(input.Headers, XClientID)
 	if err != nil {
@@ -60,8 +75,14 @@ func (h *HttpOnCallService) EnqueueRequest(ctx context.Context, input audit.Requ
 
 	ctx = ctxkey.SetCorrelationID(ctx, correlationID)
 
-	input.Headers = sanitizeHeaders(input.Headers)
-	input.Query = sanitizeQueryParams(input.Query)
+	tenant, _ := getValue(input.Headers, XTenantID)
+	replacements := h.replacements(tenant)
+	input.Headers = sanitizeHeaders(input.Headers, replacements)
+	input.Query = sanitizeQueryParams(input.Query, replacements)
+	if _, pending := h.memEventStore[requestID]; !pending {
+		h.pending.Add(1)
+		metrics.PendingRequests.Inc()
+	}
 	h.memEventStore[requestID] = input
 
 	return nil
@@ -84,31 +105,57 @@ func (h *HttpOnCallService) EnqueueResponse(ctx context.Context, input audit.Res
 		return fmt.Errorf("request not found for requestID: %s", requestID)
 	}
 
+	traceID, spanID := telemetry.IDs(ctx)
 	correlationID, err := getValue(input.RequestHeaders, XCorrelationID)
 	if err != nil {
 		correlationID = "unknown"
+		if traceID != "" {
+			correlationID = traceID
+		}
 	}
 
+	ctx = ctxkey.SetClientID(ctx, clientID)
+	ctx = ctxkey.SetAuditKey(ctx, clientID)
+	ctx = ctxkey.SetRequestID(ctx, requestID)
+	ctx = ctxkey.SetCorrelationID(ctx, correlationID)
+
+	// the tenant store refuses tenants it does not know
+	tenant, _ := getValue(input.RequestHeaders, XTenantID)
+	ctx = ctxkey.SetTenant(ctx, tenant)
+
 	if err := h.store.Upsert(ctx, audit.DataAudit{
 		Metadata: audit.MetadataAudit{
+			ID:            audit.NewID(),
+			Tenant:        tenant,
 			Key:           clientID,
-			EventName:     "http_audit",
+			EventName:     audit.HttpAuditEvent,
 			RequestID:     requestID,
 			CorrelationID: correlationID,
+			TraceID:       traceID,
+			SpanID:        spanID,
 		},
 		Data: audit.HttpAudit{
 			Request:  request,
 			Response: input,
 		},
 	}); err != nil {
+		metrics.ObserveAudit(clientID, metrics.ResultFailed)
 		return fmt.Errorf("failed to save data: %w", err)
 	}
+	metrics.ObserveAudit(clientID, metrics.ResultAccepted)
 
-	delete(h.memEventStore, clientID)
+	delete(h.memEventStore, requestID)
+	h.pending.Add(-1)
+	metrics.PendingRequests.Dec()
 
 	return nil
 }
 
+// Pending returns how many requests wait for their response.
+func (h *HttpOnCallService) Pending() (int64, error) {
+	return h.pending.Load(), nil
+}
+
 func getValue(headers map[string][]string, headerKey string) (string, error) {
 	// Busca case-insensitive para headers HTTP
 	for key, values := range headers {
@@ -121,13 +168,20 @@ func getValue(headers map[string][]string, headerKey string) (string, error) {
 	return "", fmt.Errorf("%s header is required", headerKey)
 }
 
-func sanitizeHeaders(headers map[string][]string) map[string][]string {
+// replacements are the lower case names masked in the exchanges of tenant.
+func (h *HttpOnCallService) replacements(tenant string) []string {
+	if names, ok := h.redactions[tenant]; ok && tenant != "" {
+		return names
+	}
+
 	conf := cfg.GetConfig()
 	if conf == nil {
-		return headers
+		return nil
 	}
-	listReplacements := strings.Split(strings.ToLower(conf.AppConfig.ReplacedAudit), ",")
+	return strings.Split(strings.ToLower(conf.AppConfig.ReplacedAudit), ",")
+}
 
+func sanitizeHeaders(headers map[string][]string, listReplacements []string) map[string][]string {
 	for key, value := range headers {
 		if slices.Contains(listReplacements, strings.ToLower(key)) {
 			headers[key] = []string{strings.Repeat("*", len(value[0]))}
@@ -136,23 +190,21 @@ func sanitizeHeaders(headers map[string][]string) map[string][]string {
 	return headers
 }
 
-func sanitizeQueryParams(queryParams string) string {
-	conf := cfg.GetConfig()
-	if conf == nil {
+func sanitizeQueryParams(queryParams string, listReplacements []string) string {
+	if len(listReplacements) == 0 {
 		return queryParams
 	}
-	listReplacements := strings.Split(strings.ToLower(conf.AppConfig.ReplacedAudit), ",")
 
-	queryUrl, err := url.Parse(queryParams)
+	values, err := url.ParseQuery(queryParams)
 	if err != nil {
-		log.Printf("failed to parse query params: %v", err)
+		logger.Warn("failed to parse query params", "error", err)
 		return ""
 	}
 
-	for key, value := range queryUrl.Query() {
+	for key, value := range values {
 		if slices.Contains(listReplacements, strings.ToLower(key)) {
-			queryUrl.Query()[key] = []string{strings.Repeat("*", len(value[0]))}
+			values[key] = []string{strings.Repeat("*", len(value[0]))}
 		}
 	}
-	return queryUrl.String()
+	return values.Encode()
 }
@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHttpOnCallService_Tenant(t *testing.T) {
+	tests := []struct {
+		name           string
+		tenant         string
+		expectedHeader string
+		expectedQuery  string
+	}{
+		{
+			name:           "success - tenant redactions mask its headers and query",
+			tenant:         "acme",
+			expectedHeader: "*****",
+			expectedQuery:  "page=1&session=%2A%2A%2A",
+		},
+		{
+			name:           "success - other tenants keep their exchange as sent",
+			tenant:         "globex",
+			expectedHeader: "t-123",
+			expectedQuery:  "page=1&session=abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			headers := map[string][]string{
+				XClientID:   {"client-1"},
+				XRequestID:  {"req-1"},
+				XTenantID:   {tt.tenant},
+				"X-Api-Key": {"t-123"},
+			}
+
+			store := mocks.NewMockAuditStore(ctrl)
+			store.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) error {
+				if input.Metadata.Tenant != tt.tenant {
+					t.Errorf("expected tenant %s, got %q", tt.tenant, input.Metadata.Tenant)
+				}
+				request := input.Data.(audit.HttpAudit).Request
+				if got := request.Headers["X-Api-Key"][0]; got != tt.expectedHeader {
+					t.Errorf("expected header %s, got %s", tt.expectedHeader, got)
+				}
+				if request.Query != tt.expectedQuery {
+					t.Errorf("expected query %s, got %s", tt.expectedQuery, request.Query)
+				}
+				return nil
+			})
+
+			svc := NewHttpOnCallService(store).WithRedactions(map[string]string{"acme": "X-Api-Key|session"})
+			ctx := context.Background()
+			if err := svc.EnqueueRequest(ctx, audit.RequestAudit{Headers: headers, Query: "page=1&session=abc"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := svc.EnqueueResponse(ctx, audit.ResponseAudit{RequestHeaders: headers}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package backup
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("backup")
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: archive_reconciliation.go
+//
+// Generated by this command:
+//
+//	mockgen -source=archive_reconciliation.go -destination=mocks/mock_archive_reconciliation.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	store "github.com/IsaacDSC/auditory/internal/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchiveAuditor is a mock of ArchiveAuditor interface.
+type MockArchiveAuditor struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveAuditorMockRecorder
+	isgomock struct{}
+}
+
+// MockArchiveAuditorMockRecorder is the mock recorder for MockArchiveAuditor.
+type MockArchiveAuditorMockRecorder struct {
+	mock *MockArchiveAuditor
+}
+
+// NewMockArchiveAuditor creates a new mock instance.
+func NewMockArchiveAuditor(ctrl *gomock.Controller) *MockArchiveAuditor {
+	mock := &MockArchiveAuditor{ctrl: ctrl}
+	mock.recorder = &MockArchiveAuditorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchiveAuditor) EXPECT() *MockArchiveAuditorMockRecorder {
+	return m.recorder
+}
+
+// Reconcile mocks base method.
+func (m *MockArchiveAuditor) Reconcile(ctx context.Context, day time.Time) (store.ArchiveReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, day)
+	ret0, _ := ret[0].(store.ArchiveReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockArchiveAuditorMockRecorder) Reconcile(ctx, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockArchiveAuditor)(nil).Reconcile), ctx, day)
+}
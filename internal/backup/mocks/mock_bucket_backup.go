@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/backup/bucket_backup.go
+// Source: bucket_backup.go
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/backup/bucket_backup.go -destination=internal/backup/mocks/mock_bucket_backup.go -package=mocks
+//	mockgen -source=bucket_backup.go -destination=mocks/mock_bucket_backup.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	audit "github.com/IsaacDSC/auditory/internal/audit"
 	store "github.com/IsaacDSC/auditory/internal/store"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,20 +43,6 @@ func (m *MockFileStore) EXPECT() *MockFileStoreMockRecorder {
 	return m.recorder
 }
 
-// DeleteAfterDay mocks base method.
-func (m *MockFileStore) DeleteAfterDay(ctx context.Context, timeNow time.Time) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAfterDay", ctx, timeNow)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// DeleteAfterDay indicates an expected call of DeleteAfterDay.
-func (mr *MockFileStoreMockRecorder) DeleteAfterDay(ctx, timeNow any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAfterDay", reflect.TypeOf((*MockFileStore)(nil).DeleteAfterDay), ctx, timeNow)
-}
-
 // GetAll mocks base method.
 func (m *MockFileStore) GetAll(ctx context.Context) (map[string]store.Data, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +58,20 @@ func (mr *MockFileStoreMockRecorder) GetAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockFileStore)(nil).GetAll), ctx)
 }
 
+// Remove mocks base method.
+func (m *MockFileStore) Remove(ctx context.Context, key store.Key, sealed store.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, key, sealed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockFileStoreMockRecorder) Remove(ctx, key, sealed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFileStore)(nil).Remove), ctx, key, sealed)
+}
+
 // MockS3Store is a mock of S3Store interface.
 type MockS3Store struct {
 	ctrl     *gomock.Controller
@@ -109,16 +110,69 @@ func (mr *MockS3StoreMockRecorder) Backup(ctx, timeNow, data any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockS3Store)(nil).Backup), ctx, timeNow, data)
 }
 
-// Save mocks base method.
-func (m *MockS3Store) Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) error {
+// RecordManifest mocks base method.
+func (m *MockS3Store) RecordManifest(ctx context.Context, day time.Time, objects []store.ArchivedObject) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", ctx, dataKey, timeNow, data)
+	ret := m.ctrl.Call(m, "RecordManifest", ctx, day, objects)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// RecordManifest indicates an expected call of RecordManifest.
+func (mr *MockS3StoreMockRecorder) RecordManifest(ctx, day, objects any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordManifest", reflect.TypeOf((*MockS3Store)(nil).RecordManifest), ctx, day, objects)
+}
+
+// Save mocks base method.
+func (m *MockS3Store) Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) ([]store.ArchivedObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, dataKey, timeNow, data)
+	ret0, _ := ret[0].([]store.ArchivedObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Save indicates an expected call of Save.
 func (mr *MockS3StoreMockRecorder) Save(ctx, dataKey, timeNow, data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockS3Store)(nil).Save), ctx, dataKey, timeNow, data)
 }
+
+// MockAuditSink is a mock of AuditSink interface.
+type MockAuditSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditSinkMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditSinkMockRecorder is the mock recorder for MockAuditSink.
+type MockAuditSinkMockRecorder struct {
+	mock *MockAuditSink
+}
+
+// NewMockAuditSink creates a new mock instance.
+func NewMockAuditSink(ctrl *gomock.Controller) *MockAuditSink {
+	mock := &MockAuditSink{ctrl: ctrl}
+	mock.recorder = &MockAuditSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditSink) EXPECT() *MockAuditSinkMockRecorder {
+	return m.recorder
+}
+
+// InsertBatch mocks base method.
+func (m *MockAuditSink) InsertBatch(ctx context.Context, input []audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBatch", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertBatch indicates an expected call of InsertBatch.
+func (mr *MockAuditSinkMockRecorder) InsertBatch(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBatch", reflect.TypeOf((*MockAuditSink)(nil).InsertBatch), ctx, input)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: restore.go
+//
+// Generated by this command:
+//
+//	mockgen -source=restore.go -destination=mocks/mock_restore.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	store "github.com/IsaacDSC/auditory/internal/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchiveReader is a mock of ArchiveReader interface.
+type MockArchiveReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockArchiveReaderMockRecorder is the mock recorder for MockArchiveReader.
+type MockArchiveReaderMockRecorder struct {
+	mock *MockArchiveReader
+}
+
+// NewMockArchiveReader creates a new mock instance.
+func NewMockArchiveReader(ctrl *gomock.Controller) *MockArchiveReader {
+	mock := &MockArchiveReader{ctrl: ctrl}
+	mock.recorder = &MockArchiveReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchiveReader) EXPECT() *MockArchiveReaderMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockArchiveReader) Get(ctx context.Context, path string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, path)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockArchiveReaderMockRecorder) Get(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArchiveReader)(nil).Get), ctx, path)
+}
+
+// List mocks base method.
+func (m *MockArchiveReader) List(ctx context.Context, prefix string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, prefix)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockArchiveReaderMockRecorder) List(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArchiveReader)(nil).List), ctx, prefix)
+}
+
+// MockRestoreStore is a mock of RestoreStore interface.
+type MockRestoreStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRestoreStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRestoreStoreMockRecorder is the mock recorder for MockRestoreStore.
+type MockRestoreStoreMockRecorder struct {
+	mock *MockRestoreStore
+}
+
+// NewMockRestoreStore creates a new mock instance.
+func NewMockRestoreStore(ctrl *gomock.Controller) *MockRestoreStore {
+	mock := &MockRestoreStore{ctrl: ctrl}
+	mock.recorder = &MockRestoreStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRestoreStore) EXPECT() *MockRestoreStoreMockRecorder {
+	return m.recorder
+}
+
+// Merge mocks base method.
+func (m *MockRestoreStore) Merge(ctx context.Context, key store.Key, data store.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockRestoreStoreMockRecorder) Merge(ctx, key, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockRestoreStore)(nil).Merge), ctx, key, data)
+}
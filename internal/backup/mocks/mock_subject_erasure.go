@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/backup/subject_erasure.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/backup/subject_erasure.go -destination=internal/backup/mocks/mock_subject_erasure.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockErasureKeyStore is a mock of ErasureKeyStore interface.
+type MockErasureKeyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockErasureKeyStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockErasureKeyStoreMockRecorder is the mock recorder for MockErasureKeyStore.
+type MockErasureKeyStoreMockRecorder struct {
+	mock *MockErasureKeyStore
+}
+
+// NewMockErasureKeyStore creates a new mock instance.
+func NewMockErasureKeyStore(ctrl *gomock.Controller) *MockErasureKeyStore {
+	mock := &MockErasureKeyStore{ctrl: ctrl}
+	mock.recorder = &MockErasureKeyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockErasureKeyStore) EXPECT() *MockErasureKeyStoreMockRecorder {
+	return m.recorder
+}
+
+// Destroy mocks base method.
+func (m *MockErasureKeyStore) Destroy(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Destroy", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Destroy indicates an expected call of Destroy.
+func (mr *MockErasureKeyStoreMockRecorder) Destroy(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Destroy", reflect.TypeOf((*MockErasureKeyStore)(nil).Destroy), key)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tenant_backup.go
+//
+// Generated by this command:
+//
+//	mockgen -source=tenant_backup.go -destination=mocks/mock_tenant_backup.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchival is a mock of Archival interface.
+type MockArchival struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchivalMockRecorder
+	isgomock struct{}
+}
+
+// MockArchivalMockRecorder is the mock recorder for MockArchival.
+type MockArchivalMockRecorder struct {
+	mock *MockArchival
+}
+
+// NewMockArchival creates a new mock instance.
+func NewMockArchival(ctrl *gomock.Controller) *MockArchival {
+	mock := &MockArchival{ctrl: ctrl}
+	mock.recorder = &MockArchivalMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchival) EXPECT() *MockArchivalMockRecorder {
+	return m.recorder
+}
+
+// Backup mocks base method.
+func (m *MockArchival) Backup(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockArchivalMockRecorder) Backup(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockArchival)(nil).Backup), ctx)
+}
+
+// Store mocks base method.
+func (m *MockArchival) Store(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockArchivalMockRecorder) Store(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockArchival)(nil).Store), ctx)
+}
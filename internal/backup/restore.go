@@ -0,0 +1,404 @@
+package backup
+
+//go:generate mockgen -source=restore.go -destination=mocks/mock_restore.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// SourceObjects restores the per key objects Store archived.
+	SourceObjects = "objects"
+	// SourceSnapshots restores the daily Backup snapshots, which also hold
+	// what was never archived by Store.
+	SourceSnapshots = "snapshots"
+)
+
+// Replay sinks.
+const (
+	SinkSQL      = "sql"
+	SinkBroker   = "broker"
+	SinkWebhooks = "webhooks"
+)
+
+var (
+	ErrInvalidRestore = errors.New("invalid restore request")
+	ErrReplayRunning  = errors.New("a replay is already running")
+)
+
+type ArchiveReader interface {
+	Get(ctx context.Context, path string) ([]byte, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+type RestoreStore interface {
+	Merge(ctx context.Context, key store.Key, data store.Data) error
+}
+
+// AuditSinkFunc adapts a function to AuditSink.
+type AuditSinkFunc func(ctx context.Context, input []audit.DataAudit) error
+
+func (f AuditSinkFunc) InsertBatch(ctx context.Context, input []audit.DataAudit) error {
+	return f(ctx, input)
+}
+
+// RestoreRequest selects the archived audits of Keys, every key when empty,
+// filed from From to To, both days included.
+type RestoreRequest struct {
+	Keys   []string  `json:"keys,omitempty"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Source string    `json:"source"`
+}
+
+type RestoreResult struct {
+	Keys   int `json:"keys"`
+	Events int `json:"events"`
+}
+
+// ReplayRequest re-emits the audits RestoreRequest selects to Sinks, every
+// configured sink when empty, at Rate events per second; 0 keeps the
+// configured rate.
+type ReplayRequest struct {
+	RestoreRequest
+	Sinks []string `json:"sinks,omitempty"`
+	Rate  float64  `json:"rate,omitempty"`
+}
+
+type ReplayStatus struct {
+	Running    bool           `json:"running"`
+	Request    *ReplayRequest `json:"request,omitempty"`
+	Replayed   int            `json:"replayed"`
+	StartedAt  time.Time      `json:"started_at,omitzero"`
+	FinishedAt time.Time      `json:"finished_at,omitzero"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Restore brings archived audits back: into the local store, so they are
+// served and archived again, or replayed to the sinks to rebuild a
+// downstream system. Both merge by event, so running one twice is harmless
+// for the local store and the SQL sink.
+type Restore struct {
+	archive   ArchiveReader
+	store     RestoreStore
+	tenant    string
+	sinks     map[string]AuditSink
+	rate      float64
+	batchSize int
+
+	mu     sync.Mutex
+	status ReplayStatus
+}
+
+func NewRestore(archive ArchiveReader, restoreStore RestoreStore, rate float64, batchSize int) *Restore {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &Restore{
+		archive:   archive,
+		store:     restoreStore,
+		sinks:     make(map[string]AuditSink),
+		rate:      rate,
+		batchSize: batchSize,
+	}
+}
+
+// WithTenant keeps the audits of tenant only, should its archive storage hold
+// the audits of others.
+func (r *Restore) WithTenant(tenant string) *Restore {
+	r.tenant = tenant
+	return r
+}
+
+// WithReplaySink makes sink available to Replay under name.
+func (r *Restore) WithReplaySink(name string, sink AuditSink) *Restore {
+	r.sinks[name] = sink
+	return r
+}
+
+func (r *Restore) Restore(ctx context.Context, req RestoreRequest) (RestoreResult, error) {
+	if err := validateRestore(req); err != nil {
+		return RestoreResult{}, err
+	}
+
+	var result RestoreResult
+	err := r.read(ctx, req, func(key string, data store.Data) error {
+		if err := r.store.Merge(ctx, store.Key(key), data); err != nil {
+			return fmt.Errorf("failed to restore key %s: %w", key, err)
+		}
+		result.Keys++
+		result.Events += data.Len()
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	logger.InfoContext(ctx, "restored archived audits", "source", req.Source, "keys", result.Keys, "events", result.Events)
+	return result, nil
+}
+
+// StartReplay validates req and replays it in the background; ReplayStatus
+// follows its progress. One replay runs at a time.
+func (r *Restore) StartReplay(ctx context.Context, req ReplayRequest) error {
+	sinks, err := r.replaySinks(req)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status.Running {
+		return ErrReplayRunning
+	}
+	r.status = ReplayStatus{Running: true, Request: &req, StartedAt: clock.Now()}
+
+	go func() {
+		err := r.replay(ctx, req, sinks)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.status.Running = false
+		r.status.FinishedAt = clock.Now()
+		if err != nil {
+			logger.ErrorContext(ctx, "replay failed", "replayed", r.status.Replayed, "error", err)
+			r.status.Error = err.Error()
+			return
+		}
+		logger.InfoContext(ctx, "replayed archived audits", "replayed", r.status.Replayed)
+	}()
+
+	return nil
+}
+
+func (r *Restore) ReplayStatus() ReplayStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// replay sends each key's events in event order, in batches paced by the
+// limiter. It stops at the first sink failure: the status tells how far it
+// got, and the sinks that deduplicate can be replayed again from the start.
+func (r *Restore) replay(ctx context.Context, req ReplayRequest, sinks map[string]AuditSink) error {
+	limit, burst := rate.Inf, r.batchSize
+	switch {
+	case req.Rate > 0:
+		limit = rate.Limit(req.Rate)
+	case r.rate > 0:
+		limit = rate.Limit(r.rate)
+	}
+	limiter := rate.NewLimiter(limit, burst)
+
+	return r.read(ctx, req.RestoreRequest, func(key string, data store.Data) error {
+		var events []audit.DataAudit
+		for _, dateEvents := range data {
+			events = append(events, dateEvents...)
+		}
+		sort.Slice(events, func(i, j int) bool {
+			return events[i].Metadata.EventAt.Before(events[j].Metadata.EventAt)
+		})
+
+		for start := 0; start < len(events); start += r.batchSize {
+			batch := events[start:min(start+r.batchSize, len(events))]
+			if err := limiter.WaitN(ctx, len(batch)); err != nil {
+				return err
+			}
+
+			for name, sink := range sinks {
+				if err := sink.InsertBatch(ctx, batch); err != nil {
+					return fmt.Errorf("sink %s failed on key %s: %w", name, key, err)
+				}
+			}
+
+			r.mu.Lock()
+			r.status.Replayed += len(batch)
+			r.mu.Unlock()
+		}
+		return nil
+	})
+}
+
+func (r *Restore) replaySinks(req ReplayRequest) (map[string]AuditSink, error) {
+	if err := validateRestore(req.RestoreRequest); err != nil {
+		return nil, err
+	}
+	if req.Rate < 0 {
+		return nil, fmt.Errorf("%w: rate must not be negative", ErrInvalidRestore)
+	}
+	if len(r.sinks) == 0 {
+		return nil, fmt.Errorf("%w: no replay sink is configured", ErrInvalidRestore)
+	}
+	if len(req.Sinks) == 0 {
+		return r.sinks, nil
+	}
+
+	sinks := make(map[string]AuditSink, len(req.Sinks))
+	for _, name := range req.Sinks {
+		sink, ok := r.sinks[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sink %q", ErrInvalidRestore, name)
+		}
+		sinks[name] = sink
+	}
+	return sinks, nil
+}
+
+func validateRestore(req RestoreRequest) error {
+	switch {
+	case req.From.IsZero() || req.To.IsZero():
+		return fmt.Errorf("%w: from and to are required", ErrInvalidRestore)
+	case req.To.Before(req.From):
+		return fmt.Errorf("%w: to is before from", ErrInvalidRestore)
+	case req.Source != SourceObjects && req.Source != SourceSnapshots:
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidRestore, req.Source)
+	}
+	return nil
+}
+
+// read calls fn with the archived events of each selected key, keeping the
+// days in the range and the events of the tenant only.
+func (r *Restore) read(ctx context.Context, req RestoreRequest, fn func(key string, data store.Data) error) error {
+	from, to := req.From.Format(time.DateOnly), req.To.Format(time.DateOnly)
+	inRange := func(day string) bool { return day >= from && day <= to }
+	wanted := func(key string) bool { return len(req.Keys) == 0 || slices.Contains(req.Keys, key) }
+	fn = r.ownEvents(fn)
+
+	if req.Source == SourceSnapshots {
+		return r.readSnapshots(ctx, req, wanted, inRange, fn)
+	}
+	return r.readObjects(ctx, wanted, inRange, fn)
+}
+
+// readObjects reads the objects Store archived, in the daily and the hourly
+// layouts.
+func (r *Restore) readObjects(ctx context.Context, wanted func(string) bool, inRange func(string) bool, fn func(string, store.Data) error) error {
+	paths, err := r.archive.List(ctx, "audits/")
+	if err != nil {
+		return fmt.Errorf("failed to list archives: %w", err)
+	}
+
+	for _, path := range paths {
+		key, day, ok := store.ParseArchivePath(path)
+		if !ok || !wanted(key) || !inRange(day.Format(time.DateOnly)) {
+			continue
+		}
+
+		data, err := r.get(ctx, path, day)
+		if err != nil {
+			return err
+		}
+		if data = filterDays(data, inRange); len(data) > 0 {
+			if err := fn(key, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// readSnapshots reads the audits/{date}.json backups, and the
+// audits/{date}.{node}.json ones of cluster nodes, of each day in the range.
+// A key found in several snapshots is handed over once per snapshot.
+func (r *Restore) readSnapshots(ctx context.Context, req RestoreRequest, wanted func(string) bool, inRange func(string) bool, fn func(string, store.Data) error) error {
+	for day := req.From; !day.After(req.To); day = day.AddDate(0, 0, 1) {
+		prefix := "audits/" + day.Format(time.DateOnly) + "."
+		paths, err := r.archive.List(ctx, prefix)
+		if err != nil {
+			return fmt.Errorf("failed to list snapshots: %w", err)
+		}
+
+		for _, path := range paths {
+			if strings.Contains(strings.TrimPrefix(path, "audits/"), "/") {
+				continue
+			}
+
+			payload, err := r.archive.Get(ctx, path)
+			if err != nil {
+				return fmt.Errorf("failed to read snapshot %s: %w", path, err)
+			}
+			var snapshot map[string]store.Data
+			if err := json.Unmarshal(payload, &snapshot); err != nil {
+				return fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+			}
+
+			keys := make([]string, 0, len(snapshot))
+			for key := range snapshot {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if !wanted(key) {
+					continue
+				}
+				if data := filterDays(snapshot[key], inRange); len(data) > 0 {
+					if err := fn(key, data); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Restore) get(ctx context.Context, path string, day time.Time) (store.Data, error) {
+	payload, err := r.archive.Get(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", path, err)
+	}
+
+	data, err := store.DecodeArchive(path, day, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode archive %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ownEvents hands fn the events of the tenant of r only.
+func (r *Restore) ownEvents(fn func(string, store.Data) error) func(string, store.Data) error {
+	return func(key string, data store.Data) error {
+		own := make(store.Data, len(data))
+		for date, events := range data {
+			for _, event := range events {
+				if event.Metadata.Tenant == r.tenant {
+					own[date] = append(own[date], event)
+				}
+			}
+		}
+		if len(own) == 0 {
+			return nil
+		}
+		return fn(key, own)
+	}
+}
+
+// filterDays keeps the days of data in the range; dates are filed unpadded,
+// so they are compared in the padded form.
+func filterDays(data store.Data, inRange func(string) bool) store.Data {
+	filtered := make(store.Data, len(data))
+	for date, events := range data {
+		day, err := date.Time()
+		if err != nil || !inRange(day.Format(time.DateOnly)) {
+			continue
+		}
+		filtered[date] = events
+	}
+	return filtered
+}
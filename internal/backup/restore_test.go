@@ -0,0 +1,240 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup/mocks"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestArchive fills a local archive with the objects of user:1 on the 14th
+// and the 15th, of user:2 on the 14th, an hourly part of user:4 on the 15th, a
+// manifest and the snapshot of the 15th.
+func newTestArchive(t *testing.T) store.ObjectStorage {
+	t.Helper()
+
+	storage, err := store.NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	ctx := context.Background()
+	put := func(path string, value any) {
+		payload, _ := json.Marshal(value)
+		if err := storage.Put(ctx, path, payload, time.Time{}); err != nil {
+			t.Fatalf("failed to put %s: %v", path, err)
+		}
+	}
+	put("audits/user:1/2025-01-14.json", store.Data{"2025-1-14": {testEvent("user:1", day14.Add(2*time.Hour)), testEvent("user:1", day14.Add(time.Hour))}})
+	put("audits/user:1/2025-01-15.json", store.Data{"2025-1-15": {testEvent("user:1", day15)}})
+	put("audits/user:2/2025-01-14.json", store.Data{"2025-1-14": {testEvent("user:2", day14)}})
+	part, _ := json.Marshal(testEvent("user:4", day15.Add(9*time.Hour)))
+	if err := storage.Put(ctx, "audits/key=user:4/date=2025-01-15/hour=09/part-0.jsonl", append(part, '\n'), time.Time{}); err != nil {
+		t.Fatalf("failed to put part: %v", err)
+	}
+	put("audits/_manifests/2025-01-14.json", store.Manifest{Date: "2025-01-14"})
+	put("audits/2025-01-15.json", map[string]store.Data{
+		"user:1": {"2025-1-15": {testEvent("user:1", day15.Add(time.Hour))}},
+		"user:3": {"2025-1-15": {testEvent("user:3", day15)}},
+	})
+
+	return storage
+}
+
+var (
+	day14 = time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+	day15 = time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+)
+
+func TestRestore_Restore(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            RestoreRequest
+		setupMocks     func(restoreStore *mocks.MockRestoreStore)
+		expectedResult RestoreResult
+		expectedError  error
+	}{
+		{
+			name: "success - restores the objects of a key in the range",
+			req:  RestoreRequest{Keys: []string{"user:1"}, From: day14, To: day14, Source: SourceObjects},
+			setupMocks: func(restoreStore *mocks.MockRestoreStore) {
+				restoreStore.EXPECT().Merge(gomock.Any(), store.Key("user:1"), gomock.Len(1)).Return(nil)
+			},
+			expectedResult: RestoreResult{Keys: 1, Events: 2},
+		},
+		{
+			name: "success - restores every key",
+			req:  RestoreRequest{From: day14, To: day15, Source: SourceObjects},
+			setupMocks: func(restoreStore *mocks.MockRestoreStore) {
+				restoreStore.EXPECT().Merge(gomock.Any(), store.Key("user:1"), gomock.Any()).Return(nil).Times(2)
+				restoreStore.EXPECT().Merge(gomock.Any(), store.Key("user:2"), gomock.Any()).Return(nil)
+				restoreStore.EXPECT().Merge(gomock.Any(), store.Key("user:4"), gomock.Any()).Return(nil)
+			},
+			expectedResult: RestoreResult{Keys: 4, Events: 5},
+		},
+		{
+			name: "success - restores the hourly parts of a key",
+			req:  RestoreRequest{Keys: []string{"user:4"}, From: day15, To: day15, Source: SourceObjects},
+			setupMocks: func(restoreStore *mocks.MockRestoreStore) {
+				restoreStore.EXPECT().Merge(gomock.Any(), store.Key("user:4"), gomock.Len(1)).Return(nil)
+			},
+			expectedResult: RestoreResult{Keys: 1, Events: 1},
+		},
+		{
+			name: "success - restores a key from the snapshots",
+			req:  RestoreRequest{Keys: []string{"user:3"}, From: day14, To: day15, Source: SourceSnapshots},
+			setupMocks: func(restoreStore *mocks.MockRestoreStore) {
+				restoreStore.EXPECT().Merge(gomock.Any(), store.Key("user:3"), gomock.Len(1)).Return(nil)
+			},
+			expectedResult: RestoreResult{Keys: 1, Events: 1},
+		},
+		{
+			name:          "error - to before from",
+			req:           RestoreRequest{From: day15, To: day14, Source: SourceObjects},
+			setupMocks:    func(restoreStore *mocks.MockRestoreStore) {},
+			expectedError: ErrInvalidRestore,
+		},
+		{
+			name:          "error - unknown source",
+			req:           RestoreRequest{From: day14, To: day14, Source: "tape"},
+			setupMocks:    func(restoreStore *mocks.MockRestoreStore) {},
+			expectedError: ErrInvalidRestore,
+		},
+		{
+			name: "error - local store fails",
+			req:  RestoreRequest{Keys: []string{"user:2"}, From: day14, To: day14, Source: SourceObjects},
+			setupMocks: func(restoreStore *mocks.MockRestoreStore) {
+				restoreStore.EXPECT().Merge(gomock.Any(), store.Key("user:2"), gomock.Any()).Return(errDiskFull)
+			},
+			expectedError: errDiskFull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			restoreStore := mocks.NewMockRestoreStore(ctrl)
+			tt.setupMocks(restoreStore)
+
+			result, err := NewRestore(newTestArchive(t), restoreStore, 0, 10).Restore(context.Background(), tt.req)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && result != tt.expectedResult {
+				t.Errorf("expected %+v, got %+v", tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestRestore_Replay(t *testing.T) {
+	req := ReplayRequest{RestoreRequest: RestoreRequest{Keys: []string{"user:1"}, From: day14, To: day14, Source: SourceObjects}}
+
+	tests := []struct {
+		name          string
+		req           ReplayRequest
+		setupMocks    func(sql, broker *mocks.MockAuditSink)
+		expectedError error
+		expected      ReplayStatus
+	}{
+		{
+			name: "success - replays in event order to the selected sinks",
+			req:  ReplayRequest{RestoreRequest: req.RestoreRequest, Sinks: []string{SinkSQL}},
+			setupMocks: func(sql, broker *mocks.MockAuditSink) {
+				sql.EXPECT().InsertBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input []audit.DataAudit) error {
+					if len(input) != 1 || !input[0].Metadata.EventAt.Equal(day14.Add(time.Hour)) {
+						t.Errorf("expected the earliest event first, got %v", input)
+					}
+					return nil
+				})
+				sql.EXPECT().InsertBatch(gomock.Any(), gomock.Len(1)).Return(nil)
+			},
+			expected: ReplayStatus{Replayed: 2},
+		},
+		{
+			name: "success - every sink by default",
+			req:  req,
+			setupMocks: func(sql, broker *mocks.MockAuditSink) {
+				sql.EXPECT().InsertBatch(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				broker.EXPECT().InsertBatch(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			expected: ReplayStatus{Replayed: 2},
+		},
+		{
+			name: "error - sink failure stops the replay",
+			req:  req,
+			setupMocks: func(sql, broker *mocks.MockAuditSink) {
+				sql.EXPECT().InsertBatch(gomock.Any(), gomock.Any()).Return(errDiskFull).MaxTimes(1)
+				broker.EXPECT().InsertBatch(gomock.Any(), gomock.Any()).Return(errDiskFull).MaxTimes(1)
+			},
+			expected: ReplayStatus{Error: "disk full"},
+		},
+		{
+			name:          "error - unknown sink",
+			req:           ReplayRequest{RestoreRequest: req.RestoreRequest, Sinks: []string{"kafka"}},
+			setupMocks:    func(sql, broker *mocks.MockAuditSink) {},
+			expectedError: ErrInvalidRestore,
+		},
+		{
+			name:          "error - negative rate",
+			req:           ReplayRequest{RestoreRequest: req.RestoreRequest, Rate: -1},
+			setupMocks:    func(sql, broker *mocks.MockAuditSink) {},
+			expectedError: ErrInvalidRestore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sql := mocks.NewMockAuditSink(ctrl)
+			broker := mocks.NewMockAuditSink(ctrl)
+			tt.setupMocks(sql, broker)
+
+			restore := NewRestore(newTestArchive(t), mocks.NewMockRestoreStore(ctrl), 1000, 1).
+				WithReplaySink(SinkSQL, sql).
+				WithReplaySink(SinkBroker, broker)
+
+			err := restore.StartReplay(context.Background(), tt.req)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			status := waitReplay(t, restore)
+			if status.Replayed != tt.expected.Replayed {
+				t.Errorf("expected %d replayed, got %d", tt.expected.Replayed, status.Replayed)
+			}
+			if (status.Error != "") != (tt.expected.Error != "") {
+				t.Errorf("expected error %q, got %q", tt.expected.Error, status.Error)
+			}
+		})
+	}
+}
+
+func waitReplay(t *testing.T, restore *Restore) ReplayStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if status := restore.ReplayStatus(); !status.Running {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("replay did not finish")
+	return ReplayStatus{}
+}
+
+var errDiskFull = errors.New("disk full")
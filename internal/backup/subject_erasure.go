@@ -0,0 +1,56 @@
+package backup
+
+//go:generate mockgen -source=subject_erasure.go -destination=mocks/mock_subject_erasure.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/shred"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+const SubjectErasedEvent = "subject.erased"
+
+type ErasureKeyStore interface {
+	Destroy(key string) error
+}
+
+type SubjectErasure struct {
+	keyStore   ErasureKeyStore
+	auditStore AuditStore
+}
+
+// NewSubjectErasure expects a plain (non encrypting) audit store: the erasure
+// event must stay readable after the subject key is gone.
+func NewSubjectErasure(keyStore ErasureKeyStore, auditStore AuditStore) *SubjectErasure {
+	return &SubjectErasure{
+		keyStore:   keyStore,
+		auditStore: auditStore,
+	}
+}
+
+// Erase destroys the key of metadata's subject within its tenant: another
+// tenant's subject of the same key stays readable.
+func (se *SubjectErasure) Erase(ctx context.Context, metadata audit.MetadataAudit) error {
+	if err := se.keyStore.Destroy(shred.SubjectKey(metadata)); err != nil {
+		return fmt.Errorf("failed to destroy key: %w", err)
+	}
+
+	metadata.EventName = SubjectErasedEvent
+	metadata.EventAt = clock.Now()
+	metadata.EnsureID()
+
+	if err := se.auditStore.Upsert(ctx, audit.DataAudit{
+		Metadata: metadata,
+		Data: map[string]string{
+			"algorithm": shred.Algorithm,
+			"method":    "crypto-shredding",
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to save erasure event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup/mocks"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSubjectErasure_Erase(t *testing.T) {
+	fixedTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	clock.SetNow(fixedTime)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	metadata := audit.MetadataAudit{
+		Key:           "user:123",
+		RequestID:     "req-123",
+		CorrelationID: "corr-123",
+	}
+
+	tests := []struct {
+		name          string
+		tenant        string
+		setupMocks    func(keyStore *mocks.MockErasureKeyStore, auditStore *mocks.MockAuditStore)
+		expectedError bool
+	}{
+		{
+			name: "success - destroys key and records erasure event",
+			setupMocks: func(keyStore *mocks.MockErasureKeyStore, auditStore *mocks.MockAuditStore) {
+				keyStore.EXPECT().Destroy("user:123").Return(nil)
+				auditStore.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, input audit.DataAudit) error {
+						if input.Metadata.EventName != SubjectErasedEvent {
+							t.Errorf("expected event %q, got %q", SubjectErasedEvent, input.Metadata.EventName)
+						}
+						if !input.Metadata.EventAt.Equal(fixedTime) {
+							t.Errorf("expected event at %v, got %v", fixedTime, input.Metadata.EventAt)
+						}
+						return nil
+					})
+			},
+			expectedError: false,
+		},
+		{
+			name:   "success - destroys only the key of the tenant subject",
+			tenant: "acme",
+			setupMocks: func(keyStore *mocks.MockErasureKeyStore, auditStore *mocks.MockAuditStore) {
+				keyStore.EXPECT().Destroy("acme/user%3A123").Return(nil)
+				auditStore.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, input audit.DataAudit) error {
+						if input.Metadata.Tenant != "acme" {
+							t.Errorf("expected the erasure event of acme, got %q", input.Metadata.Tenant)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "error - destroy fails, no event recorded",
+			setupMocks: func(keyStore *mocks.MockErasureKeyStore, auditStore *mocks.MockAuditStore) {
+				keyStore.EXPECT().Destroy("user:123").Return(errors.New("key not found"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "error - erasure event fails",
+			setupMocks: func(keyStore *mocks.MockErasureKeyStore, auditStore *mocks.MockAuditStore) {
+				keyStore.EXPECT().Destroy("user:123").Return(nil)
+				auditStore.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("write failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keyStore := mocks.NewMockErasureKeyStore(ctrl)
+			auditStore := mocks.NewMockAuditStore(ctrl)
+			tt.setupMocks(keyStore, auditStore)
+
+			se := NewSubjectErasure(keyStore, auditStore)
+			metadata := metadata
+			metadata.Tenant = tt.tenant
+			err := se.Erase(context.Background(), metadata)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package backup
+
+//go:generate mockgen -source=tenant_backup.go -destination=mocks/mock_tenant_backup.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type Archival interface {
+	Backup(ctx context.Context) error
+	Store(ctx context.Context) error
+}
+
+// TenantBackups runs Backup and Store for the shared store and then for each
+// tenant, every one against its own directory and archive storage. A tenant
+// that fails does not keep the others from being archived.
+type TenantBackups struct {
+	shared  Archival
+	tenants map[string]Archival
+}
+
+func NewTenantBackups(shared Archival) *TenantBackups {
+	return &TenantBackups{
+		shared:  shared,
+		tenants: make(map[string]Archival),
+	}
+}
+
+func (tb *TenantBackups) Add(tenant string, archival Archival) *TenantBackups {
+	tb.tenants[tenant] = archival
+	return tb
+}
+
+func (tb *TenantBackups) Backup(ctx context.Context) error {
+	return tb.each(ctx, Archival.Backup)
+}
+
+func (tb *TenantBackups) Store(ctx context.Context) error {
+	return tb.each(ctx, Archival.Store)
+}
+
+func (tb *TenantBackups) each(ctx context.Context, run func(Archival, context.Context) error) error {
+	var errs []error
+	if err := run(tb.shared, ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, tenant := range slices.Sorted(maps.Keys(tb.tenants)) {
+		if err := run(tb.tenants[tenant], ctx); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
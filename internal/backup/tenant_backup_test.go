@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/backup/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTenantBackups_Store(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMocks    func(shared, acme, globex *mocks.MockArchival)
+		expectedError string
+	}{
+		{
+			name: "success - shared store and every tenant are archived",
+			setupMocks: func(shared, acme, globex *mocks.MockArchival) {
+				shared.EXPECT().Store(gomock.Any()).Return(nil)
+				acme.EXPECT().Store(gomock.Any()).Return(nil)
+				globex.EXPECT().Store(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error - a failing tenant does not stop the others",
+			setupMocks: func(shared, acme, globex *mocks.MockArchival) {
+				shared.EXPECT().Store(gomock.Any()).Return(nil)
+				acme.EXPECT().Store(gomock.Any()).Return(errors.New("access denied"))
+				globex.EXPECT().Store(gomock.Any()).Return(nil)
+			},
+			expectedError: "tenant acme: access denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			shared := mocks.NewMockArchival(ctrl)
+			acme := mocks.NewMockArchival(ctrl)
+			globex := mocks.NewMockArchival(ctrl)
+			tt.setupMocks(shared, acme, globex)
+
+			err := NewTenantBackups(shared).Add("acme", acme).Add("globex", globex).Store(context.Background())
+			if tt.expectedError == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError) {
+				t.Errorf("expected error %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
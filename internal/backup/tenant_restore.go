@@ -0,0 +1,76 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+// TenantRestores routes restores and replays to the Restore of the caller's
+// tenant, every one reading its own archive storage into its own directory.
+// Replays of different tenants run side by side.
+type TenantRestores struct {
+	shared  *Restore
+	tenants map[string]*Restore
+}
+
+func NewTenantRestores(shared *Restore) *TenantRestores {
+	return &TenantRestores{
+		shared:  shared,
+		tenants: make(map[string]*Restore),
+	}
+}
+
+func (tr *TenantRestores) Add(tenant string, restore *Restore) *TenantRestores {
+	tr.tenants[tenant] = restore.WithTenant(tenant)
+	return tr
+}
+
+// WithReplaySink makes sink available to the replays of every tenant.
+func (tr *TenantRestores) WithReplaySink(name string, sink AuditSink) *TenantRestores {
+	tr.shared.WithReplaySink(name, sink)
+	for _, restore := range tr.tenants {
+		restore.WithReplaySink(name, sink)
+	}
+	return tr
+}
+
+func (tr *TenantRestores) Restore(ctx context.Context, req RestoreRequest) (RestoreResult, error) {
+	restore, err := tr.restore(ctx)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	return restore.Restore(ctx, req)
+}
+
+func (tr *TenantRestores) StartReplay(ctx context.Context, req ReplayRequest) error {
+	restore, err := tr.restore(ctx)
+	if err != nil {
+		return err
+	}
+	return restore.StartReplay(ctx, req)
+}
+
+// ReplayStatus is the status of the last replay of the caller's tenant.
+func (tr *TenantRestores) ReplayStatus(ctx context.Context) ReplayStatus {
+	restore, err := tr.restore(ctx)
+	if err != nil {
+		return ReplayStatus{}
+	}
+	return restore.ReplayStatus()
+}
+
+func (tr *TenantRestores) restore(ctx context.Context) (*Restore, error) {
+	tenant, _ := ctxkey.Tenant(ctx)
+	if tenant == "" {
+		return tr.shared, nil
+	}
+
+	restore, ok := tr.tenants[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", store.ErrUnknownTenant, tenant)
+	}
+	return restore, nil
+}
@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/backup/mocks"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTenantRestores_Restore(t *testing.T) {
+	acmeEvent := testEvent("user:1", day14)
+	acmeEvent.Metadata.Tenant = "acme"
+
+	// a bucket acme shares with audits of no tenant
+	acmeArchive, err := store.NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	payload, _ := json.Marshal(store.Data{"2025-1-14": {acmeEvent, testEvent("user:1", day14.Add(time.Hour))}})
+	if err := acmeArchive.Put(context.Background(), "audits/user%3A1/2025-01-14.json", payload, time.Time{}); err != nil {
+		t.Fatalf("failed to put archive: %v", err)
+	}
+
+	req := RestoreRequest{Keys: []string{"user:1"}, From: day14, To: day14, Source: SourceObjects}
+
+	tests := []struct {
+		name           string
+		tenant         string
+		setupMocks     func(shared, acme *mocks.MockRestoreStore)
+		expectedResult RestoreResult
+		expectedError  error
+	}{
+		{
+			name: "success - shared caller restores the shared archive",
+			setupMocks: func(shared, acme *mocks.MockRestoreStore) {
+				shared.EXPECT().Merge(gomock.Any(), store.Key("user:1"), gomock.Any()).Return(nil)
+			},
+			expectedResult: RestoreResult{Keys: 1, Events: 2},
+		},
+		{
+			name:   "success - tenant restores its own audits into its store",
+			tenant: "acme",
+			setupMocks: func(shared, acme *mocks.MockRestoreStore) {
+				acme.EXPECT().Merge(gomock.Any(), store.Key("user:1"), gomock.Any()).DoAndReturn(func(_ context.Context, _ store.Key, data store.Data) error {
+					if events := data["2025-1-14"]; len(events) != 1 || events[0].Metadata.Tenant != "acme" {
+						t.Errorf("expected the audit of acme only, got %v", events)
+					}
+					return nil
+				})
+			},
+			expectedResult: RestoreResult{Keys: 1, Events: 1},
+		},
+		{
+			name:          "error - unknown tenant",
+			tenant:        "globex",
+			setupMocks:    func(shared, acme *mocks.MockRestoreStore) {},
+			expectedError: store.ErrUnknownTenant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			shared := mocks.NewMockRestoreStore(ctrl)
+			acme := mocks.NewMockRestoreStore(ctrl)
+			tt.setupMocks(shared, acme)
+
+			restores := NewTenantRestores(NewRestore(newTestArchive(t), shared, 0, 10)).
+				Add("acme", NewRestore(acmeArchive, acme, 0, 10))
+
+			ctx := ctxkey.SetTenant(context.Background(), tt.tenant)
+			result, err := restores.Restore(ctx, req)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if result != tt.expectedResult {
+				t.Errorf("expected result %+v, got %+v", tt.expectedResult, result)
+			}
+		})
+	}
+}
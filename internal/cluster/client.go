@@ -0,0 +1,142 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+)
+
+// SecretHeader carries the cluster secret on node-to-node requests.
+const SecretHeader = "X-Cluster-Secret"
+
+// Handoff moves everything a node holds for a key to its owner.
+type Handoff struct {
+	Key  string     `json:"key"`
+	Data store.Data `json:"data"`
+}
+
+type SaveResponse struct {
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+// Client talks to the other nodes: POST /cluster/audits, POST
+// /cluster/handoff and GET /healthz.
+type Client struct {
+	peers  map[string]string
+	secret string
+	http   *http.Client
+}
+
+func NewClient(peers map[string]string, secret string, httpClient *http.Client) *Client {
+	trimmed := make(map[string]string, len(peers))
+	for node, url := range peers {
+		trimmed[node] = strings.TrimSuffix(url, "/")
+	}
+	return &Client{peers: trimmed, secret: secret, http: httpClient}
+}
+
+// Authorized reports whether r carries the cluster secret.
+func Authorized(r *http.Request, secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretHeader)), []byte(secret)) == 1
+}
+
+// Forward saves input on node and returns its idempotency key.
+func (c *Client) Forward(ctx context.Context, node string, input audit.DataAudit) (string, error) {
+	resp, err := c.post(ctx, node, "/cluster/audits", input)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		var output SaveResponse
+		if err := json.NewDecoder(resp.Body).Decode(&output); err != nil {
+			return "", fmt.Errorf("failed to decode response of node %s: %w", node, err)
+		}
+		return output.IdempotencyKey, nil
+	case http.StatusConflict:
+		return "", backup.ErrIdempotencyKeyAlreadyExists
+	default:
+		return "", statusError(node, resp)
+	}
+}
+
+func (c *Client) Handoff(ctx context.Context, node string, handoff Handoff) error {
+	resp, err := c.post(ctx, node, "/cluster/handoff", handoff)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return statusError(node, resp)
+	}
+	return nil
+}
+
+// Probe checks node is alive through its liveness endpoint.
+func (c *Client) Probe(ctx context.Context, node string) error {
+	url, ok := c.peers[node]
+	if !ok {
+		return fmt.Errorf("unknown node %s", node)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/healthz", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create probe request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to probe node %s: %w", node, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("node %s is not live: status %d", node, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *Client) post(ctx context.Context, node, path string, body any) (*http.Response, error) {
+	url, ok := c.peers[node]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %s", node)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SecretHeader, c.secret)
+	telemetry.Inject(ctx, req.Header)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach node %s: %w", node, err)
+	}
+	return resp, nil
+}
+
+func statusError(node string, resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("node %s answered status %d: %s", node, resp.StatusCode, strings.TrimSpace(string(body)))
+}
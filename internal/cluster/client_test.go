@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+)
+
+func TestClient_Forward(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		expectedKey string
+		expectedErr error
+		anyErr      bool
+	}{
+		{
+			name:        "success - returns the owner's idempotency key",
+			status:      http.StatusCreated,
+			body:        `{"idempotency_key":"user:123-created--"}`,
+			expectedKey: "user:123-created--",
+		},
+		{
+			name:        "error - conflict is a duplicate",
+			status:      http.StatusConflict,
+			expectedErr: backup.ErrIdempotencyKeyAlreadyExists,
+			anyErr:      true,
+		},
+		{
+			name:   "error - owner fails",
+			status: http.StatusInternalServerError,
+			anyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/cluster/audits" || r.Header.Get(SecretHeader) != "s3cret" {
+					t.Errorf("unexpected request %s with secret %q", r.URL.Path, r.Header.Get(SecretHeader))
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(map[string]string{"node-b": server.URL + "/"}, "s3cret", server.Client())
+			key, err := client.Forward(context.Background(), "node-b", audit.DataAudit{})
+
+			if (err != nil) != tt.anyErr {
+				t.Fatalf("expected error %v, got %v", tt.anyErr, err)
+			}
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if key != tt.expectedKey {
+				t.Errorf("expected key %q, got %q", tt.expectedKey, key)
+			}
+		})
+	}
+}
+
+func TestClient_Probe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(map[string]string{"node-b": server.URL}, "s3cret", server.Client())
+	if err := client.Probe(context.Background(), "node-b"); err == nil {
+		t.Error("expected degraded node to fail the probe")
+	}
+	if err := client.Probe(context.Background(), "node-z"); err == nil {
+		t.Error("expected unknown node to fail the probe")
+	}
+}
+
+func TestAuthorized(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		header   string
+		expected bool
+	}{
+		{name: "success - matching secret", secret: "s3cret", header: "s3cret", expected: true},
+		{name: "error - wrong secret", secret: "s3cret", header: "guess", expected: false},
+		{name: "error - empty secret never matches", secret: "", header: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/cluster/audits", nil)
+			req.Header.Set(SecretHeader, tt.header)
+			if got := Authorized(req, tt.secret); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package cluster
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("cluster")
@@ -0,0 +1,120 @@
+package cluster
+
+//go:generate mockgen -source=membership.go -destination=mocks/mock_membership.go -package=mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/metrics"
+)
+
+type Prober interface {
+	Probe(ctx context.Context, node string) error
+}
+
+// Membership probes the peers and keeps the ring to this node plus the peers
+// that are alive. A peer leaves after threshold failed probes in a row and
+// comes back on its first successful one.
+type Membership struct {
+	mu        sync.Mutex
+	self      string
+	peers     []string
+	ring      *Ring
+	prober    Prober
+	threshold int
+	failures  map[string]int
+	changes   chan struct{}
+}
+
+func NewMembership(self string, peers []string, ring *Ring, prober Prober, threshold int) *Membership {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	sort.Strings(peers)
+
+	m := &Membership{
+		self:      self,
+		peers:     peers,
+		ring:      ring,
+		prober:    prober,
+		threshold: threshold,
+		failures:  make(map[string]int, len(peers)),
+		changes:   make(chan struct{}, 1),
+	}
+	// peers start in the ring so a restarting node does not pull every key
+	// to itself before the first probe
+	m.ring.Set(append([]string{self}, peers...))
+	metrics.ClusterMembers.Set(float64(len(peers) + 1))
+
+	return m
+}
+
+// Changes signals, without blocking the prober, that the ring changed.
+func (m *Membership) Changes() <-chan struct{} {
+	return m.changes
+}
+
+// Run probes the peers every period until ctx is done.
+func (m *Membership) Run(ctx context.Context, period, timeout time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.probe(ctx, timeout)
+		}
+	}
+}
+
+func (m *Membership) probe(ctx context.Context, timeout time.Duration) {
+	results := make(map[string]error, len(m.peers))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, peer := range m.peers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			err := m.prober.Probe(probeCtx, peer)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[peer] = err
+		}()
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	members := []string{m.self}
+	for _, peer := range m.peers {
+		if err := results[peer]; err != nil {
+			m.failures[peer]++
+			logger.DebugContext(ctx, "peer probe failed", "node", peer, "failures", m.failures[peer], "error", err)
+		} else {
+			m.failures[peer] = 0
+		}
+		if m.failures[peer] < m.threshold {
+			members = append(members, peer)
+		}
+	}
+
+	if m.ring.Set(members) {
+		logger.InfoContext(ctx, "cluster membership changed", "members", members)
+		metrics.ClusterMembers.Set(float64(len(members)))
+		select {
+		case m.changes <- struct{}{}:
+		default:
+		}
+	}
+}
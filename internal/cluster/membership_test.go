@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fakeProber map[string]error
+
+func (fp fakeProber) Probe(ctx context.Context, node string) error {
+	return fp[node]
+}
+
+func TestMembership_Probe(t *testing.T) {
+	prober := fakeProber{}
+	ring := NewRing(16)
+	membership := NewMembership("node-a", []string{"node-b", "node-c"}, ring, prober, 2)
+
+	probe := func(nodeCErr error) {
+		prober["node-c"] = nodeCErr
+		membership.probe(context.Background(), time.Second)
+	}
+	changed := func() bool {
+		select {
+		case <-membership.Changes():
+			return true
+		default:
+			return false
+		}
+	}
+
+	all := []string{"node-a", "node-b", "node-c"}
+	if !slices.Equal(ring.Members(), all) {
+		t.Fatalf("expected peers in the ring from the start, got %v", ring.Members())
+	}
+
+	// one failure is below the threshold
+	probe(errors.New("timeout"))
+	if !slices.Equal(ring.Members(), all) || changed() {
+		t.Fatalf("expected node-c to stay after one failure, got %v", ring.Members())
+	}
+
+	probe(errors.New("timeout"))
+	if !slices.Equal(ring.Members(), []string{"node-a", "node-b"}) || !changed() {
+		t.Fatalf("expected node-c to leave after two failures, got %v", ring.Members())
+	}
+
+	probe(nil)
+	if !slices.Equal(ring.Members(), all) || !changed() {
+		t.Fatalf("expected node-c to come back, got %v", ring.Members())
+	}
+}
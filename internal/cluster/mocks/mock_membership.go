@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: membership.go
+//
+// Generated by this command:
+//
+//	mockgen -source=membership.go -destination=mocks/mock_membership.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProber is a mock of Prober interface.
+type MockProber struct {
+	ctrl     *gomock.Controller
+	recorder *MockProberMockRecorder
+	isgomock struct{}
+}
+
+// MockProberMockRecorder is the mock recorder for MockProber.
+type MockProberMockRecorder struct {
+	mock *MockProber
+}
+
+// NewMockProber creates a new mock instance.
+func NewMockProber(ctrl *gomock.Controller) *MockProber {
+	mock := &MockProber{ctrl: ctrl}
+	mock.recorder = &MockProberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProber) EXPECT() *MockProberMockRecorder {
+	return m.recorder
+}
+
+// Probe mocks base method.
+func (m *MockProber) Probe(ctx context.Context, node string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Probe", ctx, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Probe indicates an expected call of Probe.
+func (mr *MockProberMockRecorder) Probe(ctx, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Probe", reflect.TypeOf((*MockProber)(nil).Probe), ctx, node)
+}
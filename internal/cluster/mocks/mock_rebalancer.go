@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rebalancer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=rebalancer.go -destination=mocks/mock_rebalancer.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	cluster "github.com/IsaacDSC/auditory/internal/cluster"
+	store "github.com/IsaacDSC/auditory/internal/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeyStore is a mock of KeyStore interface.
+type MockKeyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyStoreMockRecorder is the mock recorder for MockKeyStore.
+type MockKeyStoreMockRecorder struct {
+	mock *MockKeyStore
+}
+
+// NewMockKeyStore creates a new mock instance.
+func NewMockKeyStore(ctrl *gomock.Controller) *MockKeyStore {
+	mock := &MockKeyStore{ctrl: ctrl}
+	mock.recorder = &MockKeyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyStore) EXPECT() *MockKeyStoreMockRecorder {
+	return m.recorder
+}
+
+// Keys mocks base method.
+func (m *MockKeyStore) Keys(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockKeyStoreMockRecorder) Keys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockKeyStore)(nil).Keys), ctx)
+}
+
+// Merge mocks base method.
+func (m *MockKeyStore) Merge(ctx context.Context, key store.Key, data store.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockKeyStoreMockRecorder) Merge(ctx, key, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockKeyStore)(nil).Merge), ctx, key, data)
+}
+
+// Take mocks base method.
+func (m *MockKeyStore) Take(ctx context.Context, key store.Key) (store.Data, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, key)
+	ret0, _ := ret[0].(store.Data)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockKeyStoreMockRecorder) Take(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockKeyStore)(nil).Take), ctx, key)
+}
+
+// MockHandoffSender is a mock of HandoffSender interface.
+type MockHandoffSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandoffSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockHandoffSenderMockRecorder is the mock recorder for MockHandoffSender.
+type MockHandoffSenderMockRecorder struct {
+	mock *MockHandoffSender
+}
+
+// NewMockHandoffSender creates a new mock instance.
+func NewMockHandoffSender(ctrl *gomock.Controller) *MockHandoffSender {
+	mock := &MockHandoffSender{ctrl: ctrl}
+	mock.recorder = &MockHandoffSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandoffSender) EXPECT() *MockHandoffSenderMockRecorder {
+	return m.recorder
+}
+
+// Handoff mocks base method.
+func (m *MockHandoffSender) Handoff(ctx context.Context, node string, handoff cluster.Handoff) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handoff", ctx, node, handoff)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Handoff indicates an expected call of Handoff.
+func (mr *MockHandoffSenderMockRecorder) Handoff(ctx, node, handoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handoff", reflect.TypeOf((*MockHandoffSender)(nil).Handoff), ctx, node, handoff)
+}
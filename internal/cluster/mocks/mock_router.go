@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: router.go
+//
+// Generated by this command:
+//
+//	mockgen -source=router.go -destination=mocks/mock_router.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditSaver is a mock of AuditSaver interface.
+type MockAuditSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditSaverMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditSaverMockRecorder is the mock recorder for MockAuditSaver.
+type MockAuditSaverMockRecorder struct {
+	mock *MockAuditSaver
+}
+
+// NewMockAuditSaver creates a new mock instance.
+func NewMockAuditSaver(ctrl *gomock.Controller) *MockAuditSaver {
+	mock := &MockAuditSaver{ctrl: ctrl}
+	mock.recorder = &MockAuditSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditSaver) EXPECT() *MockAuditSaverMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockAuditSaver) Save(ctx context.Context, input audit.DataAudit) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAuditSaverMockRecorder) Save(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAuditSaver)(nil).Save), ctx, input)
+}
+
+// MockForwarder is a mock of Forwarder interface.
+type MockForwarder struct {
+	ctrl     *gomock.Controller
+	recorder *MockForwarderMockRecorder
+	isgomock struct{}
+}
+
+// MockForwarderMockRecorder is the mock recorder for MockForwarder.
+type MockForwarderMockRecorder struct {
+	mock *MockForwarder
+}
+
+// NewMockForwarder creates a new mock instance.
+func NewMockForwarder(ctrl *gomock.Controller) *MockForwarder {
+	mock := &MockForwarder{ctrl: ctrl}
+	mock.recorder = &MockForwarderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockForwarder) EXPECT() *MockForwarderMockRecorder {
+	return m.recorder
+}
+
+// Forward mocks base method.
+func (m *MockForwarder) Forward(ctx context.Context, node string, input audit.DataAudit) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forward", ctx, node, input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Forward indicates an expected call of Forward.
+func (mr *MockForwarderMockRecorder) Forward(ctx, node, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockForwarder)(nil).Forward), ctx, node, input)
+}
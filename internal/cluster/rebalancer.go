@@ -0,0 +1,100 @@
+package cluster
+
+//go:generate mockgen -source=rebalancer.go -destination=mocks/mock_rebalancer.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/store"
+)
+
+type KeyStore interface {
+	Keys(ctx context.Context) ([]string, error)
+	Take(ctx context.Context, key store.Key) (store.Data, error)
+	Merge(ctx context.Context, key store.Key, data store.Data) error
+}
+
+type HandoffSender interface {
+	Handoff(ctx context.Context, node string, handoff Handoff) error
+}
+
+// Rebalancer hands the local keys owned by other nodes to their owners: keys
+// written while the owner was unreachable and keys whose owner changed.
+type Rebalancer struct {
+	self   string
+	ring   *Ring
+	keys   KeyStore
+	sender HandoffSender
+}
+
+func NewRebalancer(self string, ring *Ring, keys KeyStore, sender HandoffSender) *Rebalancer {
+	return &Rebalancer{self: self, ring: ring, keys: keys, sender: sender}
+}
+
+// Run rebalances every period and whenever changes fires, until ctx is done.
+func (rb *Rebalancer) Run(ctx context.Context, period time.Duration, changes <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-changes:
+		}
+
+		if remaining, err := rb.Rebalance(ctx); err != nil || remaining > 0 {
+			logger.WarnContext(ctx, "keys left to hand off", "remaining", remaining, "error", err)
+		}
+	}
+}
+
+// Rebalance hands off every local key owned by another node and returns how
+// many could not be handed off.
+func (rb *Rebalancer) Rebalance(ctx context.Context) (int, error) {
+	keys, err := rb.keys.Keys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := 0
+	for _, key := range keys {
+		owner := rb.ring.Owner(key)
+		if owner == "" || owner == rb.self {
+			continue
+		}
+
+		if err := rb.handoff(ctx, owner, key); err != nil {
+			logger.ErrorContext(ctx, "failed to hand off key", "key", key, "node", owner, "error", err)
+			remaining++
+			continue
+		}
+		metrics.ClusterHandoffKeys.Inc()
+	}
+
+	return remaining, nil
+}
+
+func (rb *Rebalancer) handoff(ctx context.Context, owner, key string) error {
+	data, err := rb.keys.Take(ctx, store.Key(key))
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := rb.sender.Handoff(ctx, owner, Handoff{Key: key, Data: data}); err != nil {
+		// put it back for the next attempt
+		if mergeErr := rb.keys.Merge(ctx, store.Key(key), data); mergeErr != nil {
+			logger.ErrorContext(ctx, "ALERT: failed to restore key after failed handoff", "key", key, "error", mergeErr)
+		}
+		return err
+	}
+
+	logger.InfoContext(ctx, "handed off key", "key", key, "node", owner)
+	return nil
+}
@@ -0,0 +1,82 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/cluster"
+	"github.com/IsaacDSC/auditory/internal/cluster/mocks"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRebalancer_Rebalance(t *testing.T) {
+	ring := cluster.NewRing(64)
+	ring.Set([]string{"node-a", "node-b"})
+	localKey := keyOwnedBy(t, ring, "node-a")
+	remoteKey := keyOwnedBy(t, ring, "node-b")
+	data := store.Data{"2025-1-15": nil}
+
+	tests := []struct {
+		name              string
+		setupMocks        func(keys *mocks.MockKeyStore, sender *mocks.MockHandoffSender)
+		expectedRemaining int
+		expectedErr       bool
+	}{
+		{
+			name: "success - hands off only keys owned by other nodes",
+			setupMocks: func(keys *mocks.MockKeyStore, sender *mocks.MockHandoffSender) {
+				keys.EXPECT().Keys(gomock.Any()).Return([]string{localKey, remoteKey}, nil)
+				keys.EXPECT().Take(gomock.Any(), store.Key(remoteKey)).Return(data, nil)
+				sender.EXPECT().Handoff(gomock.Any(), "node-b", cluster.Handoff{Key: remoteKey, Data: data}).Return(nil)
+			},
+			expectedRemaining: 0,
+		},
+		{
+			name: "success - failed handoff puts the data back",
+			setupMocks: func(keys *mocks.MockKeyStore, sender *mocks.MockHandoffSender) {
+				keys.EXPECT().Keys(gomock.Any()).Return([]string{remoteKey}, nil)
+				keys.EXPECT().Take(gomock.Any(), store.Key(remoteKey)).Return(data, nil)
+				sender.EXPECT().Handoff(gomock.Any(), "node-b", gomock.Any()).Return(errors.New("connection refused"))
+				keys.EXPECT().Merge(gomock.Any(), store.Key(remoteKey), data).Return(nil)
+			},
+			expectedRemaining: 1,
+		},
+		{
+			name: "success - empty key is not sent",
+			setupMocks: func(keys *mocks.MockKeyStore, sender *mocks.MockHandoffSender) {
+				keys.EXPECT().Keys(gomock.Any()).Return([]string{remoteKey}, nil)
+				keys.EXPECT().Take(gomock.Any(), store.Key(remoteKey)).Return(store.Data{}, nil)
+			},
+			expectedRemaining: 0,
+		},
+		{
+			name: "error - listing keys fails",
+			setupMocks: func(keys *mocks.MockKeyStore, sender *mocks.MockHandoffSender) {
+				keys.EXPECT().Keys(gomock.Any()).Return(nil, errors.New("permission denied"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keys := mocks.NewMockKeyStore(ctrl)
+			sender := mocks.NewMockHandoffSender(ctrl)
+			tt.setupMocks(keys, sender)
+
+			remaining, err := cluster.NewRebalancer("node-a", ring, keys, sender).Rebalance(context.Background())
+
+			if (err != nil) != tt.expectedErr {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if remaining != tt.expectedRemaining {
+				t.Errorf("expected %d remaining, got %d", tt.expectedRemaining, remaining)
+			}
+		})
+	}
+}
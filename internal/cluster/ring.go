@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Ring maps audit keys to nodes by consistent hashing. Each node owns
+// vnodes points on the ring, so a membership change only moves the keys
+// between the changed node and its neighbours.
+type Ring struct {
+	mu      sync.RWMutex
+	vnodes  int
+	points  []uint64
+	owners  map[uint64]string
+	members []string
+}
+
+func NewRing(vnodes int) *Ring {
+	if vnodes <= 0 {
+		vnodes = 128
+	}
+	return &Ring{vnodes: vnodes, owners: make(map[uint64]string)}
+}
+
+// Set replaces the ring members and reports whether they changed.
+func (r *Ring) Set(members []string) bool {
+	members = slices.Clone(members)
+	sort.Strings(members)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.Equal(members, r.members) {
+		return false
+	}
+
+	points := make([]uint64, 0, len(members)*r.vnodes)
+	owners := make(map[uint64]string, len(members)*r.vnodes)
+	for _, member := range members {
+		for i := range r.vnodes {
+			point := hash(member + "#" + strconv.Itoa(i))
+			// on the rare collision the smaller name wins, the same on every node
+			if owner, taken := owners[point]; taken && owner < member {
+				continue
+			}
+			if _, taken := owners[point]; !taken {
+				points = append(points, point)
+			}
+			owners[point] = member
+		}
+	}
+	slices.Sort(points)
+
+	r.points, r.owners, r.members = points, owners, members
+	return true
+}
+
+// Owner returns the node owning key, or an empty string on an empty ring.
+func (r *Ring) Owner(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	point := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= point })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+func (r *Ring) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.members)
+}
+
+// hash is FNV-1a followed by the splitmix64 finalizer, which spreads the
+// near-identical virtual node names evenly over the ring.
+func hash(value string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRing_Owner(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []string
+		key     string
+		want    func(owner string) bool
+	}{
+		{
+			name:    "success - empty ring has no owner",
+			members: nil,
+			key:     "user:123",
+			want:    func(owner string) bool { return owner == "" },
+		},
+		{
+			name:    "success - single node owns every key",
+			members: []string{"node-a"},
+			key:     "user:123",
+			want:    func(owner string) bool { return owner == "node-a" },
+		},
+		{
+			name:    "success - owner is a member",
+			members: []string{"node-a", "node-b", "node-c"},
+			key:     "order:987",
+			want:    func(owner string) bool { return owner == "node-a" || owner == "node-b" || owner == "node-c" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := NewRing(64)
+			ring.Set(tt.members)
+
+			if owner := ring.Owner(tt.key); !tt.want(owner) {
+				t.Errorf("unexpected owner %q", owner)
+			}
+		})
+	}
+}
+
+func TestRing_SameOwnerOnEveryNode(t *testing.T) {
+	first, second := NewRing(128), NewRing(128)
+	first.Set([]string{"node-a", "node-b", "node-c"})
+	second.Set([]string{"node-c", "node-a", "node-b"})
+
+	for i := range 1000 {
+		key := fmt.Sprintf("user:%d", i)
+		if first.Owner(key) != second.Owner(key) {
+			t.Fatalf("nodes disagree on the owner of %s", key)
+		}
+	}
+}
+
+func TestRing_Rebalance(t *testing.T) {
+	const keys = 10000
+	ring := NewRing(128)
+	ring.Set([]string{"node-a", "node-b", "node-c"})
+
+	before := make(map[string]string, keys)
+	counts := make(map[string]int)
+	for i := range keys {
+		key := fmt.Sprintf("user:%d", i)
+		before[key] = ring.Owner(key)
+		counts[before[key]]++
+	}
+
+	// every node holds a fair share
+	for node, count := range counts {
+		if count < keys/3/2 || count > keys/3*2 {
+			t.Errorf("node %s owns %d of %d keys", node, count, keys)
+		}
+	}
+
+	if changed := ring.Set([]string{"node-a", "node-b", "node-c", "node-d"}); !changed {
+		t.Fatal("expected membership change")
+	}
+	if changed := ring.Set([]string{"node-d", "node-c", "node-b", "node-a"}); changed {
+		t.Fatal("expected no change for the same members")
+	}
+
+	moved := 0
+	for key, owner := range before {
+		if now := ring.Owner(key); now != owner {
+			moved++
+			if now != "node-d" {
+				t.Fatalf("key %s moved from %s to %s instead of the new node", key, owner, now)
+			}
+		}
+	}
+	if moved < keys/4/2 || moved > keys/4*2 {
+		t.Errorf("expected about a quarter of the keys to move, %d of %d did", moved, keys)
+	}
+}
@@ -0,0 +1,58 @@
+package cluster
+
+//go:generate mockgen -source=router.go -destination=mocks/mock_router.go -package=mocks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+)
+
+type AuditSaver interface {
+	Save(ctx context.Context, input audit.DataAudit) (string, error)
+}
+
+type Forwarder interface {
+	Forward(ctx context.Context, node string, input audit.DataAudit) (string, error)
+}
+
+// Router saves an audit on the node owning its key. When the owner cannot
+// take it the audit is kept locally and the rebalancer hands it off later,
+// so ingestion never fails because a peer is down.
+type Router struct {
+	self      string
+	ring      *Ring
+	forwarder Forwarder
+	local     AuditSaver
+}
+
+func NewRouter(self string, ring *Ring, forwarder Forwarder, local AuditSaver) *Router {
+	return &Router{self: self, ring: ring, forwarder: forwarder, local: local}
+}
+
+func (r *Router) Save(ctx context.Context, input audit.DataAudit) (string, error) {
+	owner := r.ring.Owner(input.Metadata.Key)
+	if owner == "" || owner == r.self {
+		return r.local.Save(ctx, input)
+	}
+
+	// the owner may have stored the audit before its reply was lost, so the
+	// local copy kept for handoff must carry the same id to be deduplicated
+	input.Metadata.EnsureID()
+	idempotencyKey, err := r.forwarder.Forward(ctx, owner, input)
+	switch {
+	case err == nil:
+		metrics.ClusterForwarded.WithLabelValues(metrics.ResultAccepted).Inc()
+		return idempotencyKey, nil
+	case errors.Is(err, backup.ErrIdempotencyKeyAlreadyExists):
+		metrics.ClusterForwarded.WithLabelValues(metrics.ResultDuplicate).Inc()
+		return "", err
+	default:
+		logger.WarnContext(ctx, "failed to forward audit, keeping it for handoff", "node", owner, "error", err)
+		metrics.ClusterForwarded.WithLabelValues(metrics.ResultFailed).Inc()
+		return r.local.Save(ctx, input)
+	}
+}
@@ -0,0 +1,106 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/cluster"
+	"github.com/IsaacDSC/auditory/internal/cluster/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+// keyOwnedBy finds a key ring assigns to node.
+func keyOwnedBy(t *testing.T, ring *cluster.Ring, node string) string {
+	t.Helper()
+	for i := range 1000 {
+		key := fmt.Sprintf("user:%d", i)
+		if ring.Owner(key) == node {
+			return key
+		}
+	}
+	t.Fatalf("no key owned by %s", node)
+	return ""
+}
+
+func TestRouter_Save(t *testing.T) {
+	ring := cluster.NewRing(64)
+	ring.Set([]string{"node-a", "node-b"})
+	localKey := keyOwnedBy(t, ring, "node-a")
+	remoteKey := keyOwnedBy(t, ring, "node-b")
+
+	tests := []struct {
+		name        string
+		key         string
+		setupMocks  func(forwarder *mocks.MockForwarder, local *mocks.MockAuditSaver)
+		expectedKey string
+		expectedErr error
+	}{
+		{
+			name: "success - saves locally owned key",
+			key:  localKey,
+			setupMocks: func(forwarder *mocks.MockForwarder, local *mocks.MockAuditSaver) {
+				local.EXPECT().Save(gomock.Any(), gomock.Any()).Return("local-key", nil)
+			},
+			expectedKey: "local-key",
+		},
+		{
+			name: "success - forwards key owned by another node",
+			key:  remoteKey,
+			setupMocks: func(forwarder *mocks.MockForwarder, local *mocks.MockAuditSaver) {
+				forwarder.EXPECT().Forward(gomock.Any(), "node-b", gomock.Any()).Return("remote-key", nil)
+			},
+			expectedKey: "remote-key",
+		},
+		{
+			name: "success - keeps audit locally when the owner is unreachable",
+			key:  remoteKey,
+			setupMocks: func(forwarder *mocks.MockForwarder, local *mocks.MockAuditSaver) {
+				var forwardedID string
+				forwarder.EXPECT().Forward(gomock.Any(), "node-b", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, input audit.DataAudit) (string, error) {
+					forwardedID = input.Metadata.ID
+					return "", errors.New("connection refused")
+				})
+				local.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) (string, error) {
+					if forwardedID == "" || input.Metadata.ID != forwardedID {
+						t.Errorf("expected the forwarded id %q, got %q", forwardedID, input.Metadata.ID)
+					}
+					return "local-key", nil
+				})
+			},
+			expectedKey: "local-key",
+		},
+		{
+			name: "error - duplicate on the owner is not saved again",
+			key:  remoteKey,
+			setupMocks: func(forwarder *mocks.MockForwarder, local *mocks.MockAuditSaver) {
+				forwarder.EXPECT().Forward(gomock.Any(), "node-b", gomock.Any()).Return("", backup.ErrIdempotencyKeyAlreadyExists)
+			},
+			expectedErr: backup.ErrIdempotencyKeyAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			forwarder := mocks.NewMockForwarder(ctrl)
+			local := mocks.NewMockAuditSaver(ctrl)
+			tt.setupMocks(forwarder, local)
+
+			router := cluster.NewRouter("node-a", ring, forwarder, local)
+			key, err := router.Save(context.Background(), audit.DataAudit{Metadata: audit.MetadataAudit{Key: tt.key}})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if key != tt.expectedKey {
+				t.Errorf("expected idempotency key %q, got %q", tt.expectedKey, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DirCheck writes and removes a probe file in dir and fails when the
+// filesystem has less than minFreeBytes available.
+func DirCheck(name, dir string, minFreeBytes uint64) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (map[string]any, error) {
+		details := map[string]any{"dir": dir}
+
+		probe, err := os.CreateTemp(dir, ".healthz-*")
+		if err != nil {
+			return details, fmt.Errorf("dir is not writable: %w", err)
+		}
+		probe.Close()
+		if err := os.Remove(probe.Name()); err != nil {
+			return details, fmt.Errorf("failed to remove probe file: %w", err)
+		}
+
+		free, ok, err := freeBytes(dir)
+		if err != nil {
+			return details, fmt.Errorf("failed to stat filesystem: %w", err)
+		}
+		if !ok {
+			return details, nil
+		}
+
+		details["free_bytes"] = free
+		details["min_free_bytes"] = minFreeBytes
+		if free < minFreeBytes {
+			return details, fmt.Errorf("free space %d below minimum %d", free, minFreeBytes)
+		}
+		return details, nil
+	}}
+}
+
+// PingCheck reports whether a remote dependency answers.
+func PingCheck(name string, pinger Pinger) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (map[string]any, error) {
+		return nil, pinger.Ping(ctx)
+	}}
+}
+
+// SizeCheck reports size and fails once it exceeds max; max 0 only reports.
+// Calling size also proves the component is not stuck holding its lock.
+func SizeCheck(name string, size func() (int64, error), max int64) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (map[string]any, error) {
+		n, err := size()
+		if err != nil {
+			return nil, err
+		}
+
+		details := map[string]any{"size": n}
+		if max > 0 {
+			details["max"] = max
+			if n > max {
+				return details, fmt.Errorf("size %d above maximum %d", n, max)
+			}
+		}
+		return details, nil
+	}}
+}
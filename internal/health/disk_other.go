@@ -0,0 +1,8 @@
+//go:build !unix
+
+package health
+
+// freeBytes is not implemented here; the disk check only tests writability.
+func freeBytes(dir string) (uint64, bool, error) {
+	return 0, false, nil
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package health
+
+import "golang.org/x/sys/unix"
+
+func freeBytes(dir string) (uint64, bool, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, false, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), true, nil
+}
@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDegraded = "degraded"
+)
+
+// Check probes one component. Details are reported whether it fails or not.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (map[string]any, error)
+}
+
+type ComponentReport struct {
+	Status  string         `json:"status"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentReport `json:"components"`
+}
+
+// Checker serves /healthz and /readyz. Liveness checks should only fail when a
+// restart helps (a stuck task, a deadlocked store); dependencies go to
+// readiness so an S3 outage takes the instance out of rotation instead.
+type Checker struct {
+	timeout   time.Duration
+	liveness  []Check
+	readiness []Check
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{timeout: timeout}
+}
+
+func (c *Checker) AddLiveness(checks ...Check) *Checker {
+	c.liveness = append(c.liveness, checks...)
+	return c
+}
+
+func (c *Checker) AddReadiness(checks ...Check) *Checker {
+	c.readiness = append(c.readiness, checks...)
+	return c
+}
+
+func (c *Checker) Liveness() (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.Run(r.Context(), c.liveness))
+	}
+}
+
+// Readiness also runs the liveness checks: a dead instance is never ready.
+func (c *Checker) Readiness() (string, func(w http.ResponseWriter, r *http.Request)) {
+	return "GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		checks := append(append([]Check{}, c.liveness...), c.readiness...)
+		writeReport(w, c.Run(r.Context(), checks))
+	}
+}
+
+// Run executes checks concurrently, each bounded by the checker timeout.
+func (c *Checker) Run(ctx context.Context, checks []Check) Report {
+	report := Report{Status: StatusOK, Components: make(map[string]ComponentReport, len(checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			component := c.runOne(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[check.Name] = component
+			if component.Status != StatusOK {
+				report.Status = StatusDegraded
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+type result struct {
+	details map[string]any
+	err     error
+}
+
+func (c *Checker) runOne(ctx context.Context, check Check) ComponentReport {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// a check blocked on a lock must not block the probe, so it runs apart
+	done := make(chan result, 1)
+	go func() {
+		details, err := check.Run(ctx)
+		done <- result{details: details, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = errors.New("check timed out")
+	}
+
+	component := ComponentReport{Status: StatusOK, Details: res.details}
+	if res.err != nil {
+		component.Status = StatusFail
+		component.Error = res.err.Error()
+	}
+	return component
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+func okCheck(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (map[string]any, error) {
+		return map[string]any{"size": 1}, nil
+	}}
+}
+
+func failCheck(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (map[string]any, error) {
+		return nil, errors.New("bucket unreachable")
+	}}
+}
+
+func blockedCheck(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (map[string]any, error) {
+		// ignores ctx like a check stuck on a lock
+		time.Sleep(time.Second)
+		return nil, nil
+	}}
+}
+
+func TestChecker(t *testing.T) {
+	tests := []struct {
+		name           string
+		liveness       []Check
+		readiness      []Check
+		probe          func(c *Checker) (string, func(w http.ResponseWriter, r *http.Request))
+		expectedStatus int
+		expectedFailed []string
+	}{
+		{
+			name:           "success - healthy liveness",
+			liveness:       []Check{okCheck("tasks")},
+			readiness:      []Check{failCheck("archive")},
+			probe:          (*Checker).Liveness,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "success - healthy readiness",
+			liveness:       []Check{okCheck("tasks")},
+			readiness:      []Check{okCheck("archive"), okCheck("tmp")},
+			probe:          (*Checker).Readiness,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "error - failing dependency degrades readiness",
+			liveness:       []Check{okCheck("tasks")},
+			readiness:      []Check{failCheck("archive"), okCheck("tmp")},
+			probe:          (*Checker).Readiness,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedFailed: []string{"archive"},
+		},
+		{
+			name:           "error - readiness includes liveness",
+			liveness:       []Check{failCheck("tasks")},
+			readiness:      []Check{okCheck("archive")},
+			probe:          (*Checker).Readiness,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedFailed: []string{"tasks"},
+		},
+		{
+			name:           "error - blocked check times out",
+			liveness:       []Check{blockedCheck("idempotency")},
+			probe:          (*Checker).Liveness,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedFailed: []string{"idempotency"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(50 * time.Millisecond).AddLiveness(tt.liveness...).AddReadiness(tt.readiness...)
+			_, handler := tt.probe(checker)
+
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			var report Report
+			if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			for _, name := range tt.expectedFailed {
+				if report.Components[name].Status != StatusFail || report.Components[name].Error == "" {
+					t.Errorf("expected %s to fail, got %+v", name, report.Components[name])
+				}
+			}
+		})
+	}
+}
+
+func TestDirCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		dir          string
+		minFreeBytes uint64
+		wantErr      bool
+	}{
+		{name: "success - writable dir with space", dir: t.TempDir()},
+		{name: "error - missing dir", dir: filepath.Join(t.TempDir(), "missing"), wantErr: true},
+		{name: "error - not enough free space", dir: t.TempDir(), minFreeBytes: 1 << 62, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DirCheck("tmp", tt.dir, tt.minFreeBytes).Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSizeCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    func() (int64, error)
+		max     int64
+		wantErr bool
+	}{
+		{name: "success - below max", size: func() (int64, error) { return 5, nil }, max: 10},
+		{name: "success - max disabled", size: func() (int64, error) { return 5000, nil }},
+		{name: "error - above max", size: func() (int64, error) { return 11, nil }, max: 10, wantErr: true},
+		{name: "error - size fails", size: func() (int64, error) { return 0, errors.New("stat failed") }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SizeCheck("outbox", tt.size, tt.max).Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTaskMonitor(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock.SetNow(start)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	monitor := NewTaskMonitor()
+	monitor.Register("backup", time.Minute)
+	monitor.Register("store", time.Hour)
+
+	clock.SetNow(start.Add(2 * time.Minute))
+	monitor.Beat("backup")
+
+	clock.SetNow(start.Add(4 * time.Minute))
+	if _, err := monitor.Check().Run(context.Background()); err != nil {
+		t.Errorf("expected tasks to be alive, got %v", err)
+	}
+
+	clock.SetNow(start.Add(6 * time.Minute))
+	if _, err := monitor.Check().Run(context.Background()); err == nil {
+		t.Error("expected backup to be reported as not ticking")
+	}
+}
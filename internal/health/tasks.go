@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+// staleAfter is how many periods a task may miss before it counts as stuck.
+const staleAfter = 3
+
+// Tasks is the monitor the background tasks beat on.
+var Tasks = NewTaskMonitor()
+
+type taskState struct {
+	period   time.Duration
+	lastTick time.Time
+}
+
+type TaskMonitor struct {
+	mu    sync.Mutex
+	tasks map[string]taskState
+}
+
+func NewTaskMonitor() *TaskMonitor {
+	return &TaskMonitor{tasks: make(map[string]taskState)}
+}
+
+// Register starts tracking name, expected to tick every period.
+func (tm *TaskMonitor) Register(name string, period time.Duration) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.tasks[name] = taskState{period: period, lastTick: clock.Now()}
+}
+
+func (tm *TaskMonitor) Beat(name string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	state := tm.tasks[name]
+	state.lastTick = clock.Now()
+	tm.tasks[name] = state
+}
+
+// Check fails when a registered task missed staleAfter ticks.
+func (tm *TaskMonitor) Check() Check {
+	return Check{Name: "tasks", Run: func(ctx context.Context) (map[string]any, error) {
+		tm.mu.Lock()
+		defer tm.mu.Unlock()
+
+		now := clock.Now()
+		details := make(map[string]any, len(tm.tasks))
+		var stale []string
+		for name, state := range tm.tasks {
+			details[name] = map[string]any{
+				"last_tick": state.lastTick,
+				"period":    state.period.String(),
+			}
+			if now.Sub(state.lastTick) > staleAfter*state.period {
+				stale = append(stale, name)
+			}
+		}
+
+		if len(stale) > 0 {
+			sort.Strings(stale)
+			return details, fmt.Errorf("tasks not ticking: %s", strings.Join(stale, ", "))
+		}
+		return details, nil
+	}}
+}
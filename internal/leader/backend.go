@@ -0,0 +1,56 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/store"
+)
+
+// NewBackend builds the lease selected by LEADER_BACKEND; none returns nil
+// and every replica runs the archival tasks.
+func NewBackend(ctx context.Context, conf *cfg.GeneralConfig) (Backend, error) {
+	leader := conf.LeaderConfig
+	switch leader.Backend {
+	case "", "none":
+		return nil, nil
+	case "file":
+		return NewFileLease(leader.FilePath)
+	case "s3":
+		s3Store, err := store.NewS3BucketStore(ctx, store.S3Config{
+			Bucket:          conf.BucketConfig.Name,
+			Endpoint:        conf.BucketConfig.Endpoint,
+			AccessKeyID:     conf.BucketConfig.AccessKeyID,
+			SecretAccessKey: conf.BucketConfig.SecretAccessKey,
+			Region:          conf.BucketConfig.Region,
+			UsePathStyle:    conf.BucketConfig.UsePathStyle,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return store.NewS3Lease(s3Store, leader.S3Key), nil
+	case "redis":
+		return NewRedisLease(RedisConfig{
+			Addr:     leader.RedisAddr,
+			Password: leader.RedisPassword,
+			Key:      leader.RedisKey,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown leader backend: %s", leader.Backend)
+	}
+}
+
+// HolderID identifies this replica in the lease: LEADER_HOLDER_ID, or
+// hostname-pid.
+func HolderID(conf cfg.LeaderConfig) string {
+	if conf.HolderID != "" {
+		return conf.HolderID
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
@@ -0,0 +1,121 @@
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+// Backend holds a lease on behalf of one holder at a time. Acquire takes a
+// free or expired lease, or renews the one holder already has, and returns
+// its fencing token: every new holder gets a token greater than all before it.
+type Backend interface {
+	Acquire(ctx context.Context, holder string, ttl time.Duration) (token uint64, acquired bool, err error)
+	Release(ctx context.Context, holder string) error
+}
+
+// Elector keeps trying to hold the lease and renews it every third of its TTL.
+type Elector struct {
+	mu      sync.Mutex
+	backend Backend
+	holder  string
+	ttl     time.Duration
+	token   uint64
+	expires time.Time
+}
+
+func NewElector(backend Backend, holder string, ttl time.Duration) *Elector {
+	return &Elector{backend: backend, holder: holder, ttl: ttl}
+}
+
+// Leading reports whether this replica holds the lease and its token. A
+// replica that cannot renew stops leading once its last lease would have
+// expired, before anyone else may take it over.
+func (e *Elector) Leading() (uint64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leadingInternal()
+}
+
+// leadingInternal reports leadership without acquiring lock (for internal use when lock is already held)
+func (e *Elector) leadingInternal() (uint64, bool) {
+	if e.token == 0 || !clock.Now().Before(e.expires) {
+		return 0, false
+	}
+	return e.token, true
+}
+
+// Run campaigns until ctx is done, then releases the lease.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		e.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Elector) campaign(ctx context.Context) {
+	// the lease is only trusted from before the request was sent
+	start := clock.Now()
+	token, acquired, err := e.backend.Acquire(ctx, e.holder, e.ttl)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_, wasLeading := e.leadingInternal()
+	switch {
+	case err != nil:
+		logger.ErrorContext(ctx, "failed to renew leader lease", "holder", e.holder, "error", err)
+	case acquired:
+		e.token, e.expires = token, start.Add(e.ttl)
+	default:
+		e.token, e.expires = 0, time.Time{}
+	}
+
+	token, leading := e.leadingInternal()
+	if leading != wasLeading {
+		if leading {
+			logger.InfoContext(ctx, "became leader", "holder", e.holder, "token", token)
+		} else {
+			logger.WarnContext(ctx, "lost leadership", "holder", e.holder)
+		}
+	}
+	metrics.Leader.Set(boolGauge(leading))
+	metrics.LeaderToken.Set(float64(token))
+}
+
+func (e *Elector) release() {
+	e.mu.Lock()
+	_, leading := e.leadingInternal()
+	e.token, e.expires = 0, time.Time{}
+	e.mu.Unlock()
+	metrics.Leader.Set(0)
+
+	if !leading {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.ttl)
+	defer cancel()
+	if err := e.backend.Release(ctx, e.holder); err != nil {
+		logger.ErrorContext(ctx, "failed to release leader lease", "holder", e.holder, "error", err)
+	}
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,126 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+type acquireResult struct {
+	token    uint64
+	acquired bool
+	err      error
+}
+
+type fakeBackend struct {
+	results  []acquireResult
+	released bool
+}
+
+func (fb *fakeBackend) Acquire(ctx context.Context, holder string, ttl time.Duration) (uint64, bool, error) {
+	result := fb.results[0]
+	fb.results = fb.results[1:]
+	return result.token, result.acquired, result.err
+}
+
+func (fb *fakeBackend) Release(ctx context.Context, holder string) error {
+	fb.released = true
+	return nil
+}
+
+func TestElector_Leading(t *testing.T) {
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	oldNow := clock.Now
+	defer func() { clock.Now = oldNow }()
+
+	tests := []struct {
+		name            string
+		results         []acquireResult
+		elapsed         time.Duration
+		expectedToken   uint64
+		expectedLeading bool
+	}{
+		{
+			name:            "success - leads with the acquired token",
+			results:         []acquireResult{{token: 3, acquired: true}},
+			expectedToken:   3,
+			expectedLeading: true,
+		},
+		{
+			name:            "success - follows while another replica holds the lease",
+			results:         []acquireResult{{acquired: false}},
+			expectedLeading: false,
+		},
+		{
+			name:            "success - stops leading when another replica takes over",
+			results:         []acquireResult{{token: 3, acquired: true}, {acquired: false}},
+			expectedLeading: false,
+		},
+		{
+			name:            "success - keeps leading while the lease is valid and renewal fails",
+			results:         []acquireResult{{token: 3, acquired: true}, {err: errors.New("bucket unavailable")}},
+			elapsed:         5 * time.Second,
+			expectedToken:   3,
+			expectedLeading: true,
+		},
+		{
+			name:            "error - stops leading once the lease expires without renewal",
+			results:         []acquireResult{{token: 3, acquired: true}, {err: errors.New("bucket unavailable")}},
+			elapsed:         15 * time.Second,
+			expectedLeading: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.SetNow(start)
+			backend := &fakeBackend{results: tt.results}
+			elector := NewElector(backend, "replica-a", 15*time.Second)
+
+			for range tt.results {
+				elector.campaign(context.Background())
+			}
+			clock.SetNow(start.Add(tt.elapsed))
+
+			token, leading := elector.Leading()
+			if leading != tt.expectedLeading {
+				t.Errorf("expected leading %v, got %v", tt.expectedLeading, leading)
+			}
+			if token != tt.expectedToken {
+				t.Errorf("expected token %d, got %d", tt.expectedToken, token)
+			}
+		})
+	}
+}
+
+func TestElector_ReleasesOnStop(t *testing.T) {
+	backend := &fakeBackend{results: []acquireResult{{token: 1, acquired: true}}}
+	elector := NewElector(backend, "replica-a", 15*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(done)
+	}()
+
+	// wait for the first campaign before stopping
+	for {
+		if _, leading := elector.Leading(); leading {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if !backend.released {
+		t.Error("expected lease to be released")
+	}
+	if _, leading := elector.Leading(); leading {
+		t.Error("expected elector to stop leading")
+	}
+}
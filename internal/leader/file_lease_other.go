@@ -0,0 +1,24 @@
+//go:build !unix
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FileLease needs flock(2); use the s3 or redis backend on this platform.
+type FileLease struct{}
+
+func NewFileLease(path string) (*FileLease, error) {
+	return nil, errors.New("file lease is only supported on unix")
+}
+
+func (fl *FileLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (uint64, bool, error) {
+	return 0, false, errors.New("file lease is only supported on unix")
+}
+
+func (fl *FileLease) Release(ctx context.Context, holder string) error {
+	return nil
+}
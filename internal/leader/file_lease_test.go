@@ -0,0 +1,46 @@
+//go:build unix
+
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLease(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "leases", "leader.lock")
+
+	first, err := NewFileLease(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := NewFileLease(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	token, acquired, err := first.Acquire(ctx, "replica-a", time.Second)
+	if err != nil || !acquired || token != 1 {
+		t.Fatalf("expected first holder to acquire token 1, got %d %v %v", token, acquired, err)
+	}
+
+	// renewing keeps the token
+	if token, acquired, err = first.Acquire(ctx, "replica-a", time.Second); err != nil || !acquired || token != 1 {
+		t.Fatalf("expected renewal to keep token 1, got %d %v %v", token, acquired, err)
+	}
+
+	if _, acquired, err = second.Acquire(ctx, "replica-b", time.Second); err != nil || acquired {
+		t.Fatalf("expected second holder to be locked out, got %v %v", acquired, err)
+	}
+
+	if err := first.Release(ctx, "replica-a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if token, acquired, err = second.Acquire(ctx, "replica-b", time.Second); err != nil || !acquired || token != 2 {
+		t.Fatalf("expected second holder to take over with token 2, got %d %v %v", token, acquired, err)
+	}
+}
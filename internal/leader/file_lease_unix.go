@@ -0,0 +1,110 @@
+//go:build unix
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// FileLease elects a leader among processes on one host with flock(2). The
+// lock goes away with the process, so the TTL is not needed; the file keeps
+// the last token handed out.
+type FileLease struct {
+	mu    sync.Mutex
+	path  string
+	file  *os.File
+	token uint64
+}
+
+func NewFileLease(path string) (*FileLease, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lease dir: %w", err)
+	}
+	return &FileLease{path: path}, nil
+}
+
+func (fl *FileLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (uint64, bool, error) {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if fl.file != nil {
+		return fl.token, true, nil
+	}
+
+	file, err := os.OpenFile(fl.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to open lease file: %w", err)
+	}
+
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to lock lease file: %w", err)
+	}
+
+	token, err := fl.bumpToken(file)
+	if err != nil {
+		unix.Flock(int(file.Fd()), unix.LOCK_UN)
+		file.Close()
+		return 0, false, err
+	}
+
+	fl.file, fl.token = file, token
+	return token, true, nil
+}
+
+func (fl *FileLease) Release(ctx context.Context, holder string) error {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if fl.file == nil {
+		return nil
+	}
+	defer func() { fl.file, fl.token = nil, 0 }()
+
+	if err := unix.Flock(int(fl.file.Fd()), unix.LOCK_UN); err != nil {
+		fl.file.Close()
+		return fmt.Errorf("failed to unlock lease file: %w", err)
+	}
+	return fl.file.Close()
+}
+
+// bumpToken increments the token stored in the locked file.
+func (fl *FileLease) bumpToken(file *os.File) (uint64, error) {
+	content, err := os.ReadFile(fl.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read lease file: %w", err)
+	}
+
+	var token uint64
+	if raw := strings.TrimSpace(string(content)); raw != "" {
+		if token, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return 0, fmt.Errorf("failed to parse lease token: %w", err)
+		}
+	}
+	token++
+
+	if err := file.Truncate(0); err != nil {
+		return 0, fmt.Errorf("failed to write lease token: %w", err)
+	}
+	if _, err := file.WriteAt([]byte(strconv.FormatUint(token, 10)), 0); err != nil {
+		return 0, fmt.Errorf("failed to write lease token: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to write lease token: %w", err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,5 @@
+package leader
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("leader")
@@ -0,0 +1,140 @@
+package leader
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// acquireScript takes KEYS[1] for ARGV[1] when it is free, or renews it when
+// ARGV[1] already holds it, and returns the fencing token; 0 means another
+// holder has it. KEYS[2] is a counter that never expires, so tokens only grow.
+const acquireScript = `
+local holder = redis.call('GET', KEYS[1])
+if holder == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return tonumber(redis.call('GET', KEYS[2]))
+end
+if holder then
+	return 0
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return token
+`
+
+const releaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+type RedisConfig struct {
+	Addr     string
+	Password string
+	Key      string
+	Timeout  time.Duration
+}
+
+// RedisLease keeps the lease in a key with a TTL on any server speaking the
+// Redis protocol (Redis, Valkey, KeyDB). Scripts make each step atomic.
+type RedisLease struct {
+	conf RedisConfig
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func NewRedisLease(conf RedisConfig) *RedisLease {
+	if conf.Timeout <= 0 {
+		conf.Timeout = 5 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: conf.Timeout}
+	return &RedisLease{conf: conf, dial: dialer.DialContext}
+}
+
+func (rl *RedisLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (uint64, bool, error) {
+	reply, err := rl.eval(ctx, acquireScript, holder, strconv.FormatInt(ttl.Milliseconds(), 10), rl.conf.Key, rl.conf.Key+":token")
+	if err != nil {
+		return 0, false, err
+	}
+	if reply <= 0 {
+		return 0, false, nil
+	}
+	return uint64(reply), true, nil
+}
+
+func (rl *RedisLease) Release(ctx context.Context, holder string) error {
+	_, err := rl.eval(ctx, releaseScript, holder, "", rl.conf.Key)
+	return err
+}
+
+func (rl *RedisLease) eval(ctx context.Context, script, holder, ttl string, keys ...string) (int64, error) {
+	conn, err := rl.dial(ctx, "tcp", rl.conf.Addr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(rl.conf.Timeout))
+
+	reader := bufio.NewReader(conn)
+	if rl.conf.Password != "" {
+		if _, err := command(conn, reader, "AUTH", rl.conf.Password); err != nil {
+			return 0, fmt.Errorf("failed to authenticate to redis: %w", err)
+		}
+	}
+
+	args := []string{"EVAL", script, strconv.Itoa(len(keys))}
+	args = append(args, keys...)
+	args = append(args, holder)
+	if ttl != "" {
+		args = append(args, ttl)
+	}
+
+	reply, err := command(conn, reader, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to run lease script: %w", err)
+	}
+	return reply, nil
+}
+
+// command writes args as a RESP array and reads an integer or status reply.
+func command(w io.Writer, r *bufio.Reader, args ...string) (int64, error) {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	if _, err := w.Write(buf); err != nil {
+		return 0, err
+	}
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if len(line) < 3 {
+		return 0, fmt.Errorf("malformed reply %q", line)
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '+':
+		return 0, nil
+	case '$':
+		// nil bulk string: the script returned false
+		if payload == "-1" {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("unexpected bulk reply")
+	case '-':
+		return 0, errors.New(payload)
+	default:
+		return 0, fmt.Errorf("unexpected reply %q", line)
+	}
+}
@@ -0,0 +1,134 @@
+package leader
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveRESP answers each command read from one connection with the next reply
+// and sends the commands it received on commands.
+func serveRESP(t *testing.T, replies []string) (string, <-chan []string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	commands := make(chan []string, len(replies))
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for _, reply := range replies {
+			header, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			count, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+
+			args := make([]string, 0, count)
+			for range count {
+				length, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				size, _ := strconv.Atoi(strings.TrimSpace(length[1:]))
+				arg := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, arg); err != nil {
+					return
+				}
+				args = append(args, string(arg[:size]))
+			}
+			commands <- args
+
+			if _, err := conn.Write([]byte(reply)); err != nil {
+				return
+			}
+		}
+	}()
+
+	return listener.Addr().String(), commands
+}
+
+func TestRedisLease_Acquire(t *testing.T) {
+	tests := []struct {
+		name             string
+		password         string
+		replies          []string
+		expectedToken    uint64
+		expectedAcquired bool
+		expectedErr      bool
+	}{
+		{
+			name:             "success - acquires with the returned token",
+			replies:          []string{":7\r\n"},
+			expectedToken:    7,
+			expectedAcquired: true,
+		},
+		{
+			name:             "success - another holder has the lease",
+			replies:          []string{":0\r\n"},
+			expectedAcquired: false,
+		},
+		{
+			name:             "success - authenticates before acquiring",
+			password:         "secret",
+			replies:          []string{"+OK\r\n", ":2\r\n"},
+			expectedToken:    2,
+			expectedAcquired: true,
+		},
+		{
+			name:        "error - server rejects the script",
+			replies:     []string{"-NOSCRIPT no scripting\r\n"},
+			expectedErr: true,
+		},
+		{
+			name:        "error - wrong password",
+			password:    "wrong",
+			replies:     []string{"-WRONGPASS invalid password\r\n"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, commands := serveRESP(t, tt.replies)
+			lease := NewRedisLease(RedisConfig{Addr: addr, Password: tt.password, Key: "auditory:leader", Timeout: time.Second})
+
+			token, acquired, err := lease.Acquire(context.Background(), "replica-a", 15*time.Second)
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got: %v", tt.expectedErr, err)
+			}
+			if token != tt.expectedToken || acquired != tt.expectedAcquired {
+				t.Errorf("expected token %d acquired %v, got %d %v", tt.expectedToken, tt.expectedAcquired, token, acquired)
+			}
+			if tt.expectedErr {
+				return
+			}
+
+			if tt.password != "" {
+				if auth := <-commands; auth[0] != "AUTH" || auth[1] != tt.password {
+					t.Errorf("expected AUTH command, got %v", auth)
+				}
+			}
+			eval := <-commands
+			expected := []string{"EVAL", acquireScript, "2", "auditory:leader", "auditory:leader:token", "replica-a", "15000"}
+			if strings.Join(eval, "|") != strings.Join(expected, "|") {
+				t.Errorf("expected command %q, got %q", expected, eval)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+// output is the handler every package logger writes through. Package loggers
+// are created at init time, before Setup runs, so they look it up per record.
+var output atomic.Pointer[config]
+
+type config struct {
+	handler slog.Handler
+	level   slog.Level
+	levels  map[string]slog.Level
+}
+
+func (c *config) levelFor(pkg string) slog.Level {
+	if level, ok := c.levels[pkg]; ok {
+		return level
+	}
+	return c.level
+}
+
+func init() {
+	output.Store(&config{
+		handler: slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		level:   slog.LevelInfo,
+	})
+}
+
+// Setup applies conf to every package logger and routes slog's and the log
+// package's defaults through them.
+func Setup(conf cfg.LogConfig) error {
+	return SetupWriter(os.Stderr, conf)
+}
+
+// SetupWriter is Setup writing to w.
+func SetupWriter(w io.Writer, conf cfg.LogConfig) error {
+	level, err := parseLevel(conf.Level)
+	if err != nil {
+		return err
+	}
+
+	levels := make(map[string]slog.Level, len(conf.Levels))
+	for pkg, value := range conf.Levels {
+		if levels[pkg], err = parseLevel(value); err != nil {
+			return fmt.Errorf("package %s: %w", pkg, err)
+		}
+	}
+
+	// filtering happens per package in Enabled, so the sink accepts everything
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+
+	var handler slog.Handler
+	switch conf.Format {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return fmt.Errorf("unknown log format: %s", conf.Format)
+	}
+
+	output.Store(&config{handler: handler, level: level, levels: levels})
+
+	// slog.SetDefault also sends the log package through this handler
+	slog.SetDefault(For("main"))
+
+	return nil
+}
+
+// For returns the logger of pkg. Its level comes from LOG_LEVELS[pkg], or
+// LOG_LEVEL, and every record carries the context fields.
+func For(pkg string) *slog.Logger {
+	return slog.New(&contextHandler{pkg: pkg})
+}
+
+func parseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: %w", value, err)
+	}
+	return level, nil
+}
+
+// contextHandler resolves the output on every record and adds the request,
+// correlation, client and audit key ids plus the trace found in ctx.
+type contextHandler struct {
+	pkg string
+	// wrap replays WithAttrs/WithGroup calls on the current output
+	wrap []func(slog.Handler) slog.Handler
+}
+
+func (ch *contextHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= output.Load().levelFor(ch.pkg)
+}
+
+func (ch *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	handler := output.Load().handler.WithAttrs([]slog.Attr{slog.String("package", ch.pkg)})
+	for _, wrap := range ch.wrap {
+		handler = wrap(handler)
+	}
+
+	record.AddAttrs(contextAttrs(ctx)...)
+	return handler.Handle(ctx, record)
+}
+
+func (ch *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ch.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (ch *contextHandler) WithGroup(name string) slog.Handler {
+	return ch.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+func (ch *contextHandler) with(wrap func(slog.Handler) slog.Handler) slog.Handler {
+	return &contextHandler{
+		pkg:  ch.pkg,
+		wrap: append(ch.wrap[:len(ch.wrap):len(ch.wrap)], wrap),
+	}
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	var attrs []slog.Attr
+	if requestID, ok := ctxkey.RequestID(ctx); ok && requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if correlationID, ok := ctxkey.CorrelationID(ctx); ok && correlationID != "" {
+		attrs = append(attrs, slog.String("correlation_id", correlationID))
+	}
+	if clientID, ok := ctxkey.ClientID(ctx); ok && clientID != "" {
+		attrs = append(attrs, slog.String("client_id", clientID))
+	}
+	if auditKey, ok := ctxkey.AuditKey(ctx); ok && auditKey != "" {
+		attrs = append(attrs, slog.String("audit_key", auditKey))
+	}
+	if tenant, ok := ctxkey.Tenant(ctx); ok && tenant != "" {
+		attrs = append(attrs, slog.String("tenant", tenant))
+	}
+	if traceID, spanID := telemetry.IDs(ctx); traceID != "" {
+		attrs = append(attrs, slog.String("trace_id", traceID), slog.String("span_id", spanID))
+	}
+
+	return attrs
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+func TestSetupWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    cfg.LogConfig
+		wantErr bool
+	}{
+		{name: "success - json with package levels", conf: cfg.LogConfig{Level: "info", Format: "json", Levels: map[string]string{"store": "debug"}}},
+		{name: "success - text", conf: cfg.LogConfig{Level: "warn", Format: "text"}},
+		{name: "error - unknown level", conf: cfg.LogConfig{Level: "verbose"}, wantErr: true},
+		{name: "error - unknown package level", conf: cfg.LogConfig{Level: "info", Levels: map[string]string{"store": "loud"}}, wantErr: true},
+		{name: "error - unknown format", conf: cfg.LogConfig{Level: "info", Format: "xml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := output.Load()
+			t.Cleanup(func() { output.Store(previous) })
+
+			err := SetupWriter(&bytes.Buffer{}, tt.conf)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFor(t *testing.T) {
+	previous := output.Load()
+	t.Cleanup(func() { output.Store(previous) })
+
+	var buf bytes.Buffer
+	if err := SetupWriter(&buf, cfg.LogConfig{Level: "info", Format: "json", Levels: map[string]string{"store": "debug"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := ctxkey.SetRequestID(context.Background(), "req-1")
+	ctx = ctxkey.SetCorrelationID(ctx, "corr-1")
+	ctx = ctxkey.SetClientID(ctx, "client-a")
+	ctx = ctxkey.SetAuditKey(ctx, "user:42")
+	ctx = ctxkey.SetTenant(ctx, "acme")
+
+	For("store").DebugContext(ctx, "upserted", "size", 10)
+	For("tasks").DebugContext(ctx, "filtered out by the default level")
+	For("tasks").With("task", "backup").InfoContext(context.Background(), "backup task running")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d: %s", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("expected json output: %v", err)
+	}
+	for key, expected := range map[string]any{
+		"level":          "DEBUG",
+		"package":        "store",
+		"request_id":     "req-1",
+		"correlation_id": "corr-1",
+		"client_id":      "client-a",
+		"audit_key":      "user:42",
+		"tenant":         "acme",
+	} {
+		if record[key] != expected {
+			t.Errorf("expected %s=%v, got %v", key, expected, record[key])
+		}
+	}
+
+	record = map[string]any{}
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("expected json output: %v", err)
+	}
+	if record["task"] != "backup" || record["package"] != "tasks" {
+		t.Errorf("expected logger attributes, got %v", record)
+	}
+	if _, ok := record["request_id"]; ok {
+		t.Errorf("expected no context fields without context values, got %v", record)
+	}
+}
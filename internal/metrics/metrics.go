@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "auditory"
+
+// Results of an ingested audit.
+const (
+	ResultAccepted  = "accepted"
+	ResultRejected  = "rejected"
+	ResultDuplicate = "duplicate"
+	ResultThrottled = "throttled"
+	ResultFailed    = "failed"
+)
+
+// Tasks with a last success timestamp.
+const (
+	TaskBackup    = "backup"
+	TaskStore     = "store"
+	TaskReconcile = "reconcile"
+)
+
+// What the local store quota did with an audit that did not fit.
+const (
+	QuotaRejected = "rejected"
+	QuotaDropped  = "dropped"
+	QuotaSpilled  = "spilled"
+)
+
+// Reconciliation issues.
+const (
+	IssueMissing = "missing"
+	IssueAltered = "altered"
+)
+
+// Registry holds every auditory collector plus the Go runtime and process
+// ones. A dedicated registry keeps tests free of global state from libraries.
+var Registry = prometheus.NewRegistry()
+
+var (
+	AuditsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audits_total",
+		Help:      "Audits received, by key namespace and result.",
+	}, []string{"key", "result"})
+
+	FileStoreUpsertDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "file_store_upsert_duration_seconds",
+		Help:      "Latency of DataFileStore.Upsert, including the file rewrite.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	FileStoreFileBytes = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "file_store_file_bytes",
+		Help:      "Size on disk of each local audit file after it is rewritten.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 10),
+	})
+
+	S3UploadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "s3_upload_bytes_total",
+		Help:      "Bytes uploaded to S3.",
+	})
+
+	S3UploadDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "s3_upload_duration_seconds",
+		Help:      "Latency of S3 uploads, successful or not.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	S3UploadErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "s3_upload_errors_total",
+		Help:      "Failed S3 uploads.",
+	})
+
+	TaskLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "task_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful run of a background task.",
+	}, []string{"task"})
+
+	ProxyUpstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "proxy_upstream_duration_seconds",
+		Help:      "Latency of upstream round trips made by the data plane proxy.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	PendingRequests = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pending_requests",
+		Help:      "Proxied requests waiting for their response to be paired into an audit.",
+	})
+
+	Leader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "leader",
+		Help:      "1 while this replica holds the leader lease and runs the archival tasks.",
+	})
+
+	LeaderToken = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "leader_fencing_token",
+		Help:      "Fencing token of the leader lease held by this replica, 0 when following.",
+	})
+
+	ClusterMembers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_members",
+		Help:      "Nodes in the hash ring as seen by this node, itself included.",
+	})
+
+	ClusterForwarded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cluster_forwarded_total",
+		Help:      "Audits forwarded to the node owning their key, by result; failed ones are kept for handoff.",
+	}, []string{"result"})
+
+	ClusterHandoffKeys = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cluster_handoff_keys_total",
+		Help:      "Keys handed off to the node owning them.",
+	})
+
+	WALApplyDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "wal_apply_duration_seconds",
+		Help:      "Time from proposing an audit to the write-ahead log until a majority persisted it.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	WALForwarded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "wal_forwarded_total",
+		Help:      "Audits a follower forwarded to the write-ahead log leader.",
+	})
+
+	ArchiveReconcileIssues = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "archive_reconcile_issues",
+		Help:      "Manifest objects found missing or altered in the bucket by the last reconciliation, by issue.",
+	}, []string{"issue"})
+
+	FileStoreBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "file_store_bytes",
+		Help:      "Bytes of the local audit files, as counted against the quota.",
+	})
+
+	FileStoreQuotaEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "file_store_quota_events_total",
+		Help:      "Events the local store quota rejected, dropped to make room or spilled, by action.",
+	}, []string{"action"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		AuditsTotal,
+		FileStoreUpsertDuration,
+		FileStoreFileBytes,
+		S3UploadBytes,
+		S3UploadDuration,
+		S3UploadErrors,
+		TaskLastSuccess,
+		ProxyUpstreamDuration,
+		PendingRequests,
+		Leader,
+		LeaderToken,
+		ClusterMembers,
+		ClusterForwarded,
+		ClusterHandoffKeys,
+		WALApplyDuration,
+		WALForwarded,
+		ArchiveReconcileIssues,
+		FileStoreBytes,
+		FileStoreQuotaEvents,
+	)
+}
+
+// Handler serves Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// otherKeyLabel counts the keys without a namespace.
+const otherKeyLabel = "other"
+
+// KeyLabel bounds the key label to the namespace before the first colon, so
+// "user:123" is counted as "user" instead of creating a series per subject.
+// Keys without a namespace would still be a series each and fall in "other".
+func KeyLabel(key string) string {
+	prefix, _, found := strings.Cut(key, ":")
+	if !found || prefix == "" {
+		return otherKeyLabel
+	}
+	return prefix
+}
+
+// ObserveAudit counts one audit of key with result.
+func ObserveAudit(key, result string) {
+	AuditsTotal.WithLabelValues(KeyLabel(key), result).Inc()
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestKeyLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{name: "success - namespaced key keeps the namespace", key: "user:123", expected: "user"},
+		{name: "success - nested key keeps the first segment", key: "order:42:item:7", expected: "order"},
+		{name: "success - plain key falls in other", key: "client-a", expected: "other"},
+		{name: "success - empty namespace falls in other", key: ":123", expected: "other"},
+		{name: "success - empty key", key: "", expected: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KeyLabel(tt.key); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	before := testutil.ToFloat64(AuditsTotal.WithLabelValues("invoice", ResultDuplicate))
+	ObserveAudit("invoice:9", ResultDuplicate)
+	if got := testutil.ToFloat64(AuditsTotal.WithLabelValues("invoice", ResultDuplicate)); got != before+1 {
+		t.Errorf("expected %v duplicates, got %v", before+1, got)
+	}
+
+	rr := httptest.NewRecorder()
+	Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, _ := io.ReadAll(rr.Body)
+	for _, expected := range []string{
+		`auditory_audits_total{key="invoice",result="duplicate"}`,
+		"auditory_file_store_upsert_duration_seconds",
+		"auditory_pending_requests",
+		"go_goroutines",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected %s in exposition", expected)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package scheduler
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("scheduler")
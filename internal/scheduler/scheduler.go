@@ -0,0 +1,246 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/health"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"github.com/robfig/cron/v3"
+)
+
+// parser accepts standard five-field expressions, an optional leading seconds
+// field and descriptors such as @daily or @every 10m.
+var parser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ErrSkipped is returned by a job that had nothing to do on this replica, such
+// as an archival task on a follower.
+var ErrSkipped = errors.New("task skipped")
+
+type StateStore interface {
+	Load() (map[string]RunState, error)
+	Save(name string, state RunState) error
+}
+
+type Job struct {
+	Name     string
+	Schedule string
+	Run      func(ctx context.Context) error
+}
+
+// TaskStatus is the GET /tasks view of a job.
+type TaskStatus struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	NextRun  time.Time `json:"next_run"`
+	Running  bool      `json:"running"`
+	RunState
+}
+
+type task struct {
+	job      Job
+	schedule cron.Schedule
+	next     time.Time
+	running  bool
+	state    RunState
+	hasState bool
+}
+
+type Config struct {
+	Location *time.Location
+	// Jitter delays every run by a random duration up to it, so replicas
+	// sharing a schedule do not hit the bucket at the same instant.
+	Jitter time.Duration
+}
+
+// Scheduler runs jobs on cron schedules. A job whose scheduled run was missed
+// while the process was down runs once as soon as it starts.
+type Scheduler struct {
+	mu       sync.Mutex
+	conf     Config
+	states   StateStore
+	tasks    map[string]*task
+	wg       sync.WaitGroup
+	after    func(d time.Duration) <-chan time.Time
+	jitterFn func(max time.Duration) time.Duration
+}
+
+func New(conf Config, states StateStore) *Scheduler {
+	if conf.Location == nil {
+		conf.Location = time.UTC
+	}
+	return &Scheduler{
+		conf:     conf,
+		states:   states,
+		tasks:    make(map[string]*task),
+		after:    time.After,
+		jitterFn: randomJitter,
+	}
+}
+
+// Add registers job; it must be called before Start.
+func (s *Scheduler) Add(job Job) error {
+	schedule, err := parser.Parse(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for task %s: %w", job.Schedule, job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.tasks[job.Name]; exists {
+		return fmt.Errorf("task %s already registered", job.Name)
+	}
+	s.tasks[job.Name] = &task{job: job, schedule: schedule}
+
+	return nil
+}
+
+// Start loads the persisted state and runs every job until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) error {
+	states, err := s.states.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := clock.Now().In(s.conf.Location)
+	for name, t := range s.tasks {
+		t.state, t.hasState = states[name]
+
+		// the liveness check expects a run about every interval
+		first := t.schedule.Next(now)
+		health.Tasks.Register(name, t.schedule.Next(first).Sub(first))
+
+		s.wg.Add(1)
+		go s.loop(ctx, t)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) Tasks() []TaskStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]TaskStatus, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		statuses = append(statuses, TaskStatus{
+			Name:     t.job.Name,
+			Schedule: t.job.Schedule,
+			NextRun:  t.next,
+			Running:  t.running,
+			RunState: t.state,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	return statuses
+}
+
+func (s *Scheduler) loop(ctx context.Context, t *task) {
+	defer s.wg.Done()
+
+	if s.missed(t) {
+		logger.InfoContext(ctx, "catching up missed task run", "task", t.job.Name, "last_run", t.state.LastRun)
+		s.run(ctx, t)
+	}
+
+	for {
+		next := s.scheduleNext(t)
+
+		select {
+		case <-ctx.Done():
+			logger.InfoContext(ctx, "task stopped", "task", t.job.Name)
+			return
+		case <-s.after(next.Sub(clock.Now())):
+			s.run(ctx, t)
+		}
+	}
+}
+
+// missed reports whether a run was due between the last successful run and
+// now, or whether no run ever succeeded: a follower skipping its runs or a
+// failing job catches up once it can. Tasks that never ran have nothing to
+// catch up on.
+func (s *Scheduler) missed(t *task) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !t.hasState || t.state.LastRun.IsZero() {
+		return false
+	}
+	lastSuccess := t.state.lastSuccess()
+	if lastSuccess.IsZero() {
+		return true
+	}
+	due := t.schedule.Next(lastSuccess.In(s.conf.Location))
+	return !due.After(clock.Now())
+}
+
+func (s *Scheduler) scheduleNext(t *task) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.next = t.schedule.Next(clock.Now().In(s.conf.Location)).Add(s.jitterFn(s.conf.Jitter))
+	return t.next
+}
+
+func (s *Scheduler) run(ctx context.Context, t *task) {
+	health.Tasks.Beat(t.job.Name)
+
+	s.mu.Lock()
+	t.running = true
+	s.mu.Unlock()
+
+	start := clock.Now()
+	err := t.job.Run(ctx)
+
+	s.mu.Lock()
+	lastSuccess := t.state.lastSuccess()
+	s.mu.Unlock()
+
+	state := RunState{
+		LastRun:      start,
+		LastOutcome:  OutcomeSuccess,
+		LastDuration: clock.Now().Sub(start).String(),
+		LastSuccess:  lastSuccess,
+	}
+	switch {
+	case errors.Is(err, ErrSkipped):
+		state.LastOutcome = OutcomeSkipped
+		logger.DebugContext(ctx, "task skipped", "task", t.job.Name)
+	case err != nil:
+		state.LastOutcome = OutcomeError
+		state.LastError = err.Error()
+		logger.ErrorContext(ctx, "task failed", "task", t.job.Name, "error", err)
+	default:
+		state.LastSuccess = start
+	}
+
+	s.mu.Lock()
+	t.running = false
+	t.state, t.hasState = state, true
+	s.mu.Unlock()
+
+	if err := s.states.Save(t.job.Name, state); err != nil {
+		logger.ErrorContext(ctx, "failed to persist task state", "task", t.job.Name, "error", err)
+	}
+}
+
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
@@ -0,0 +1,284 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+type memStateStore struct {
+	mu     sync.Mutex
+	states map[string]RunState
+}
+
+func newMemStateStore(states map[string]RunState) *memStateStore {
+	if states == nil {
+		states = make(map[string]RunState)
+	}
+	return &memStateStore{states: states}
+}
+
+func (m *memStateStore) Load() (map[string]RunState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	states := make(map[string]RunState, len(m.states))
+	for name, state := range m.states {
+		states[name] = state
+	}
+	return states, nil
+}
+
+func (m *memStateStore) Save(name string, state RunState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[name] = state
+	return nil
+}
+
+func (m *memStateStore) get(name string) (RunState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[name]
+	return state, ok
+}
+
+// newTestScheduler never fires on its own: every wait is reported on waits
+// and only ends when the context does.
+func newTestScheduler(conf Config, states StateStore) (*Scheduler, chan time.Duration) {
+	waits := make(chan time.Duration, 16)
+	s := New(conf, states)
+	s.after = func(d time.Duration) <-chan time.Time {
+		waits <- d
+		return nil
+	}
+	s.jitterFn = func(time.Duration) time.Duration { return 0 }
+	return s, waits
+}
+
+func TestScheduler_Add(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name        string
+		jobs        []Job
+		expectedErr bool
+	}{
+		{
+			name:        "success - five field expression",
+			jobs:        []Job{{Name: "store", Schedule: "0 0 * * *", Run: noop}},
+			expectedErr: false,
+		},
+		{
+			name:        "success - seconds field and descriptors",
+			jobs:        []Job{{Name: "backup", Schedule: "30 */5 * * * *", Run: noop}, {Name: "clear", Schedule: "@every 1m", Run: noop}},
+			expectedErr: false,
+		},
+		{
+			name:        "error - invalid expression",
+			jobs:        []Job{{Name: "store", Schedule: "every day", Run: noop}},
+			expectedErr: true,
+		},
+		{
+			name:        "error - duplicated task name",
+			jobs:        []Job{{Name: "store", Schedule: "@daily", Run: noop}, {Name: "store", Schedule: "@hourly", Run: noop}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Config{}, newMemStateStore(nil))
+
+			var err error
+			for _, job := range tt.jobs {
+				if err = s.Add(job); err != nil {
+					break
+				}
+			}
+
+			if (err != nil) != tt.expectedErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestScheduler_CatchUp(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	oldNow := clock.Now
+	clock.SetNow(now)
+	defer func() { clock.Now = oldNow }()
+
+	tests := []struct {
+		name         string
+		states       map[string]RunState
+		jobErr       error
+		expectedRuns int
+		expectedOut  string
+	}{
+		{
+			name:         "success - runs missed daily run once on start",
+			states:       map[string]RunState{"store": {LastRun: now.Add(-72 * time.Hour), LastOutcome: OutcomeSuccess}},
+			expectedRuns: 1,
+			expectedOut:  OutcomeSuccess,
+		},
+		{
+			name:         "success - does not run when no run was due",
+			states:       map[string]RunState{"store": {LastRun: now.Add(-time.Hour), LastOutcome: OutcomeSuccess}},
+			expectedRuns: 0,
+			expectedOut:  OutcomeSuccess,
+		},
+		{
+			name:         "success - does not run a task that never ran",
+			states:       nil,
+			expectedRuns: 0,
+		},
+		{
+			name:         "success - skipped run is recorded without error",
+			states:       map[string]RunState{"store": {LastRun: now.Add(-48 * time.Hour), LastOutcome: OutcomeSuccess}},
+			jobErr:       ErrSkipped,
+			expectedRuns: 1,
+			expectedOut:  OutcomeSkipped,
+		},
+		{
+			name:         "error - failed catch-up is recorded",
+			states:       map[string]RunState{"store": {LastRun: now.Add(-48 * time.Hour), LastOutcome: OutcomeSuccess}},
+			jobErr:       errors.New("bucket unavailable"),
+			expectedRuns: 1,
+			expectedOut:  OutcomeError,
+		},
+		{
+			name:         "success - a recent skipped run does not hide the missed one",
+			states:       map[string]RunState{"store": {LastRun: now.Add(-time.Hour), LastOutcome: OutcomeSkipped, LastSuccess: now.Add(-48 * time.Hour)}},
+			expectedRuns: 1,
+			expectedOut:  OutcomeSuccess,
+		},
+		{
+			name:         "success - a recent failed run is caught up",
+			states:       map[string]RunState{"store": {LastRun: now.Add(-time.Hour), LastOutcome: OutcomeError, LastSuccess: now.Add(-48 * time.Hour)}},
+			expectedRuns: 1,
+			expectedOut:  OutcomeSuccess,
+		},
+		{
+			name:         "success - a follower that never succeeded catches up",
+			states:       map[string]RunState{"store": {LastRun: now.Add(-time.Hour), LastOutcome: OutcomeSkipped}},
+			expectedRuns: 1,
+			expectedOut:  OutcomeSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := newMemStateStore(tt.states)
+			s, waits := newTestScheduler(Config{}, states)
+
+			runs := 0
+			if err := s.Add(Job{Name: "store", Schedule: "0 0 * * *", Run: func(ctx context.Context) error {
+				runs++
+				return tt.jobErr
+			}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if err := s.Start(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			<-waits
+			cancel()
+			s.Wait()
+
+			if runs != tt.expectedRuns {
+				t.Errorf("expected %d runs, got %d", tt.expectedRuns, runs)
+			}
+
+			state, _ := states.get("store")
+			if state.LastOutcome != tt.expectedOut {
+				t.Errorf("expected outcome %q, got %q", tt.expectedOut, state.LastOutcome)
+			}
+			if tt.expectedRuns > 0 && !state.LastRun.Equal(now) {
+				t.Errorf("expected last run %v, got %v", now, state.LastRun)
+			}
+			expectedSuccess := tt.states["store"].lastSuccess()
+			if tt.expectedRuns > 0 && tt.expectedOut == OutcomeSuccess {
+				expectedSuccess = now
+			}
+			if !state.lastSuccess().Equal(expectedSuccess) {
+				t.Errorf("expected last success %v, got %v", expectedSuccess, state.lastSuccess())
+			}
+			if tt.expectedOut == OutcomeError && state.LastError != tt.jobErr.Error() {
+				t.Errorf("expected last error %q, got %q", tt.jobErr.Error(), state.LastError)
+			}
+		})
+	}
+}
+
+func TestScheduler_NextRun(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	oldNow := clock.Now
+	clock.SetNow(now)
+	defer func() { clock.Now = oldNow }()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		schedule     string
+		location     *time.Location
+		jitter       time.Duration
+		expectedNext time.Time
+	}{
+		{
+			name:         "success - midnight in UTC",
+			schedule:     "0 0 * * *",
+			expectedNext: time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "success - midnight in the configured timezone",
+			schedule:     "0 0 * * *",
+			location:     saoPaulo,
+			expectedNext: time.Date(2026, 1, 11, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "success - jitter delays the run",
+			schedule:     "*/30 * * * *",
+			jitter:       7 * time.Second,
+			expectedNext: time.Date(2026, 1, 10, 12, 30, 7, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, waits := newTestScheduler(Config{Location: tt.location, Jitter: tt.jitter}, newMemStateStore(nil))
+			s.jitterFn = func(max time.Duration) time.Duration { return max }
+
+			if err := s.Add(Job{Name: "store", Schedule: tt.schedule, Run: func(ctx context.Context) error { return nil }}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if err := s.Start(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			wait := <-waits
+			cancel()
+			s.Wait()
+
+			if expected := tt.expectedNext.Sub(now); wait != expected {
+				t.Errorf("expected to wait %v, got %v", expected, wait)
+			}
+
+			tasks := s.Tasks()
+			if len(tasks) != 1 || !tasks[0].NextRun.Equal(tt.expectedNext) {
+				t.Errorf("expected next run %v, got %+v", tt.expectedNext, tasks)
+			}
+		})
+	}
+}
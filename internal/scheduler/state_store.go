@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+	OutcomeSkipped = "skipped"
+)
+
+// RunState is what survives a restart: the last run of a task and how it went.
+// LastSuccess only moves on success, so skipped and failed runs are caught up.
+type RunState struct {
+	LastRun      time.Time `json:"last_run"`
+	LastOutcome  string    `json:"last_outcome"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastDuration string    `json:"last_duration"`
+	LastSuccess  time.Time `json:"last_success,omitempty"`
+}
+
+// lastSuccess also reads states persisted before LastSuccess existed.
+func (rs RunState) lastSuccess() time.Time {
+	if rs.LastSuccess.IsZero() && rs.LastOutcome == OutcomeSuccess {
+		return rs.LastRun
+	}
+	return rs.LastSuccess
+}
+
+// FileStateStore keeps every task's RunState in {dir}/tasks.json.
+type FileStateStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileStateStore(dir string) (*FileStateStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create task state dir: %w", err)
+	}
+	return &FileStateStore{path: filepath.Join(dir, "tasks.json")}, nil
+}
+
+func (fss *FileStateStore) Load() (map[string]RunState, error) {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+	return fss.loadInternal()
+}
+
+func (fss *FileStateStore) Save(name string, state RunState) error {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	states, err := fss.loadInternal()
+	if err != nil {
+		return err
+	}
+	states[name] = state
+
+	payload, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal task state: %w", err)
+	}
+
+	tmpPath := fss.path + ".tmp"
+	if err := os.WriteFile(tmpPath, payload, 0644); err != nil {
+		return fmt.Errorf("failed to write task state: %w", err)
+	}
+	if err := os.Rename(tmpPath, fss.path); err != nil {
+		return fmt.Errorf("failed to write task state: %w", err)
+	}
+
+	return nil
+}
+
+// loadInternal reads the state file without acquiring lock (for internal use when lock is already held)
+func (fss *FileStateStore) loadInternal() (map[string]RunState, error) {
+	states := make(map[string]RunState)
+
+	payload, err := os.ReadFile(fss.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task state: %w", err)
+	}
+
+	if err := json.Unmarshal(payload, &states); err != nil {
+		return nil, fmt.Errorf("failed to decode task state: %w", err)
+	}
+	return states, nil
+}
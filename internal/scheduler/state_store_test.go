@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStateStore(t *testing.T) {
+	dir := t.TempDir()
+
+	fss, err := NewFileStateStore(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	states, err := fss.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(states) != 0 {
+		t.Fatalf("expected no state before the first save, got %v", states)
+	}
+
+	lastRun := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	if err := fss.Save("store", RunState{LastRun: lastRun, LastOutcome: OutcomeSuccess, LastDuration: "2s"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fss.Save("backup", RunState{LastRun: lastRun, LastOutcome: OutcomeError, LastError: "boom"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a new store reads what the previous process left behind
+	reopened, err := NewFileStateStore(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	states, err = reopened.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := states["store"]; !got.LastRun.Equal(lastRun) || got.LastOutcome != OutcomeSuccess {
+		t.Errorf("unexpected store state: %+v", got)
+	}
+	if got := states["backup"]; got.LastOutcome != OutcomeError || got.LastError != "boom" {
+		t.Errorf("unexpected backup state: %+v", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tasks.json.tmp")); !os.IsNotExist(err) {
+		t.Errorf("expected temporary file to be renamed, got %v", err)
+	}
+}
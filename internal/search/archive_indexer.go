@@ -0,0 +1,54 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/store"
+)
+
+type ArchiveReader interface {
+	Get(ctx context.Context, path string) ([]byte, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// IndexArchives adds every object Store archived, audits/{key}/{date}.json
+// and the hourly parts, to the index. The daily backups (audits/{date}.json)
+// are skipped since they only duplicate the local files, and so are the
+// manifests of the archived days.
+func IndexArchives(ctx context.Context, reader ArchiveReader, index DocIndex) (int, error) {
+	paths, err := reader.List(ctx, "audits/")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list archives: %w", err)
+	}
+
+	indexed := 0
+	for _, path := range paths {
+		_, day, ok := store.ParseArchivePath(path)
+		if !ok {
+			continue
+		}
+
+		payload, err := reader.Get(ctx, path)
+		if err != nil {
+			return indexed, fmt.Errorf("failed to read archive %s: %w", path, err)
+		}
+
+		data, err := store.DecodeArchive(path, day, payload)
+		if err != nil {
+			logger.WarnContext(ctx, "skipping archive", "path", path, "error", err)
+			continue
+		}
+
+		for _, audits := range data {
+			for _, doc := range audits {
+				if err := index.Add(doc); err != nil {
+					return indexed, err
+				}
+				indexed++
+			}
+		}
+	}
+
+	return indexed, nil
+}
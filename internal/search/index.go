@@ -0,0 +1,469 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+const allField = "_all"
+
+var ErrEmptyQuery = errors.New("query is empty")
+
+type docID int
+
+// docRef locates a document in docs.jsonl and keeps what Search filters and
+// sorts on; the document itself stays on disk.
+type docRef struct {
+	offset   int64
+	length   int
+	tenant   string
+	at       time.Time // event at, or stored at for audits without one
+	storedAt time.Time
+}
+
+// Index is an embedded inverted index over audits. Every document produces
+// terms "field:value" for metadata and flattened data paths (data.order.id)
+// plus free text terms under the _all field. Documents are appended to
+// docs.jsonl and read back from it for results; memory only holds their
+// references and the postings, rebuilt from the file on start. Without a
+// directory the documents are kept in memory instead.
+type Index struct {
+	mu        sync.RWMutex
+	refs      []docRef
+	seen      map[string]struct{}
+	postings  map[string]map[docID]struct{}
+	path      string
+	file      *os.File
+	size      int64
+	mem       []byte
+	retention time.Duration
+}
+
+func NewIndex(dir string) (*Index, error) {
+	idx := &Index{}
+	idx.reset()
+
+	if dir == "" {
+		return idx, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	idx.path = filepath.Join(dir, "docs.jsonl")
+	if err := idx.open(); err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// WithRetention makes documents stored more than days ago invisible to
+// Search and lets Prune drop them; zero keeps every document.
+func (idx *Index) WithRetention(days int) *Index {
+	idx.retention = time.Duration(days) * 24 * time.Hour
+	return idx
+}
+
+func (idx *Index) reset() {
+	idx.refs = nil
+	idx.seen = make(map[string]struct{})
+	idx.postings = make(map[string]map[docID]struct{})
+	idx.size = 0
+}
+
+// open reads docs.jsonl into the index and keeps it open for appends. A torn
+// last line after a crash is cut off so the next document starts on a line of
+// its own.
+func (idx *Index) open() error {
+	file, err := os.OpenFile(idx.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open index log: %w", err)
+	}
+
+	size, err := idx.load(file)
+	if err == nil {
+		err = file.Truncate(size)
+	}
+	if err == nil {
+		_, err = file.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to load index log: %w", err)
+	}
+
+	idx.file = file
+	return nil
+}
+
+// load indexes the documents of r and returns the size of its complete lines.
+func (idx *Index) load(r io.Reader) (int64, error) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var doc audit.DataAudit
+		if json.Unmarshal(line, &doc) == nil {
+			idx.addInternal(doc, offset, len(line))
+		}
+		offset += int64(len(line))
+	}
+}
+
+// Add indexes the audit unless the same event was already indexed, which
+// happens when archives are indexed after the live upserts.
+func (idx *Index) Add(doc audit.DataAudit) error {
+	doc.Data = normalize(doc.Data)
+
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.seen[docIdentity(doc.Metadata)]; ok {
+		return nil
+	}
+
+	offset := idx.size
+	if idx.file == nil {
+		idx.mem = append(idx.mem, payload...)
+	} else if _, err := idx.file.Write(payload); err != nil {
+		return fmt.Errorf("failed to write index log: %w", err)
+	}
+
+	idx.addInternal(doc, offset, len(payload))
+	return nil
+}
+
+// addInternal indexes without acquiring lock (for internal use when lock is already held)
+func (idx *Index) addInternal(doc audit.DataAudit, offset int64, length int) {
+	idx.size = offset + int64(length)
+
+	identity := docIdentity(doc.Metadata)
+	if _, ok := idx.seen[identity]; ok {
+		return
+	}
+	idx.seen[identity] = struct{}{}
+
+	storedAt, ok := audit.IDTime(doc.Metadata.ID)
+	if !ok {
+		storedAt = doc.Metadata.EventAt
+	}
+	if storedAt.IsZero() {
+		storedAt = clock.Now()
+	}
+	at := doc.Metadata.EventAt
+	if at.IsZero() {
+		at = storedAt
+	}
+
+	id := docID(len(idx.refs))
+	idx.refs = append(idx.refs, docRef{
+		offset:   offset,
+		length:   length,
+		tenant:   doc.Metadata.Tenant,
+		at:       at,
+		storedAt: storedAt,
+	})
+
+	for _, term := range terms(doc) {
+		postings, ok := idx.postings[term]
+		if !ok {
+			postings = make(map[docID]struct{})
+			idx.postings[term] = postings
+		}
+		postings[id] = struct{}{}
+	}
+}
+
+// Search returns the documents of tenant matching every clause of the query,
+// newest first; the empty tenant only sees the shared namespace. Clauses are
+// separated by spaces: "field:value" matches a metadata field or data path, a
+// bare word matches any indexed text.
+func (idx *Index) Search(tenant, query string, limit int) ([]audit.DataAudit, error) {
+	clauses, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var result map[docID]struct{}
+	for _, clause := range clauses {
+		matches := idx.postings[clause]
+		if result == nil {
+			result = make(map[docID]struct{}, len(matches))
+			for id := range matches {
+				result[id] = struct{}{}
+			}
+			continue
+		}
+		for id := range result {
+			if _, ok := matches[id]; !ok {
+				delete(result, id)
+			}
+		}
+	}
+
+	cutoff := idx.cutoff()
+	refs := make([]docRef, 0, len(result))
+	for id := range result {
+		if ref := idx.refs[id]; ref.tenant == tenant && !ref.storedAt.Before(cutoff) {
+			refs = append(refs, ref)
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].at.After(refs[j].at)
+	})
+
+	if limit > 0 && len(refs) > limit {
+		refs = refs[:limit]
+	}
+
+	output := make([]audit.DataAudit, 0, len(refs))
+	for _, ref := range refs {
+		doc, err := idx.read(ref)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, doc)
+	}
+
+	return output, nil
+}
+
+// read loads the document of ref from docs.jsonl, or from memory.
+func (idx *Index) read(ref docRef) (audit.DataAudit, error) {
+	payload, err := idx.readRaw(ref)
+	if err != nil {
+		return audit.DataAudit{}, err
+	}
+
+	var doc audit.DataAudit
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return audit.DataAudit{}, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return doc, nil
+}
+
+// cutoff is the oldest stored at Search returns, zero without retention.
+func (idx *Index) cutoff() time.Time {
+	if idx.retention <= 0 {
+		return time.Time{}
+	}
+	return clock.Now().Add(-idx.retention)
+}
+
+// Run prunes the index now and every period until ctx is done.
+func (idx *Index) Run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		if pruned, err := idx.Prune(); err != nil {
+			logger.ErrorContext(ctx, "failed to prune search index", "error", err)
+		} else if pruned > 0 {
+			logger.InfoContext(ctx, "search index pruned", "documents", pruned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune drops the documents older than the retention: docs.jsonl is rewritten
+// with the others and the index is rebuilt from it. It returns how many were
+// dropped.
+func (idx *Index) Prune() (int, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	cutoff := idx.cutoff()
+	var kept bytes.Buffer
+	pruned := 0
+	for _, ref := range idx.refs {
+		if ref.storedAt.Before(cutoff) {
+			pruned++
+			continue
+		}
+		payload, err := idx.readRaw(ref)
+		if err != nil {
+			return 0, err
+		}
+		kept.Write(payload)
+	}
+	if pruned == 0 {
+		return 0, nil
+	}
+
+	if idx.file == nil {
+		idx.mem = kept.Bytes()
+		idx.reset()
+		_, err := idx.load(bytes.NewReader(idx.mem))
+		return pruned, err
+	}
+
+	tmpPath := idx.path + ".tmp"
+	if err := os.WriteFile(tmpPath, kept.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write index log: %w", err)
+	}
+	if err := idx.file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close index log: %w", err)
+	}
+	if err := os.Rename(tmpPath, idx.path); err != nil {
+		return 0, fmt.Errorf("failed to replace index log: %w", err)
+	}
+
+	idx.reset()
+	return pruned, idx.open()
+}
+
+func (idx *Index) readRaw(ref docRef) ([]byte, error) {
+	if idx.file == nil {
+		return idx.mem[ref.offset : ref.offset+int64(ref.length)], nil
+	}
+	payload := make([]byte, ref.length)
+	if _, err := idx.file.ReadAt(payload, ref.offset); err != nil {
+		return nil, fmt.Errorf("failed to read index log: %w", err)
+	}
+	return payload, nil
+}
+
+func (idx *Index) Close() error {
+	if idx.file == nil {
+		return nil
+	}
+	return idx.file.Close()
+}
+
+// ParseQuery turns a query string into index terms.
+func ParseQuery(query string) ([]string, error) {
+	var clauses []string
+	for _, part := range strings.Fields(query) {
+		field, value, ok := strings.Cut(part, ":")
+		if !ok || field == "" || value == "" {
+			for _, token := range tokenize(part) {
+				clauses = append(clauses, allField+":"+token)
+			}
+			continue
+		}
+		clauses = append(clauses, strings.ToLower(field)+":"+strings.ToLower(value))
+	}
+
+	if len(clauses) == 0 {
+		return nil, ErrEmptyQuery
+	}
+
+	return clauses, nil
+}
+
+func terms(doc audit.DataAudit) []string {
+	fields := map[string][]string{
+		"key":            {doc.Metadata.Key},
+		"event_name":     {doc.Metadata.EventName},
+		"request_id":     {doc.Metadata.RequestID},
+		"correlation_id": {doc.Metadata.CorrelationID},
+		"trace_id":       {doc.Metadata.TraceID},
+	}
+	flatten("data", doc.Data, fields)
+
+	var output []string
+	for field, values := range fields {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			output = append(output, field+":"+strings.ToLower(value))
+			for _, token := range tokenize(value) {
+				output = append(output, field+":"+token, allField+":"+token)
+			}
+		}
+	}
+
+	return output
+}
+
+// flatten walks decoded JSON and records scalar values by dotted path.
+// Array elements share their parent's path so data.items.sku matches any item.
+func flatten(path string, value any, fields map[string][]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			flatten(path+"."+strings.ToLower(key), child, fields)
+		}
+	case []any:
+		for _, child := range v {
+			flatten(path, child, fields)
+		}
+	case string:
+		fields[path] = append(fields[path], v)
+	case float64:
+		fields[path] = append(fields[path], strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		fields[path] = append(fields[path], strconv.FormatBool(v))
+	}
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalize converts typed payloads (structs, typed maps) to plain JSON values.
+func normalize(data any) any {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+
+	var output any
+	if err := json.Unmarshal(payload, &output); err != nil {
+		return data
+	}
+
+	return output
+}
+
+// docIdentity is the event id, or for audits indexed before ids their
+// metadata.
+func docIdentity(m audit.MetadataAudit) string {
+	if m.ID != "" {
+		return m.ID
+	}
+	return strings.Join([]string{m.Tenant, m.Key, m.EventName, m.RequestID, m.CorrelationID, m.EventAt.Format(time.RFC3339Nano)}, "\x00")
+}
@@ -0,0 +1,264 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+var baseTime = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func seedIndex(t *testing.T, idx *Index) {
+	t.Helper()
+	docs := []audit.DataAudit{
+		{
+			Metadata: audit.MetadataAudit{Key: "order:42", EventName: "order.updated", RequestID: "req-1", CorrelationID: "corr-1", EventAt: baseTime},
+			Data:     map[string]any{"order": map[string]any{"id": 42, "status": "Paid"}, "changed_by": "Alice Smith"},
+		},
+		{
+			Metadata: audit.MetadataAudit{Key: "order:42", EventName: "order.created", RequestID: "req-2", CorrelationID: "corr-2", EventAt: baseTime.Add(-time.Hour)},
+			Data:     map[string]any{"order": map[string]any{"id": 42, "status": "pending"}, "changed_by": "Bob"},
+		},
+		{
+			Metadata: audit.MetadataAudit{Key: "user:7", EventName: "user.deleted", RequestID: "req-3", CorrelationID: "corr-3", EventAt: baseTime.Add(time.Hour)},
+			Data:     map[string]any{"items": []any{map[string]any{"sku": "A1"}, map[string]any{"sku": "B2"}}},
+		},
+	}
+
+	for _, doc := range docs {
+		if err := idx.Add(doc); err != nil {
+			t.Fatalf("failed to add document: %v", err)
+		}
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx, err := NewIndex("")
+	if err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+	seedIndex(t, idx)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedReqs  []string
+		expectedError error
+	}{
+		{name: "success - metadata field", query: "key:order:42", expectedReqs: []string{"req-1", "req-2"}},
+		{name: "success - nested data path", query: "data.order.status:paid", expectedReqs: []string{"req-1"}},
+		{name: "success - numeric data path", query: "data.order.id:42", expectedReqs: []string{"req-1", "req-2"}},
+		{name: "success - array elements share path", query: "data.items.sku:b2", expectedReqs: []string{"req-3"}},
+		{name: "success - field token", query: "data.changed_by:alice", expectedReqs: []string{"req-1"}},
+		{name: "success - free text", query: "alice", expectedReqs: []string{"req-1"}},
+		{name: "success - clauses are combined", query: "key:order:42 bob", expectedReqs: []string{"req-2"}},
+		{name: "success - no match", query: "event_name:order.refunded", expectedReqs: []string{}},
+		{name: "error - empty query", query: "   ", expectedError: ErrEmptyQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := idx.Search("", tt.query, 0)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError != nil {
+				return
+			}
+
+			if len(results) != len(tt.expectedReqs) {
+				t.Fatalf("expected %d results, got %d", len(tt.expectedReqs), len(results))
+			}
+			for i, requestID := range tt.expectedReqs {
+				if results[i].Metadata.RequestID != requestID {
+					t.Errorf("expected result %d to be %s, got %s", i, requestID, results[i].Metadata.RequestID)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_Limit(t *testing.T) {
+	idx, _ := NewIndex("")
+	seedIndex(t, idx)
+
+	results, err := idx.Search("", "key:order:42", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Metadata.RequestID != "req-1" {
+		t.Errorf("expected newest result only, got %v", results)
+	}
+}
+
+func TestIndex_Tenant(t *testing.T) {
+	idx, _ := NewIndex("")
+	seedIndex(t, idx)
+
+	// the same event of a tenant is a document of its own
+	doc := audit.DataAudit{
+		Metadata: audit.MetadataAudit{Key: "order:42", EventName: "order.updated", RequestID: "req-1", CorrelationID: "corr-1", EventAt: baseTime, Tenant: "acme"},
+		Data:     map[string]any{"order": map[string]any{"id": 42}},
+	}
+	if err := idx.Add(doc); err != nil {
+		t.Fatalf("failed to add document: %v", err)
+	}
+
+	results, _ := idx.Search("acme", "key:order:42", 0)
+	if len(results) != 1 || results[0].Metadata.Tenant != "acme" {
+		t.Errorf("expected the tenant document only, got %v", results)
+	}
+	if results, _ = idx.Search("", "key:order:42", 0); len(results) != 2 {
+		t.Errorf("expected the shared documents only, got %v", results)
+	}
+	if results, _ = idx.Search("globex", "key:order:42", 0); len(results) != 0 {
+		t.Errorf("expected nothing for another tenant, got %v", results)
+	}
+}
+
+func TestIndex_Persistence(t *testing.T) {
+	dir := t.TempDir()
+
+	idx, err := NewIndex(dir)
+	if err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+	seedIndex(t, idx)
+	// duplicates must not be written twice
+	seedIndex(t, idx)
+	idx.Close()
+
+	reopened, err := NewIndex(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen index: %v", err)
+	}
+	defer reopened.Close()
+
+	results, err := reopened.Search("", "data.order.id:42", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("expected 2 results after reload, got %d", len(results))
+	}
+}
+
+func TestIndex_Retention(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	// data plane exchanges carry no event at, their age is that of their id
+	stored := func(requestID string, storedAt time.Time) audit.DataAudit {
+		clock.SetNow(storedAt)
+		return audit.DataAudit{
+			Metadata: audit.MetadataAudit{ID: audit.NewID(), Key: "client-a", EventName: audit.HttpAuditEvent, RequestID: requestID, CorrelationID: "corr-1"},
+			Data:     map[string]any{"status": "paid"},
+		}
+	}
+	old := stored("req-old", baseTime.Add(-10*24*time.Hour))
+	recent := stored("req-recent", baseTime.Add(-time.Hour))
+	clock.SetNow(baseTime)
+
+	idx, err := NewIndex(dir)
+	if err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+	idx.WithRetention(7)
+	for _, doc := range []audit.DataAudit{old, recent} {
+		if err := idx.Add(doc); err != nil {
+			t.Fatalf("failed to add document: %v", err)
+		}
+	}
+
+	if results, _ := idx.Search("", "paid", 0); len(results) != 1 || results[0].Metadata.RequestID != "req-recent" {
+		t.Errorf("expected expired documents to be hidden, got %v", results)
+	}
+
+	pruned, err := idx.Prune()
+	if err != nil || pruned != 1 {
+		t.Fatalf("expected 1 document pruned, got %d %v", pruned, err)
+	}
+	if err := idx.Add(stored("req-new", baseTime)); err != nil {
+		t.Fatalf("failed to add document: %v", err)
+	}
+	idx.Close()
+
+	reopened, err := NewIndex(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen index: %v", err)
+	}
+	defer reopened.Close()
+
+	results, _ := reopened.Search("", "paid", 0)
+	if len(results) != 2 || results[0].Metadata.RequestID != "req-new" || results[1].Metadata.RequestID != "req-recent" {
+		t.Errorf("expected the kept and the new documents after the rewrite, got %v", results)
+	}
+}
+
+func TestIndex_TornLine(t *testing.T) {
+	dir := t.TempDir()
+
+	idx, _ := NewIndex(dir)
+	seedIndex(t, idx)
+	idx.Close()
+
+	// a crash in the middle of an append
+	file, _ := os.OpenFile(filepath.Join(dir, "docs.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = file.WriteString(`{"metadata":{"key":"order:4`)
+	file.Close()
+
+	idx, err := NewIndex(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen index: %v", err)
+	}
+	if err := idx.Add(audit.DataAudit{Metadata: audit.MetadataAudit{Key: "order:43", EventName: "order.created", RequestID: "req-4", CorrelationID: "corr-4", EventAt: baseTime}}); err != nil {
+		t.Fatalf("failed to add document: %v", err)
+	}
+	idx.Close()
+
+	reopened, _ := NewIndex(dir)
+	defer reopened.Close()
+	if results, _ := reopened.Search("", "key:order:43", 0); len(results) != 1 {
+		t.Errorf("expected the document written after the torn line, got %v", results)
+	}
+}
+
+func TestIndexArchives(t *testing.T) {
+	storage, err := store.NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	ctx := context.Background()
+	archived := `{"2025-1-14":[{"metadata":{"key":"order:42","event_name":"order.shipped","request_id":"req-9","correlation_id":"corr-9","event_at":"2025-01-14T10:00:00Z"},"data":{"carrier":"DHL"}}]}`
+	_ = storage.Put(ctx, "audits/order:42/2025-01-14.json", []byte(archived), baseTime)
+	_ = storage.Put(ctx, "audits/2025-01-14.json", []byte(`{"order:42":{}}`), baseTime)
+	part := `{"metadata":{"key":"order:43","event_name":"order.shipped","request_id":"req-10","correlation_id":"corr-10","event_at":"2025-01-15T09:00:00Z"},"data":{"carrier":"UPS"}}` + "\n"
+	_ = storage.Put(ctx, "audits/key=order:43/date=2025-01-15/hour=09/part-0.jsonl", []byte(part), baseTime)
+
+	idx, _ := NewIndex("")
+	indexed, err := IndexArchives(ctx, storage, idx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if indexed != 2 {
+		t.Errorf("expected 2 indexed audits, got %d", indexed)
+	}
+
+	results, _ := idx.Search("", "data.carrier:dhl", 0)
+	if len(results) != 1 {
+		t.Errorf("expected archived audit to be searchable, got %d results", len(results))
+	}
+}
@@ -0,0 +1,44 @@
+package search
+
+//go:generate mockgen -source=indexed_store.go -destination=mocks/mock_indexed_store.go -package=mocks
+
+import (
+	"context"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+type AuditStore interface {
+	Upsert(ctx context.Context, input audit.DataAudit) error
+}
+
+type DocIndex interface {
+	Add(doc audit.DataAudit) error
+}
+
+// IndexedStore indexes every audit accepted by the wrapped store. Indexing
+// failures are logged only: the audit is already durable and the index can be
+// rebuilt from the archives.
+type IndexedStore struct {
+	store AuditStore
+	index DocIndex
+}
+
+func NewIndexedStore(store AuditStore, index DocIndex) *IndexedStore {
+	return &IndexedStore{
+		store: store,
+		index: index,
+	}
+}
+
+func (is *IndexedStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	if err := is.store.Upsert(ctx, input); err != nil {
+		return err
+	}
+
+	if err := is.index.Add(input); err != nil {
+		logger.ErrorContext(ctx, "failed to index audit", "key", input.Metadata.Key, "event_name", input.Metadata.EventName, "error", err)
+	}
+
+	return nil
+}
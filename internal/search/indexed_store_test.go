@@ -0,0 +1,59 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/search/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIndexedStore_Upsert(t *testing.T) {
+	input := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.created"}}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(store *mocks.MockAuditStore, index *mocks.MockDocIndex)
+		expectedError bool
+	}{
+		{
+			name: "success - stores and indexes",
+			setupMocks: func(store *mocks.MockAuditStore, index *mocks.MockDocIndex) {
+				store.EXPECT().Upsert(gomock.Any(), input).Return(nil)
+				index.EXPECT().Add(input).Return(nil)
+			},
+		},
+		{
+			name: "success - index failure is not returned",
+			setupMocks: func(store *mocks.MockAuditStore, index *mocks.MockDocIndex) {
+				store.EXPECT().Upsert(gomock.Any(), input).Return(nil)
+				index.EXPECT().Add(input).Return(errors.New("disk full"))
+			},
+		},
+		{
+			name: "error - store fails, nothing indexed",
+			setupMocks: func(store *mocks.MockAuditStore, index *mocks.MockDocIndex) {
+				store.EXPECT().Upsert(gomock.Any(), input).Return(errors.New("write failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mocks.NewMockAuditStore(ctrl)
+			index := mocks.NewMockDocIndex(ctrl)
+			tt.setupMocks(store, index)
+
+			err := NewIndexedStore(store, index).Upsert(context.Background(), input)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
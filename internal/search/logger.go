@@ -0,0 +1,5 @@
+package search
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("search")
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/search/indexed_store.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/search/indexed_store.go -destination=internal/search/mocks/mock_indexed_store.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockAuditStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockAuditStoreMockRecorder) Upsert(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockAuditStore)(nil).Upsert), ctx, input)
+}
+
+// MockDocIndex is a mock of DocIndex interface.
+type MockDocIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocIndexMockRecorder
+	isgomock struct{}
+}
+
+// MockDocIndexMockRecorder is the mock recorder for MockDocIndex.
+type MockDocIndexMockRecorder struct {
+	mock *MockDocIndex
+}
+
+// NewMockDocIndex creates a new mock instance.
+func NewMockDocIndex(ctrl *gomock.Controller) *MockDocIndex {
+	mock := &MockDocIndex{ctrl: ctrl}
+	mock.recorder = &MockDocIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocIndex) EXPECT() *MockDocIndexMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockDocIndex) Add(doc audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", doc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockDocIndexMockRecorder) Add(doc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockDocIndex)(nil).Add), doc)
+}
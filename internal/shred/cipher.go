@@ -0,0 +1,122 @@
+package shred
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+)
+
+const Algorithm = "AES-256-GCM"
+
+var ErrNotEncrypted = errors.New("data is not encrypted")
+
+// Envelope replaces DataAudit.Data on disk and in the bucket. Metadata stays in
+// clear text so the trail remains verifiable after the subject key is destroyed.
+type Envelope struct {
+	Algorithm  string `json:"alg"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// SubjectKey names the subject of metadata in the key store and in the
+// additional data: "{tenant}/{key}" with the key encoded, so tenants sharing a
+// key never share its encryption key. Subjects without tenant keep their key as
+// it is, the name their audits were encrypted under before tenants.
+func SubjectKey(metadata audit.MetadataAudit) string {
+	if metadata.Tenant == "" {
+		return metadata.Key
+	}
+	return metadata.Tenant + "/" + store.EncodeKey(store.Key(metadata.Key))
+}
+
+func Encrypt(dek []byte, input audit.DataAudit) (audit.DataAudit, error) {
+	plaintext, err := json.Marshal(input.Data)
+	if err != nil {
+		return audit.DataAudit{}, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return audit.DataAudit{}, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return audit.DataAudit{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// the subject is bound as additional data so an envelope cannot be moved
+	// to another subject's or tenant's trail
+	input.Data = Envelope{
+		Algorithm:  Algorithm,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, []byte(SubjectKey(input.Metadata))),
+	}
+
+	return input, nil
+}
+
+func Decrypt(dek []byte, input audit.DataAudit) (audit.DataAudit, error) {
+	envelope, err := ParseEnvelope(input.Data)
+	if err != nil {
+		return audit.DataAudit{}, err
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return audit.DataAudit{}, err
+	}
+
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, []byte(SubjectKey(input.Metadata)))
+	if err != nil {
+		return audit.DataAudit{}, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	var data any
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return audit.DataAudit{}, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+
+	input.Data = data
+	return input, nil
+}
+
+// ParseEnvelope accepts both a typed Envelope and the generic map produced when
+// a stored file is decoded back into audit.DataAudit.
+func ParseEnvelope(data any) (Envelope, error) {
+	if envelope, ok := data.(Envelope); ok {
+		return envelope, nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil || envelope.Algorithm != Algorithm {
+		return Envelope{}, ErrNotEncrypted
+	}
+
+	return envelope, nil
+}
+
+func newGCM(dek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,63 @@
+package shred
+
+//go:generate mockgen -source=decrypting_search.go -destination=mocks/mock_decrypting_search.go -package=mocks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+)
+
+type SearchService interface {
+	Search(tenant, query string, limit int) ([]audit.DataAudit, error)
+}
+
+// DecryptingSearch is the read path of encrypted audits: it decrypts the
+// results of the wrapped search with the subject's key. Audits of a shredded
+// subject keep their envelope, so the trail stays visible but unreadable.
+type DecryptingSearch struct {
+	searchService SearchService
+	keyStore      KeyStore
+}
+
+func NewDecryptingSearch(searchService SearchService, keyStore KeyStore) *DecryptingSearch {
+	return &DecryptingSearch{
+		searchService: searchService,
+		keyStore:      keyStore,
+	}
+}
+
+func (ds *DecryptingSearch) Search(tenant, query string, limit int) ([]audit.DataAudit, error) {
+	results, err := ds.searchService.Search(tenant, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if results[i], err = Open(ds.keyStore, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// Open decrypts input when it is encrypted and its subject key still exists;
+// otherwise input is returned as it is.
+func Open(keyStore KeyStore, input audit.DataAudit) (audit.DataAudit, error) {
+	if _, err := ParseEnvelope(input.Data); err != nil {
+		return input, nil
+	}
+
+	dek, err := keyStore.Get(SubjectKey(input.Metadata))
+	switch {
+	case errors.Is(err, store.ErrKeyDestroyed), errors.Is(err, store.ErrKeyNotFound):
+		return input, nil
+	case err != nil:
+		return audit.DataAudit{}, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	return Decrypt(dek, input)
+}
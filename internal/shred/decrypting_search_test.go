@@ -0,0 +1,98 @@
+package shred
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/shred/mocks"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDecryptingSearch_Search(t *testing.T) {
+	plain := audit.DataAudit{
+		Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.created"},
+		Data:     map[string]any{"name": "John"},
+	}
+	encrypted, err := Encrypt(testKey, plain)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tenantPlain := plain
+	tenantPlain.Metadata.Tenant = "acme"
+	tenantEncrypted, err := Encrypt(testKey, tenantPlain)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(searchService *mocks.MockSearchService, keyStore *mocks.MockKeyStore)
+		expectedClear bool
+		expectedError bool
+	}{
+		{
+			name: "success - decrypts results",
+			setupMocks: func(searchService *mocks.MockSearchService, keyStore *mocks.MockKeyStore) {
+				searchService.EXPECT().Search("", "john", 10).Return([]audit.DataAudit{encrypted}, nil)
+				keyStore.EXPECT().Get("user:123").Return(testKey, nil)
+			},
+			expectedClear: true,
+		},
+		{
+			name: "success - tenant results are decrypted with the tenant key",
+			setupMocks: func(searchService *mocks.MockSearchService, keyStore *mocks.MockKeyStore) {
+				searchService.EXPECT().Search("", "john", 10).Return([]audit.DataAudit{tenantEncrypted}, nil)
+				keyStore.EXPECT().Get("acme/user%3A123").Return(testKey, nil)
+			},
+			expectedClear: true,
+		},
+		{
+			name: "success - shredded subject keeps its envelope",
+			setupMocks: func(searchService *mocks.MockSearchService, keyStore *mocks.MockKeyStore) {
+				searchService.EXPECT().Search("", "john", 10).Return([]audit.DataAudit{encrypted}, nil)
+				keyStore.EXPECT().Get("user:123").Return(nil, store.ErrKeyDestroyed)
+			},
+		},
+		{
+			name: "success - clear text results are returned as they are",
+			setupMocks: func(searchService *mocks.MockSearchService, keyStore *mocks.MockKeyStore) {
+				searchService.EXPECT().Search("", "john", 10).Return([]audit.DataAudit{plain}, nil)
+			},
+			expectedClear: true,
+		},
+		{
+			name: "error - key store fails",
+			setupMocks: func(searchService *mocks.MockSearchService, keyStore *mocks.MockKeyStore) {
+				searchService.EXPECT().Search("", "john", 10).Return([]audit.DataAudit{encrypted}, nil)
+				keyStore.EXPECT().Get("user:123").Return(nil, errors.New("disk failure"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			searchService := mocks.NewMockSearchService(ctrl)
+			keyStore := mocks.NewMockKeyStore(ctrl)
+			tt.setupMocks(searchService, keyStore)
+
+			results, err := NewDecryptingSearch(searchService, keyStore).Search("", "john", 10)
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError {
+				return
+			}
+
+			data, ok := results[0].Data.(map[string]any)
+			if clear := ok && data["name"] == "John"; clear != tt.expectedClear {
+				t.Errorf("expected clear text %v, got %v", tt.expectedClear, results[0].Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package shred
+
+//go:generate mockgen -source=encrypted_store.go -destination=mocks/mock_encrypted_store.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+type KeyStore interface {
+	Get(key string) ([]byte, error)
+	GetOrCreate(key string) ([]byte, error)
+	Destroy(key string) error
+}
+
+type AuditStore interface {
+	Upsert(ctx context.Context, input audit.DataAudit) error
+}
+
+// EncryptedStore encrypts DataAudit.Data with the subject's key before handing
+// it to the wrapped store, so everything written locally and later shipped to
+// the bucket is ciphertext.
+type EncryptedStore struct {
+	store    AuditStore
+	keyStore KeyStore
+}
+
+func NewEncryptedStore(store AuditStore, keyStore KeyStore) *EncryptedStore {
+	return &EncryptedStore{
+		store:    store,
+		keyStore: keyStore,
+	}
+}
+
+func (es *EncryptedStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	dek, err := es.keyStore.GetOrCreate(SubjectKey(input.Metadata))
+	if err != nil {
+		return fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	encrypted, err := Encrypt(dek, input)
+	if err != nil {
+		return err
+	}
+
+	return es.store.Upsert(ctx, encrypted)
+}
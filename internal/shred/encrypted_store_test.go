@@ -0,0 +1,166 @@
+package shred
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/shred/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestEncryptDecrypt(t *testing.T) {
+	input := audit.DataAudit{
+		Metadata: audit.MetadataAudit{
+			Key:           "user:123",
+			EventName:     "user.created",
+			RequestID:     "req-123",
+			CorrelationID: "corr-123",
+			EventAt:       time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+		Data: map[string]any{"name": "John"},
+	}
+
+	encrypted, err := Encrypt(testKey, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if encrypted.Metadata != input.Metadata {
+		t.Errorf("expected metadata to stay in clear text")
+	}
+
+	if _, ok := encrypted.Data.(Envelope); !ok {
+		t.Fatalf("expected data to be an envelope, got %T", encrypted.Data)
+	}
+
+	decrypted, err := Decrypt(testKey, encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, ok := decrypted.Data.(map[string]any)
+	if !ok || data["name"] != "John" {
+		t.Errorf("expected decrypted data, got %v", decrypted.Data)
+	}
+
+	otherKey := []byte("fedcba9876543210fedcba9876543210")
+	if _, err := Decrypt(otherKey, encrypted); err == nil {
+		t.Errorf("expected error decrypting with another key")
+	}
+
+	moved := encrypted
+	moved.Metadata.Tenant = "acme"
+	if _, err := Decrypt(testKey, moved); err == nil {
+		t.Errorf("expected error decrypting under another tenant")
+	}
+
+	encrypted.Metadata.Key = "user:456"
+	if _, err := Decrypt(testKey, encrypted); err == nil {
+		t.Errorf("expected error decrypting under another subject")
+	}
+}
+
+func TestSubjectKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata audit.MetadataAudit
+		expected string
+	}{
+		{name: "success - subject without tenant keeps its key", metadata: audit.MetadataAudit{Key: "user:123"}, expected: "user:123"},
+		{name: "success - tenant subject is namespaced", metadata: audit.MetadataAudit{Tenant: "acme", Key: "user:123"}, expected: "acme/user%3A123"},
+		{name: "success - slash key without tenant keeps its key", metadata: audit.MetadataAudit{Key: "acme/user:123"}, expected: "acme/user:123"},
+		{name: "success - slash key of a tenant stays in it", metadata: audit.MetadataAudit{Tenant: "acme", Key: "x/user:123"}, expected: "acme/x%2Fuser%3A123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubjectKey(tt.metadata); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecrypt_NotEncrypted(t *testing.T) {
+	_, err := Decrypt(testKey, audit.DataAudit{Data: map[string]any{"name": "John"}})
+	if !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("expected ErrNotEncrypted, got %v", err)
+	}
+}
+
+func TestEncryptedStore_Upsert(t *testing.T) {
+	input := audit.DataAudit{
+		Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.created"},
+		Data:     map[string]any{"name": "John"},
+	}
+
+	tests := []struct {
+		name          string
+		tenant        string
+		setupMocks    func(store *mocks.MockAuditStore, keyStore *mocks.MockKeyStore)
+		expectedError bool
+	}{
+		{
+			name: "success - stores encrypted data",
+			setupMocks: func(store *mocks.MockAuditStore, keyStore *mocks.MockKeyStore) {
+				keyStore.EXPECT().GetOrCreate("user:123").Return(testKey, nil)
+				store.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, stored audit.DataAudit) error {
+						if _, ok := stored.Data.(Envelope); !ok {
+							t.Errorf("expected envelope, got %T", stored.Data)
+						}
+						return nil
+					})
+			},
+			expectedError: false,
+		},
+		{
+			name:   "success - tenant subject gets its own key",
+			tenant: "acme",
+			setupMocks: func(store *mocks.MockAuditStore, keyStore *mocks.MockKeyStore) {
+				keyStore.EXPECT().GetOrCreate("acme/user%3A123").Return(testKey, nil)
+				store.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error - key store fails",
+			setupMocks: func(store *mocks.MockAuditStore, keyStore *mocks.MockKeyStore) {
+				keyStore.EXPECT().GetOrCreate("user:123").Return(nil, errors.New("disk full"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "error - inner store fails",
+			setupMocks: func(store *mocks.MockAuditStore, keyStore *mocks.MockKeyStore) {
+				keyStore.EXPECT().GetOrCreate("user:123").Return(testKey, nil)
+				store.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("write failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mocks.NewMockAuditStore(ctrl)
+			keyStore := mocks.NewMockKeyStore(ctrl)
+			tt.setupMocks(store, keyStore)
+
+			input := input
+			input.Metadata.Tenant = tt.tenant
+
+			es := NewEncryptedStore(store, keyStore)
+			err := es.Upsert(context.Background(), input)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
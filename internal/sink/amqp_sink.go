@@ -0,0 +1,112 @@
+package sink
+
+//go:generate mockgen -source=amqp_sink.go -destination=mocks/mock_amqp_sink.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQPPublisher publishes one message and returns once the broker confirmed it.
+type AMQPPublisher interface {
+	Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error
+	Close() error
+}
+
+type AMQPConfig struct {
+	URL        string
+	Exchange   string
+	RoutingKey string
+	Partitions int
+}
+
+// AMQPSink publishes persistent messages to an exchange with routing key
+// "{routingKey}.{partition}", so a topic or direct exchange can bind one
+// queue per partition.
+type AMQPSink struct {
+	publisher  AMQPPublisher
+	exchange   string
+	routingKey string
+	partitions int
+}
+
+func NewAMQPSink(conf AMQPConfig) (*AMQPSink, error) {
+	conn, err := amqp.Dial(conf.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to amqp: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open amqp channel: %w", err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	publisher := &confirmingChannel{conn: conn, channel: channel}
+	return NewAMQPSinkWithPublisher(publisher, conf.Exchange, conf.RoutingKey, conf.Partitions), nil
+}
+
+func NewAMQPSinkWithPublisher(publisher AMQPPublisher, exchange, routingKey string, partitions int) *AMQPSink {
+	return &AMQPSink{
+		publisher:  publisher,
+		exchange:   exchange,
+		routingKey: routingKey,
+		partitions: partitions,
+	}
+}
+
+func (as *AMQPSink) Publish(ctx context.Context, msgs []Message) error {
+	for _, msg := range msgs {
+		routingKey := fmt.Sprintf("%s.%d", as.routingKey, Partition(msg.Key, as.partitions))
+		publishing := amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.ID,
+			Headers:      amqp.Table{"audit-key": msg.Key},
+			Body:         msg.Value,
+		}
+
+		if err := as.publisher.Publish(ctx, as.exchange, routingKey, publishing); err != nil {
+			return fmt.Errorf("failed to publish to amqp: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (as *AMQPSink) Close() error {
+	return as.publisher.Close()
+}
+
+type confirmingChannel struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+func (cc *confirmingChannel) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	confirmation, err := cc.channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, msg)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return fmt.Errorf("message %s was nacked by the broker", msg.MessageId)
+	}
+
+	return nil
+}
+
+func (cc *confirmingChannel) Close() error {
+	return cc.conn.Close()
+}
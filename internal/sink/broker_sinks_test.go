@@ -0,0 +1,119 @@
+package sink_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/sink"
+	"github.com/IsaacDSC/auditory/internal/sink/mocks"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/mock/gomock"
+)
+
+var brokerBatch = []sink.Message{
+	{ID: "m1", Key: "user:42", Value: []byte(`{"n":1}`)},
+	{ID: "m2", Key: "user:42", Value: []byte(`{"n":2}`)},
+}
+
+func TestPartition(t *testing.T) {
+	for _, key := range []string{"user:42", "order:1", ""} {
+		first := sink.Partition(key, 16)
+		if first < 0 || first >= 16 {
+			t.Fatalf("partition %d out of range for %q", first, key)
+		}
+		if again := sink.Partition(key, 16); again != first {
+			t.Errorf("expected stable partition for %q, got %d and %d", key, first, again)
+		}
+	}
+
+	if got := sink.Partition("user:42", 0); got != 0 {
+		t.Errorf("expected partition 0 without partitions, got %d", got)
+	}
+}
+
+func TestKafkaSink_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mocks.NewMockKafkaWriter(ctrl)
+	writer.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+		if len(msgs) != 2 {
+			t.Fatalf("expected 2 records, got %d", len(msgs))
+		}
+		for i, msg := range msgs {
+			if string(msg.Key) != "user:42" || string(msg.Headers[0].Value) != brokerBatch[i].ID {
+				t.Errorf("unexpected record %+v", msg)
+			}
+		}
+		return nil
+	})
+
+	if err := sink.NewKafkaSinkWithWriter(writer).Publish(context.Background(), brokerBatch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writer.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(errors.New("leader not available"))
+	if err := sink.NewKafkaSinkWithWriter(writer).Publish(context.Background(), brokerBatch); err == nil {
+		t.Fatal("expected write error")
+	}
+}
+
+func TestNATSSink_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	subject := fmt.Sprintf("audits.%d", sink.Partition("user:42", 8))
+
+	js := mocks.NewMockJetStreamPublisher(ctrl)
+	js.EXPECT().PublishMsg(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+			if msg.Subject != subject {
+				t.Errorf("expected subject %s, got %s", subject, msg.Subject)
+			}
+			if len(opts) != 1 {
+				t.Errorf("expected the message id option")
+			}
+			return &jetstream.PubAck{}, nil
+		}).Times(2)
+
+	if err := sink.NewNATSSinkWithPublisher(js, "audits", 8).Publish(context.Background(), brokerBatch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	js.EXPECT().PublishMsg(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("no responders"))
+	if err := sink.NewNATSSinkWithPublisher(js, "audits", 8).Publish(context.Background(), brokerBatch); err == nil {
+		t.Fatal("expected publish error")
+	}
+}
+
+func TestAMQPSink_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	routingKey := fmt.Sprintf("audits.%d", sink.Partition("user:42", 8))
+
+	publisher := mocks.NewMockAMQPPublisher(ctrl)
+	for _, msg := range brokerBatch {
+		publisher.EXPECT().Publish(gomock.Any(), "audits", routingKey, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _ string, publishing amqp.Publishing) error {
+				if publishing.MessageId != msg.ID || publishing.DeliveryMode != amqp.Persistent {
+					t.Errorf("unexpected publishing %+v", publishing)
+				}
+				return nil
+			})
+	}
+
+	if err := sink.NewAMQPSinkWithPublisher(publisher, "audits", "audits", 8).Publish(context.Background(), brokerBatch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	publisher.EXPECT().Publish(gomock.Any(), "audits", routingKey, gomock.Any()).Return(errors.New("nacked"))
+	if err := sink.NewAMQPSinkWithPublisher(publisher, "audits", "audits", 8).Publish(context.Background(), brokerBatch); err == nil {
+		t.Fatal("expected publish error")
+	}
+}
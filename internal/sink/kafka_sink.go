@@ -0,0 +1,65 @@
+package sink
+
+//go:generate mockgen -source=kafka_sink.go -destination=mocks/mock_kafka_sink.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type KafkaConfig struct {
+	Brokers []string
+	Topic   string
+}
+
+// KafkaSink writes to a topic over the Kafka protocol (Kafka, Redpanda, ...).
+// Writes wait for all in-sync replicas and the partition is chosen from the
+// message key.
+type KafkaSink struct {
+	writer KafkaWriter
+}
+
+func NewKafkaSink(conf KafkaConfig) *KafkaSink {
+	return NewKafkaSinkWithWriter(&kafka.Writer{
+		Addr:         kafka.TCP(conf.Brokers...),
+		Topic:        conf.Topic,
+		Balancer:     kafka.BalancerFunc(balanceByKey),
+		RequiredAcks: kafka.RequireAll,
+	})
+}
+
+func NewKafkaSinkWithWriter(writer KafkaWriter) *KafkaSink {
+	return &KafkaSink{writer: writer}
+}
+
+func (ks *KafkaSink) Publish(ctx context.Context, msgs []Message) error {
+	records := make([]kafka.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		records = append(records, kafka.Message{
+			Key:     []byte(msg.Key),
+			Value:   msg.Value,
+			Headers: []kafka.Header{{Key: "message-id", Value: []byte(msg.ID)}},
+		})
+	}
+
+	if err := ks.writer.WriteMessages(ctx, records...); err != nil {
+		return fmt.Errorf("failed to write to kafka: %w", err)
+	}
+
+	return nil
+}
+
+func (ks *KafkaSink) Close() error {
+	return ks.writer.Close()
+}
+
+func balanceByKey(msg kafka.Message, partitions ...int) int {
+	return partitions[Partition(string(msg.Key), len(partitions))]
+}
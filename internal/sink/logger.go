@@ -0,0 +1,5 @@
+package sink
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("sink")
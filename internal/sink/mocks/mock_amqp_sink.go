@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/sink/amqp_sink.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/sink/amqp_sink.go -destination=internal/sink/mocks/mock_amqp_sink.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAMQPPublisher is a mock of AMQPPublisher interface.
+type MockAMQPPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAMQPPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockAMQPPublisherMockRecorder is the mock recorder for MockAMQPPublisher.
+type MockAMQPPublisherMockRecorder struct {
+	mock *MockAMQPPublisher
+}
+
+// NewMockAMQPPublisher creates a new mock instance.
+func NewMockAMQPPublisher(ctrl *gomock.Controller) *MockAMQPPublisher {
+	mock := &MockAMQPPublisher{ctrl: ctrl}
+	mock.recorder = &MockAMQPPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAMQPPublisher) EXPECT() *MockAMQPPublisherMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockAMQPPublisher) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockAMQPPublisherMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAMQPPublisher)(nil).Close))
+}
+
+// Publish mocks base method.
+func (m *MockAMQPPublisher) Publish(ctx context.Context, exchange, routingKey string, msg amqp091.Publishing) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, exchange, routingKey, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockAMQPPublisherMockRecorder) Publish(ctx, exchange, routingKey, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockAMQPPublisher)(nil).Publish), ctx, exchange, routingKey, msg)
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/sink/nats_sink.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/sink/nats_sink.go -destination=internal/sink/mocks/mock_nats_sink.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	nats "github.com/nats-io/nats.go"
+	jetstream "github.com/nats-io/nats.go/jetstream"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJetStreamPublisher is a mock of JetStreamPublisher interface.
+type MockJetStreamPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockJetStreamPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockJetStreamPublisherMockRecorder is the mock recorder for MockJetStreamPublisher.
+type MockJetStreamPublisherMockRecorder struct {
+	mock *MockJetStreamPublisher
+}
+
+// NewMockJetStreamPublisher creates a new mock instance.
+func NewMockJetStreamPublisher(ctrl *gomock.Controller) *MockJetStreamPublisher {
+	mock := &MockJetStreamPublisher{ctrl: ctrl}
+	mock.recorder = &MockJetStreamPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJetStreamPublisher) EXPECT() *MockJetStreamPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishMsg mocks base method.
+func (m *MockJetStreamPublisher) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PublishMsg", varargs...)
+	ret0, _ := ret[0].(*jetstream.PubAck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishMsg indicates an expected call of PublishMsg.
+func (mr *MockJetStreamPublisherMockRecorder) PublishMsg(ctx, msg any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishMsg", reflect.TypeOf((*MockJetStreamPublisher)(nil).PublishMsg), varargs...)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/sink/relay.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/sink/relay.go -destination=internal/sink/mocks/mock_relay.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	sink "github.com/IsaacDSC/auditory/internal/sink"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMessageLog is a mock of MessageLog interface.
+type MockMessageLog struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageLogMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageLogMockRecorder is the mock recorder for MockMessageLog.
+type MockMessageLogMockRecorder struct {
+	mock *MockMessageLog
+}
+
+// NewMockMessageLog creates a new mock instance.
+func NewMockMessageLog(ctrl *gomock.Controller) *MockMessageLog {
+	mock := &MockMessageLog{ctrl: ctrl}
+	mock.recorder = &MockMessageLogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageLog) EXPECT() *MockMessageLogMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockMessageLog) Append(msg sink.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockMessageLogMockRecorder) Append(msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockMessageLog)(nil).Append), msg)
+}
+
+// Commit mocks base method.
+func (m *MockMessageLog) Commit(offset int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", offset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockMessageLogMockRecorder) Commit(offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockMessageLog)(nil).Commit), offset)
+}
+
+// Pending mocks base method.
+func (m *MockMessageLog) Pending(limit int) ([]sink.Message, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pending", limit)
+	ret0, _ := ret[0].([]sink.Message)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Pending indicates an expected call of Pending.
+func (mr *MockMessageLogMockRecorder) Pending(limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockMessageLog)(nil).Pending), limit)
+}
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockAuditStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockAuditStoreMockRecorder) Upsert(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockAuditStore)(nil).Upsert), ctx, input)
+}
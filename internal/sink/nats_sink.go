@@ -0,0 +1,80 @@
+package sink
+
+//go:generate mockgen -source=nats_sink.go -destination=mocks/mock_nats_sink.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type JetStreamPublisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+type NATSConfig struct {
+	URL        string
+	Subject    string
+	Partitions int
+}
+
+// NATSSink publishes to JetStream on "{subject}.{partition}" and waits for
+// the stream's ack. The message id doubles as Nats-Msg-Id so redeliveries
+// within the stream's duplicate window are dropped by the server.
+type NATSSink struct {
+	js         JetStreamPublisher
+	conn       *nats.Conn
+	subject    string
+	partitions int
+}
+
+func NewNATSSink(conf NATSConfig) (*NATSSink, error) {
+	conn, err := nats.Connect(conf.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	ns := NewNATSSinkWithPublisher(js, conf.Subject, conf.Partitions)
+	ns.conn = conn
+	return ns, nil
+}
+
+func NewNATSSinkWithPublisher(js JetStreamPublisher, subject string, partitions int) *NATSSink {
+	return &NATSSink{
+		js:         js,
+		subject:    subject,
+		partitions: partitions,
+	}
+}
+
+func (ns *NATSSink) Publish(ctx context.Context, msgs []Message) error {
+	for _, msg := range msgs {
+		subject := fmt.Sprintf("%s.%d", ns.subject, Partition(msg.Key, ns.partitions))
+		natsMsg := &nats.Msg{
+			Subject: subject,
+			Data:    msg.Value,
+			Header:  nats.Header{"Audit-Key": []string{msg.Key}},
+		}
+
+		if _, err := ns.js.PublishMsg(ctx, natsMsg, jetstream.WithMsgID(msg.ID)); err != nil {
+			return fmt.Errorf("failed to publish to nats: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (ns *NATSSink) Close() error {
+	if ns.conn != nil {
+		return ns.conn.Drain()
+	}
+	return nil
+}
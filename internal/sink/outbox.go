@@ -0,0 +1,175 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Outbox is a local append-only log of messages waiting for the broker. The
+// relay publishes from the committed offset and only moves it forward once
+// the sink accepted the batch, so a crash in between redelivers the batch:
+// delivery is at-least-once. When everything was delivered the log is
+// truncated.
+type Outbox struct {
+	mu         sync.Mutex
+	logPath    string
+	offsetPath string
+	log        *os.File
+	offset     int64
+}
+
+func NewOutbox(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	outbox := &Outbox{
+		logPath:    filepath.Join(dir, "outbox.jsonl"),
+		offsetPath: filepath.Join(dir, "outbox.offset"),
+	}
+
+	raw, err := os.ReadFile(outbox.offsetPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read outbox offset: %w", err)
+	default:
+		if outbox.offset, err = strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid outbox offset: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(outbox.logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %w", err)
+	}
+	outbox.log = file
+
+	return outbox, nil
+}
+
+// Append durably records the message before the upsert is acknowledged.
+func (o *Outbox) Append(msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, err := o.log.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+	if err := o.log.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox: %w", err)
+	}
+
+	return nil
+}
+
+// Pending returns up to limit undelivered messages and the offset to commit
+// once they were published.
+func (o *Outbox) Pending(limit int) ([]Message, int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	file, err := os.Open(o.logPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open outbox: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(o.offset, io.SeekStart); err != nil {
+		return nil, 0, fmt.Errorf("failed to seek outbox: %w", err)
+	}
+
+	var msgs []Message
+	next := o.offset
+	reader := bufio.NewReader(file)
+	for len(msgs) < limit {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// io.EOF, possibly after a torn line that is not complete yet
+			break
+		}
+		next += int64(len(line))
+
+		var msg Message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			logger.Warn("skipping corrupt outbox entry", "offset", next-int64(len(line)), "error", err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, next, nil
+}
+
+// Commit marks everything before offset as delivered.
+func (o *Outbox) Commit(offset int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	info, err := o.log.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat outbox: %w", err)
+	}
+
+	if offset == o.offset && (offset == 0 || offset < info.Size()) {
+		return nil
+	}
+
+	if offset < info.Size() {
+		return o.commitInternal(offset)
+	}
+
+	// reset the offset before truncating: a crash in between redelivers the
+	// log instead of skipping new entries
+	if err := o.commitInternal(0); err != nil {
+		return err
+	}
+	if err := o.log.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate outbox: %w", err)
+	}
+
+	return nil
+}
+
+// Backlog returns how many bytes of the log are not committed yet.
+func (o *Outbox) Backlog() (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	info, err := o.log.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat outbox: %w", err)
+	}
+	return info.Size() - o.offset, nil
+}
+
+// commitInternal persists the offset without acquiring lock (for internal use when lock is already held)
+func (o *Outbox) commitInternal(offset int64) error {
+	tmpPath := o.offsetPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatInt(offset, 10)), 0644); err != nil {
+		return fmt.Errorf("failed to write outbox offset: %w", err)
+	}
+	if err := os.Rename(tmpPath, o.offsetPath); err != nil {
+		return fmt.Errorf("failed to write outbox offset: %w", err)
+	}
+
+	o.offset = offset
+	return nil
+}
+
+func (o *Outbox) Close() error {
+	return o.log.Close()
+}
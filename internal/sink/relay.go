@@ -0,0 +1,133 @@
+package sink
+
+//go:generate mockgen -source=relay.go -destination=mocks/mock_relay.go -package=mocks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+const defaultBatchSize = 100
+
+type MessageLog interface {
+	Append(msg Message) error
+	Pending(limit int) ([]Message, int64, error)
+	Commit(offset int64) error
+}
+
+type AuditStore interface {
+	Upsert(ctx context.Context, input audit.DataAudit) error
+}
+
+// OutboxStore records every audit the wrapped store accepted in the outbox.
+// A failed append fails the upsert so the caller retries instead of the
+// audit silently never reaching the broker.
+type OutboxStore struct {
+	store  AuditStore
+	outbox MessageLog
+}
+
+func NewOutboxStore(store AuditStore, outbox MessageLog) *OutboxStore {
+	return &OutboxStore{
+		store:  store,
+		outbox: outbox,
+	}
+}
+
+func (obs *OutboxStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	if err := obs.store.Upsert(ctx, input); err != nil {
+		return err
+	}
+
+	return appendAudit(obs.outbox, input)
+}
+
+// OutboxSink appends audits replayed from the archive to the outbox, so the
+// relay publishes them like live ones.
+type OutboxSink struct {
+	outbox MessageLog
+}
+
+func NewOutboxSink(outbox MessageLog) *OutboxSink {
+	return &OutboxSink{outbox: outbox}
+}
+
+func (obs *OutboxSink) InsertBatch(ctx context.Context, inputs []audit.DataAudit) error {
+	for _, input := range inputs {
+		if err := appendAudit(obs.outbox, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendAudit(outbox MessageLog, input audit.DataAudit) error {
+	value, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit: %w", err)
+	}
+
+	msg := Message{ID: newMessageID(), Key: input.Metadata.Key, Value: value}
+	if err := outbox.Append(msg); err != nil {
+		return fmt.Errorf("failed to append to outbox: %w", err)
+	}
+
+	return nil
+}
+
+// Relay drains the outbox into a sink.
+type Relay struct {
+	outbox    MessageLog
+	sink      Sink
+	batchSize int
+}
+
+func NewRelay(outbox MessageLog, sink Sink, batchSize int) *Relay {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Relay{
+		outbox:    outbox,
+		sink:      sink,
+		batchSize: batchSize,
+	}
+}
+
+// Flush publishes pending messages batch by batch until the outbox is empty
+// or the sink fails; a failed batch stays pending for the next flush.
+func (r *Relay) Flush(ctx context.Context) error {
+	for {
+		msgs, next, err := r.outbox.Pending(r.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			// skipped corrupt entries still move the offset
+			return r.outbox.Commit(next)
+		}
+
+		if err := r.sink.Publish(ctx, msgs); err != nil {
+			return err
+		}
+
+		if err := r.outbox.Commit(next); err != nil {
+			return err
+		}
+
+		if len(msgs) < r.batchSize {
+			return nil
+		}
+	}
+}
+
+func newMessageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,177 @@
+package sink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/sink"
+	"github.com/IsaacDSC/auditory/internal/sink/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRelay_Flush(t *testing.T) {
+	batch := []sink.Message{{ID: "m1", Key: "user:42"}, {ID: "m2", Key: "user:7"}}
+
+	tests := []struct {
+		name      string
+		setupMock func(l *mocks.MockMessageLog, s *mocks.MockSink)
+		wantErr   bool
+	}{
+		{
+			name: "success - publishes and commits until a short batch",
+			setupMock: func(l *mocks.MockMessageLog, s *mocks.MockSink) {
+				gomock.InOrder(
+					l.EXPECT().Pending(2).Return(batch, int64(100), nil),
+					s.EXPECT().Publish(gomock.Any(), batch).Return(nil),
+					l.EXPECT().Commit(int64(100)).Return(nil),
+					l.EXPECT().Pending(2).Return(batch[:1], int64(150), nil),
+					s.EXPECT().Publish(gomock.Any(), batch[:1]).Return(nil),
+					l.EXPECT().Commit(int64(150)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success - empty outbox",
+			setupMock: func(l *mocks.MockMessageLog, s *mocks.MockSink) {
+				l.EXPECT().Pending(2).Return(nil, int64(0), nil)
+				l.EXPECT().Commit(int64(0)).Return(nil)
+			},
+		},
+		{
+			name: "error - sink fails and nothing is committed",
+			setupMock: func(l *mocks.MockMessageLog, s *mocks.MockSink) {
+				l.EXPECT().Pending(2).Return(batch, int64(100), nil)
+				s.EXPECT().Publish(gomock.Any(), batch).Return(errors.New("broker unavailable"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLog := mocks.NewMockMessageLog(ctrl)
+			mockSink := mocks.NewMockSink(ctrl)
+			tt.setupMock(mockLog, mockSink)
+
+			err := sink.NewRelay(mockLog, mockSink, 2).Flush(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOutboxStore_Upsert(t *testing.T) {
+	input := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.created"}}
+
+	tests := []struct {
+		name      string
+		setupMock func(s *mocks.MockAuditStore, l *mocks.MockMessageLog)
+		wantErr   bool
+	}{
+		{
+			name: "success - appends keyed message after store",
+			setupMock: func(s *mocks.MockAuditStore, l *mocks.MockMessageLog) {
+				gomock.InOrder(
+					s.EXPECT().Upsert(gomock.Any(), input).Return(nil),
+					l.EXPECT().Append(gomock.Any()).DoAndReturn(func(msg sink.Message) error {
+						var got audit.DataAudit
+						if err := json.Unmarshal(msg.Value, &got); err != nil || got.Metadata.Key != "user:42" {
+							t.Errorf("unexpected value %s (%v)", msg.Value, err)
+						}
+						if msg.Key != "user:42" || msg.ID == "" {
+							t.Errorf("unexpected message %+v", msg)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "error - store fails and nothing is appended",
+			setupMock: func(s *mocks.MockAuditStore, l *mocks.MockMessageLog) {
+				s.EXPECT().Upsert(gomock.Any(), input).Return(errors.New("disk full"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - append fails the upsert",
+			setupMock: func(s *mocks.MockAuditStore, l *mocks.MockMessageLog) {
+				s.EXPECT().Upsert(gomock.Any(), input).Return(nil)
+				l.EXPECT().Append(gomock.Any()).Return(errors.New("disk full"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mocks.NewMockAuditStore(ctrl)
+			mockLog := mocks.NewMockMessageLog(ctrl)
+			tt.setupMock(mockStore, mockLog)
+
+			err := sink.NewOutboxStore(mockStore, mockLog).Upsert(context.Background(), input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOutboxSink_InsertBatch(t *testing.T) {
+	inputs := []audit.DataAudit{
+		{Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.created"}},
+		{Metadata: audit.MetadataAudit{Key: "user:7", EventName: "user.created"}},
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(l *mocks.MockMessageLog)
+		wantErr   bool
+	}{
+		{
+			name: "success - appends one keyed message per audit",
+			setupMock: func(l *mocks.MockMessageLog) {
+				for _, input := range inputs {
+					l.EXPECT().Append(gomock.Any()).DoAndReturn(func(msg sink.Message) error {
+						if msg.Key != input.Metadata.Key || msg.ID == "" {
+							t.Errorf("unexpected message %+v", msg)
+						}
+						return nil
+					})
+				}
+			},
+		},
+		{
+			name: "error - append fails the batch",
+			setupMock: func(l *mocks.MockMessageLog) {
+				l.EXPECT().Append(gomock.Any()).Return(errors.New("disk full"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLog := mocks.NewMockMessageLog(ctrl)
+			tt.setupMock(mockLog)
+
+			err := sink.NewOutboxSink(mockLog).InsertBatch(context.Background(), inputs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package sink
+
+//go:generate mockgen -source=sink.go -destination=mocks/mock_sink.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+)
+
+// Message is one audit as published to a broker. Key is the audit's
+// metadata key, which every sink uses to pick the partition so all events of
+// a subject stay ordered. ID is stable across redeliveries so consumers (and
+// brokers that support it) can deduplicate.
+type Message struct {
+	ID    string `json:"id"`
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type Sink interface {
+	Publish(ctx context.Context, msgs []Message) error
+	Close() error
+}
+
+// Partition maps a key onto one of n partitions with FNV-1a.
+func Partition(key string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
+
+// New builds the sink selected by SinkConfig.Backend.
+func New(conf *cfg.GeneralConfig) (Sink, error) {
+	sinkCfg := conf.SinkConfig
+
+	switch sinkCfg.Backend {
+	case "kafka":
+		return NewKafkaSink(KafkaConfig{
+			Brokers: sinkCfg.Kafka.Brokers,
+			Topic:   sinkCfg.Kafka.Topic,
+		}), nil
+	case "nats":
+		return NewNATSSink(NATSConfig{
+			URL:        sinkCfg.NATS.URL,
+			Subject:    sinkCfg.NATS.Subject,
+			Partitions: sinkCfg.Partitions,
+		})
+	case "amqp":
+		return NewAMQPSink(AMQPConfig{
+			URL:        sinkCfg.AMQP.URL,
+			Exchange:   sinkCfg.AMQP.Exchange,
+			RoutingKey: sinkCfg.AMQP.RoutingKey,
+			Partitions: sinkCfg.Partitions,
+		})
+	default:
+		return nil, fmt.Errorf("unknown sink backend: %s", sinkCfg.Backend)
+	}
+}
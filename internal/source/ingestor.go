@@ -0,0 +1,166 @@
+package source
+
+//go:generate mockgen -source=ingestor.go -destination=mocks/mock_ingestor.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+const (
+	RequestIDHeader     = "X-Request-ID"
+	CorrelationIDHeader = "X-Correlation-ID"
+	ReasonHeader        = "X-Dead-Letter-Reason"
+
+	defaultRetryBackoff = time.Second
+)
+
+// Message is one record read from a broker. raw is the client's own message,
+// kept so the source can commit exactly this record.
+type Message struct {
+	Key     string
+	Value   []byte
+	Headers map[string]string
+	raw     any
+}
+
+type Source interface {
+	Fetch(ctx context.Context) (Message, error)
+	Commit(ctx context.Context, msg Message) error
+	Close() error
+}
+
+type DeadLetterPublisher interface {
+	DeadLetter(ctx context.Context, msg Message, reason string) error
+}
+
+type AuditSaver interface {
+	Save(ctx context.Context, input audit.DataAudit) (string, error)
+}
+
+// Ingestor feeds broker records through the same validation and idempotent
+// save as POST /audit. A record is committed only after it was durably
+// written (or recognised as a duplicate); records that can never be accepted
+// go to the dead-letter destination and are committed too.
+type Ingestor struct {
+	source       Source
+	saver        AuditSaver
+	deadLetters  DeadLetterPublisher
+	retryBackoff time.Duration
+}
+
+func NewIngestor(source Source, saver AuditSaver, deadLetters DeadLetterPublisher, retryBackoff time.Duration) *Ingestor {
+	if retryBackoff <= 0 {
+		retryBackoff = defaultRetryBackoff
+	}
+
+	return &Ingestor{
+		source:       source,
+		saver:        saver,
+		deadLetters:  deadLetters,
+		retryBackoff: retryBackoff,
+	}
+}
+
+// Run consumes until ctx is done.
+func (ing *Ingestor) Run(ctx context.Context) {
+	for {
+		msg, err := ing.source.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.InfoContext(ctx, "ingestion source stopped")
+				return
+			}
+			logger.ErrorContext(ctx, "failed to fetch message", "error", err)
+			if !ing.wait(ctx) {
+				return
+			}
+			continue
+		}
+
+		if !ing.retry(ctx, func() error { return ing.Handle(ctx, msg) }) {
+			return
+		}
+	}
+}
+
+// Handle processes a single record and commits it once it is settled.
+func (ing *Ingestor) Handle(ctx context.Context, msg Message) error {
+	input, err := decode(msg)
+	if err != nil {
+		metrics.ObserveAudit(input.Metadata.Key, metrics.ResultRejected)
+		if err := ing.deadLetters.DeadLetter(ctx, msg, err.Error()); err != nil {
+			return fmt.Errorf("failed to dead-letter message: %w", err)
+		}
+		return ing.source.Commit(ctx, msg)
+	}
+
+	ctx = ctxkey.SetAuditKey(ctx, input.Metadata.Key)
+	ctx = ctxkey.SetRequestID(ctx, input.Metadata.RequestID)
+	ctx = ctxkey.SetCorrelationID(ctx, input.Metadata.CorrelationID)
+
+	_, err = ing.saver.Save(ctx, input)
+	switch {
+	case err == nil:
+		metrics.ObserveAudit(input.Metadata.Key, metrics.ResultAccepted)
+	case errors.Is(err, backup.ErrIdempotencyKeyAlreadyExists):
+		metrics.ObserveAudit(input.Metadata.Key, metrics.ResultDuplicate)
+	default:
+		metrics.ObserveAudit(input.Metadata.Key, metrics.ResultFailed)
+		return err
+	}
+
+	return ing.source.Commit(ctx, msg)
+}
+
+// retry repeats fn on the same record until it succeeds; moving on would
+// skip a record whose offset may later be committed past it.
+func (ing *Ingestor) retry(ctx context.Context, fn func() error) bool {
+	for {
+		err := fn()
+		if err == nil {
+			return true
+		}
+		logger.ErrorContext(ctx, "failed to ingest message", "error", err)
+		if !ing.wait(ctx) {
+			return false
+		}
+	}
+}
+
+func (ing *Ingestor) wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(ing.retryBackoff):
+		return true
+	}
+}
+
+func decode(msg Message) (audit.DataAudit, error) {
+	var input audit.DataAudit
+	if err := json.Unmarshal(msg.Value, &input); err != nil {
+		return audit.DataAudit{}, fmt.Errorf("invalid audit payload: %w", err)
+	}
+
+	if requestID := msg.Headers[RequestIDHeader]; requestID != "" {
+		input.Metadata.RequestID = requestID
+	}
+	if correlationID := msg.Headers[CorrelationIDHeader]; correlationID != "" {
+		input.Metadata.CorrelationID = correlationID
+	}
+
+	if err := input.Metadata.Validate(); err != nil {
+		return audit.DataAudit{}, err
+	}
+
+	return input, nil
+}
@@ -0,0 +1,149 @@
+package source_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/backup"
+	"github.com/IsaacDSC/auditory/internal/source"
+	"github.com/IsaacDSC/auditory/internal/source/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+const validPayload = `{"metadata":{"key":"user:42","event_name":"user.created","request_id":"req-1","correlation_id":"corr-1"},"data":{"name":"alice"}}`
+
+func TestIngestor_Handle(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       source.Message
+		setupMock func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher)
+		wantErr   bool
+	}{
+		{
+			name: "success - saves then commits",
+			msg:  source.Message{Value: []byte(validPayload)},
+			setupMock: func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher) {
+				gomock.InOrder(
+					saver.EXPECT().Save(gomock.Any(), gomock.Any()).Return("user:42-user.created-req-1-corr-1", nil),
+					src.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success - headers override request and correlation ids",
+			msg: source.Message{
+				Value:   []byte(`{"metadata":{"key":"user:42","event_name":"user.created"}}`),
+				Headers: map[string]string{source.RequestIDHeader: "req-h", source.CorrelationIDHeader: "corr-h"},
+			},
+			setupMock: func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher) {
+				saver.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input audit.DataAudit) (string, error) {
+					if input.Metadata.RequestID != "req-h" || input.Metadata.CorrelationID != "corr-h" {
+						t.Errorf("expected ids from headers, got %+v", input.Metadata)
+					}
+					return "", nil
+				})
+				src.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "success - duplicate is committed",
+			msg:  source.Message{Value: []byte(validPayload)},
+			setupMock: func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher) {
+				saver.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", backup.ErrIdempotencyKeyAlreadyExists)
+				src.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "success - malformed payload is dead-lettered and committed",
+			msg:  source.Message{Value: []byte(`{`)},
+			setupMock: func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher) {
+				gomock.InOrder(
+					dlq.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					src.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success - missing metadata is dead-lettered",
+			msg:  source.Message{Value: []byte(`{"metadata":{"key":"user:42"}}`)},
+			setupMock: func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher) {
+				dlq.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				src.EXPECT().Commit(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error - save fails and nothing is committed",
+			msg:  source.Message{Value: []byte(validPayload)},
+			setupMock: func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher) {
+				saver.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", errors.New("disk full"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - dead letter fails and nothing is committed",
+			msg:  source.Message{Value: []byte(`{`)},
+			setupMock: func(src *mocks.MockSource, saver *mocks.MockAuditSaver, dlq *mocks.MockDeadLetterPublisher) {
+				dlq.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("broker unavailable"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSource := mocks.NewMockSource(ctrl)
+			mockSaver := mocks.NewMockAuditSaver(ctrl)
+			mockDLQ := mocks.NewMockDeadLetterPublisher(ctrl)
+			tt.setupMock(mockSource, mockSaver, mockDLQ)
+
+			ingestor := source.NewIngestor(mockSource, mockSaver, mockDLQ, time.Millisecond)
+			err := ingestor.Handle(context.Background(), tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestIngestor_RunRetriesSameMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msg := source.Message{Value: []byte(validPayload)}
+
+	mockSource := mocks.NewMockSource(ctrl)
+	mockSaver := mocks.NewMockAuditSaver(ctrl)
+	mockDLQ := mocks.NewMockDeadLetterPublisher(ctrl)
+
+	gomock.InOrder(
+		mockSource.EXPECT().Fetch(gomock.Any()).Return(msg, nil),
+		mockSaver.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", errors.New("disk full")),
+		mockSaver.EXPECT().Save(gomock.Any(), gomock.Any()).Return("key", nil),
+		mockSource.EXPECT().Commit(gomock.Any(), msg).Return(nil),
+		mockSource.EXPECT().Fetch(gomock.Any()).DoAndReturn(func(ctx context.Context) (source.Message, error) {
+			cancel()
+			return source.Message{}, ctx.Err()
+		}),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		source.NewIngestor(mockSource, mockSaver, mockDLQ, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ingestor did not stop")
+	}
+}
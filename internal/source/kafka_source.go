@@ -0,0 +1,114 @@
+package source
+
+//go:generate mockgen -source=kafka_source.go -destination=mocks/mock_kafka_source.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type KafkaConfig struct {
+	Brokers         []string
+	Topic           string
+	GroupID         string
+	DeadLetterTopic string
+}
+
+// KafkaSource reads a topic as a consumer group and commits offsets
+// explicitly. Dead letters are written to a separate topic with the
+// original key, value and headers plus the rejection reason.
+type KafkaSource struct {
+	reader KafkaReader
+	writer KafkaWriter
+}
+
+func NewKafkaSource(conf KafkaConfig) *KafkaSource {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: conf.Brokers,
+		Topic:   conf.Topic,
+		GroupID: conf.GroupID,
+	})
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(conf.Brokers...),
+		Topic:        conf.DeadLetterTopic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}
+
+	return NewKafkaSourceWithClients(reader, writer)
+}
+
+func NewKafkaSourceWithClients(reader KafkaReader, writer KafkaWriter) *KafkaSource {
+	return &KafkaSource{
+		reader: reader,
+		writer: writer,
+	}
+}
+
+func (ks *KafkaSource) Fetch(ctx context.Context) (Message, error) {
+	record, err := ks.reader.FetchMessage(ctx)
+	if err != nil {
+		return Message{}, err
+	}
+
+	headers := make(map[string]string, len(record.Headers))
+	for _, header := range record.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+
+	return Message{
+		Key:     string(record.Key),
+		Value:   record.Value,
+		Headers: headers,
+		raw:     record,
+	}, nil
+}
+
+func (ks *KafkaSource) Commit(ctx context.Context, msg Message) error {
+	record, ok := msg.raw.(kafka.Message)
+	if !ok {
+		return fmt.Errorf("message was not read from kafka")
+	}
+
+	if err := ks.reader.CommitMessages(ctx, record); err != nil {
+		return fmt.Errorf("failed to commit offset: %w", err)
+	}
+
+	return nil
+}
+
+func (ks *KafkaSource) DeadLetter(ctx context.Context, msg Message, reason string) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+1)
+	for key, value := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	headers = append(headers, kafka.Header{Key: ReasonHeader, Value: []byte(reason)})
+
+	record := kafka.Message{Key: []byte(msg.Key), Value: msg.Value, Headers: headers}
+	if err := ks.writer.WriteMessages(ctx, record); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (ks *KafkaSource) Close() error {
+	if err := ks.writer.Close(); err != nil {
+		return err
+	}
+	return ks.reader.Close()
+}
@@ -0,0 +1,101 @@
+package source_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/source"
+	"github.com/IsaacDSC/auditory/internal/source/mocks"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/mock/gomock"
+)
+
+func TestKafkaSource_FetchCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	record := kafka.Message{
+		Topic:     "audits.in",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("user:42"),
+		Value:     []byte(validPayload),
+		Headers:   []kafka.Header{{Key: source.RequestIDHeader, Value: []byte("req-1")}},
+	}
+
+	reader := mocks.NewMockKafkaReader(ctrl)
+	writer := mocks.NewMockKafkaWriter(ctrl)
+	reader.EXPECT().FetchMessage(gomock.Any()).Return(record, nil)
+	reader.EXPECT().CommitMessages(gomock.Any(), record).Return(nil)
+
+	ks := source.NewKafkaSourceWithClients(reader, writer)
+
+	msg, err := ks.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Key != "user:42" || msg.Headers[source.RequestIDHeader] != "req-1" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+
+	if err := ks.Commit(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected commit error: %v", err)
+	}
+
+	if err := ks.Commit(context.Background(), source.Message{}); err == nil {
+		t.Error("expected error committing a message not read from kafka")
+	}
+}
+
+func TestKafkaSource_DeadLetter(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(w *mocks.MockKafkaWriter)
+		wantErr   bool
+	}{
+		{
+			name: "success - writes original record with reason",
+			setupMock: func(w *mocks.MockKafkaWriter) {
+				w.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+					if len(msgs) != 1 || string(msgs[0].Key) != "user:42" || string(msgs[0].Value) != "{" {
+						t.Errorf("unexpected dead letter %+v", msgs)
+					}
+					var reason string
+					for _, header := range msgs[0].Headers {
+						if header.Key == source.ReasonHeader {
+							reason = string(header.Value)
+						}
+					}
+					if reason != "invalid audit payload" {
+						t.Errorf("expected reason header, got %q", reason)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name: "error - write fails",
+			setupMock: func(w *mocks.MockKafkaWriter) {
+				w.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(errors.New("leader not available"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			writer := mocks.NewMockKafkaWriter(ctrl)
+			tt.setupMock(writer)
+
+			ks := source.NewKafkaSourceWithClients(mocks.NewMockKafkaReader(ctrl), writer)
+			err := ks.DeadLetter(context.Background(), source.Message{Key: "user:42", Value: []byte("{")}, "invalid audit payload")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package source
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("source")
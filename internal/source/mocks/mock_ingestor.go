@@ -0,0 +1,163 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/source/ingestor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/source/ingestor.go -destination=internal/source/mocks/mock_ingestor.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	source "github.com/IsaacDSC/auditory/internal/source"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSource is a mock of Source interface.
+type MockSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockSourceMockRecorder is the mock recorder for MockSource.
+type MockSourceMockRecorder struct {
+	mock *MockSource
+}
+
+// NewMockSource creates a new mock instance.
+func NewMockSource(ctrl *gomock.Controller) *MockSource {
+	mock := &MockSource{ctrl: ctrl}
+	mock.recorder = &MockSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSource) EXPECT() *MockSourceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockSource) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSourceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSource)(nil).Close))
+}
+
+// Commit mocks base method.
+func (m *MockSource) Commit(ctx context.Context, msg source.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockSourceMockRecorder) Commit(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockSource)(nil).Commit), ctx, msg)
+}
+
+// Fetch mocks base method.
+func (m *MockSource) Fetch(ctx context.Context) (source.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx)
+	ret0, _ := ret[0].(source.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockSourceMockRecorder) Fetch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockSource)(nil).Fetch), ctx)
+}
+
+// MockDeadLetterPublisher is a mock of DeadLetterPublisher interface.
+type MockDeadLetterPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterPublisherMockRecorder is the mock recorder for MockDeadLetterPublisher.
+type MockDeadLetterPublisherMockRecorder struct {
+	mock *MockDeadLetterPublisher
+}
+
+// NewMockDeadLetterPublisher creates a new mock instance.
+func NewMockDeadLetterPublisher(ctrl *gomock.Controller) *MockDeadLetterPublisher {
+	mock := &MockDeadLetterPublisher{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterPublisher) EXPECT() *MockDeadLetterPublisherMockRecorder {
+	return m.recorder
+}
+
+// DeadLetter mocks base method.
+func (m *MockDeadLetterPublisher) DeadLetter(ctx context.Context, msg source.Message, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetter", ctx, msg, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetter indicates an expected call of DeadLetter.
+func (mr *MockDeadLetterPublisherMockRecorder) DeadLetter(ctx, msg, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetter", reflect.TypeOf((*MockDeadLetterPublisher)(nil).DeadLetter), ctx, msg, reason)
+}
+
+// MockAuditSaver is a mock of AuditSaver interface.
+type MockAuditSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditSaverMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditSaverMockRecorder is the mock recorder for MockAuditSaver.
+type MockAuditSaverMockRecorder struct {
+	mock *MockAuditSaver
+}
+
+// NewMockAuditSaver creates a new mock instance.
+func NewMockAuditSaver(ctrl *gomock.Controller) *MockAuditSaver {
+	mock := &MockAuditSaver{ctrl: ctrl}
+	mock.recorder = &MockAuditSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditSaver) EXPECT() *MockAuditSaverMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockAuditSaver) Save(ctx context.Context, input audit.DataAudit) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAuditSaverMockRecorder) Save(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAuditSaver)(nil).Save), ctx, input)
+}
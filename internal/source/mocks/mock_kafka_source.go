@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/source/kafka_source.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/source/kafka_source.go -destination=internal/source/mocks/mock_kafka_source.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	kafka "github.com/segmentio/kafka-go"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKafkaReader is a mock of KafkaReader interface.
+type MockKafkaReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockKafkaReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockKafkaReaderMockRecorder is the mock recorder for MockKafkaReader.
+type MockKafkaReaderMockRecorder struct {
+	mock *MockKafkaReader
+}
+
+// NewMockKafkaReader creates a new mock instance.
+func NewMockKafkaReader(ctrl *gomock.Controller) *MockKafkaReader {
+	mock := &MockKafkaReader{ctrl: ctrl}
+	mock.recorder = &MockKafkaReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKafkaReader) EXPECT() *MockKafkaReaderMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockKafkaReader) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockKafkaReaderMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKafkaReader)(nil).Close))
+}
+
+// CommitMessages mocks base method.
+func (m *MockKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CommitMessages", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommitMessages indicates an expected call of CommitMessages.
+func (mr *MockKafkaReaderMockRecorder) CommitMessages(ctx any, msgs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitMessages", reflect.TypeOf((*MockKafkaReader)(nil).CommitMessages), varargs...)
+}
+
+// FetchMessage mocks base method.
+func (m *MockKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMessage", ctx)
+	ret0, _ := ret[0].(kafka.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMessage indicates an expected call of FetchMessage.
+func (mr *MockKafkaReaderMockRecorder) FetchMessage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMessage", reflect.TypeOf((*MockKafkaReader)(nil).FetchMessage), ctx)
+}
+
+// MockKafkaWriter is a mock of KafkaWriter interface.
+type MockKafkaWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockKafkaWriterMockRecorder
+	isgomock struct{}
+}
+
+// MockKafkaWriterMockRecorder is the mock recorder for MockKafkaWriter.
+type MockKafkaWriterMockRecorder struct {
+	mock *MockKafkaWriter
+}
+
+// NewMockKafkaWriter creates a new mock instance.
+func NewMockKafkaWriter(ctrl *gomock.Controller) *MockKafkaWriter {
+	mock := &MockKafkaWriter{ctrl: ctrl}
+	mock.recorder = &MockKafkaWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKafkaWriter) EXPECT() *MockKafkaWriterMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockKafkaWriter) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockKafkaWriterMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKafkaWriter)(nil).Close))
+}
+
+// WriteMessages mocks base method.
+func (m *MockKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WriteMessages", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteMessages indicates an expected call of WriteMessages.
+func (mr *MockKafkaWriterMockRecorder) WriteMessages(ctx any, msgs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessages", reflect.TypeOf((*MockKafkaWriter)(nil).WriteMessages), varargs...)
+}
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/source/nats_source.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/source/nats_source.go -destination=internal/source/mocks/mock_nats_source.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	nats "github.com/nats-io/nats.go"
+	jetstream "github.com/nats-io/nats.go/jetstream"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNATSConsumer is a mock of NATSConsumer interface.
+type MockNATSConsumer struct {
+	ctrl     *gomock.Controller
+	recorder *MockNATSConsumerMockRecorder
+	isgomock struct{}
+}
+
+// MockNATSConsumerMockRecorder is the mock recorder for MockNATSConsumer.
+type MockNATSConsumerMockRecorder struct {
+	mock *MockNATSConsumer
+}
+
+// NewMockNATSConsumer creates a new mock instance.
+func NewMockNATSConsumer(ctrl *gomock.Controller) *MockNATSConsumer {
+	mock := &MockNATSConsumer{ctrl: ctrl}
+	mock.recorder = &MockNATSConsumerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNATSConsumer) EXPECT() *MockNATSConsumerMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method.
+func (m *MockNATSConsumer) Next(opts ...jetstream.FetchOpt) (jetstream.Msg, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Next", varargs...)
+	ret0, _ := ret[0].(jetstream.Msg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockNATSConsumerMockRecorder) Next(opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockNATSConsumer)(nil).Next), opts...)
+}
+
+// MockNATSPublisher is a mock of NATSPublisher interface.
+type MockNATSPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockNATSPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockNATSPublisherMockRecorder is the mock recorder for MockNATSPublisher.
+type MockNATSPublisherMockRecorder struct {
+	mock *MockNATSPublisher
+}
+
+// NewMockNATSPublisher creates a new mock instance.
+func NewMockNATSPublisher(ctrl *gomock.Controller) *MockNATSPublisher {
+	mock := &MockNATSPublisher{ctrl: ctrl}
+	mock.recorder = &MockNATSPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNATSPublisher) EXPECT() *MockNATSPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishMsg mocks base method.
+func (m *MockNATSPublisher) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PublishMsg", varargs...)
+	ret0, _ := ret[0].(*jetstream.PubAck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishMsg indicates an expected call of PublishMsg.
+func (mr *MockNATSPublisherMockRecorder) PublishMsg(ctx, msg any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishMsg", reflect.TypeOf((*MockNATSPublisher)(nil).PublishMsg), varargs...)
+}
@@ -0,0 +1,137 @@
+package source
+
+//go:generate mockgen -source=nats_source.go -destination=mocks/mock_nats_source.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const natsFetchWait = 5 * time.Second
+
+type NATSConsumer interface {
+	Next(opts ...jetstream.FetchOpt) (jetstream.Msg, error)
+}
+
+type NATSPublisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+type NATSConfig struct {
+	URL               string
+	Stream            string
+	Consumer          string
+	Subject           string
+	DeadLetterSubject string
+}
+
+// NATSSource pulls from a durable JetStream consumer with explicit acks; a
+// message is acked (and the ack confirmed by the server) only on Commit.
+type NATSSource struct {
+	consumer          NATSConsumer
+	publisher         NATSPublisher
+	deadLetterSubject string
+	conn              *nats.Conn
+}
+
+func NewNATSSource(ctx context.Context, conf NATSConfig) (*NATSSource, error) {
+	conn, err := nats.Connect(conf.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(ctx, conf.Stream, jetstream.ConsumerConfig{
+		Durable:       conf.Consumer,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		FilterSubject: conf.Subject,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+
+	ns := NewNATSSourceWithClients(consumer, js, conf.DeadLetterSubject)
+	ns.conn = conn
+	return ns, nil
+}
+
+func NewNATSSourceWithClients(consumer NATSConsumer, publisher NATSPublisher, deadLetterSubject string) *NATSSource {
+	return &NATSSource{
+		consumer:          consumer,
+		publisher:         publisher,
+		deadLetterSubject: deadLetterSubject,
+	}
+}
+
+func (ns *NATSSource) Fetch(ctx context.Context) (Message, error) {
+	for {
+		msg, err := ns.consumer.Next(jetstream.FetchMaxWait(natsFetchWait))
+		if errors.Is(err, nats.ErrTimeout) {
+			if ctx.Err() != nil {
+				return Message{}, ctx.Err()
+			}
+			continue
+		}
+		if err != nil {
+			return Message{}, err
+		}
+
+		headers := make(map[string]string, len(msg.Headers()))
+		for key := range msg.Headers() {
+			headers[key] = msg.Headers().Get(key)
+		}
+
+		return Message{
+			Key:     msg.Subject(),
+			Value:   msg.Data(),
+			Headers: headers,
+			raw:     msg,
+		}, nil
+	}
+}
+
+func (ns *NATSSource) Commit(ctx context.Context, msg Message) error {
+	natsMsg, ok := msg.raw.(jetstream.Msg)
+	if !ok {
+		return fmt.Errorf("message was not read from nats")
+	}
+
+	if err := natsMsg.DoubleAck(ctx); err != nil {
+		return fmt.Errorf("failed to ack message: %w", err)
+	}
+
+	return nil
+}
+
+func (ns *NATSSource) DeadLetter(ctx context.Context, msg Message, reason string) error {
+	header := nats.Header{}
+	for key, value := range msg.Headers {
+		header.Set(key, value)
+	}
+	header.Set(ReasonHeader, reason)
+
+	deadLetter := &nats.Msg{Subject: ns.deadLetterSubject, Data: msg.Value, Header: header}
+	if _, err := ns.publisher.PublishMsg(ctx, deadLetter); err != nil {
+		return fmt.Errorf("failed to publish dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (ns *NATSSource) Close() error {
+	if ns.conn != nil {
+		return ns.conn.Drain()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+)
+
+type BrokerSource interface {
+	Source
+	DeadLetterPublisher
+}
+
+// New builds the source selected by SourceConfig.Backend.
+func New(ctx context.Context, conf *cfg.GeneralConfig) (BrokerSource, error) {
+	sourceCfg := conf.SourceConfig
+
+	switch sourceCfg.Backend {
+	case "kafka":
+		return NewKafkaSource(KafkaConfig{
+			Brokers:         sourceCfg.Kafka.Brokers,
+			Topic:           sourceCfg.Kafka.Topic,
+			GroupID:         sourceCfg.Kafka.GroupID,
+			DeadLetterTopic: sourceCfg.Kafka.DeadLetterTopic,
+		}), nil
+	case "nats":
+		return NewNATSSource(ctx, NATSConfig{
+			URL:               sourceCfg.NATS.URL,
+			Stream:            sourceCfg.NATS.Stream,
+			Consumer:          sourceCfg.NATS.Consumer,
+			Subject:           sourceCfg.NATS.Subject,
+			DeadLetterSubject: sourceCfg.NATS.DeadLetterSubject,
+		})
+	default:
+		return nil, fmt.Errorf("unknown source backend: %s", sourceCfg.Backend)
+	}
+}
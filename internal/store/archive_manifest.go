@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+)
+
+// ManifestPrefix holds one manifest per archived day; it is not an audit key.
+const ManifestPrefix = "audits/_manifests/"
+
+var ErrManifestNotFound = errors.New("no manifest for this day")
+
+// Manifest lists every object Store archived for a day. Store records it
+// after the uploads were verified and before deleting anything locally.
+type Manifest struct {
+	Date    string           `json:"date"`
+	Objects []ArchivedObject `json:"objects"`
+}
+
+func ManifestPath(day time.Time) string {
+	return fmt.Sprintf("%s%s.json", ManifestPrefix, day.Format(time.DateOnly))
+}
+
+// NodeManifestPath is the manifest of one cluster node, see NodeBackupPath.
+func NodeManifestPath(node string, day time.Time) string {
+	return fmt.Sprintf("%s%s.%s.json", ManifestPrefix, day.Format(time.DateOnly), node)
+}
+
+// RecordManifest adds objects to the manifest of day. An object archived again
+// on a later run, a retry or a late event, replaces its previous entry.
+func (as *ArchiveStore) RecordManifest(ctx context.Context, day time.Time, objects []ArchivedObject) error {
+	expires := as.storeExpires(day)
+
+	path := ManifestPath(day)
+	if as.node != "" {
+		path = NodeManifestPath(as.node, day)
+	}
+
+	manifest := Manifest{Date: day.Format(time.DateOnly)}
+	payload, err := as.storage.Get(ctx, path)
+	switch {
+	case errors.Is(err, ErrObjectNotFound):
+	case err != nil:
+		return fmt.Errorf("failed to read manifest %s: %w", path, err)
+	default:
+		if err := json.Unmarshal(payload, &manifest); err != nil {
+			return fmt.Errorf("failed to decode manifest %s: %w", path, err)
+		}
+	}
+
+	byPath := make(map[string]ArchivedObject, len(manifest.Objects)+len(objects))
+	for _, object := range manifest.Objects {
+		byPath[object.Path] = object
+	}
+	for _, object := range objects {
+		byPath[object.Path] = object
+	}
+
+	manifest.Objects = manifest.Objects[:0]
+	for _, object := range byPath {
+		manifest.Objects = append(manifest.Objects, object)
+	}
+	sort.Slice(manifest.Objects, func(i, j int) bool { return manifest.Objects[i].Path < manifest.Objects[j].Path })
+
+	payload, err = json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if _, err := putVerified(ctx, as.storage, path, payload, expires); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Manifest returns the manifest of day, merging the manifests of every cluster
+// node.
+func (as *ArchiveStore) Manifest(ctx context.Context, day time.Time) (Manifest, error) {
+	prefix := ManifestPrefix + day.Format(time.DateOnly) + "."
+	paths, err := as.storage.List(ctx, prefix)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to list manifests: %w", err)
+	}
+	if len(paths) == 0 {
+		return Manifest{}, ErrManifestNotFound
+	}
+
+	manifest := Manifest{Date: day.Format(time.DateOnly)}
+	for _, path := range paths {
+		payload, err := as.storage.Get(ctx, path)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to read manifest %s: %w", path, err)
+		}
+
+		var nodeManifest Manifest
+		if err := json.Unmarshal(payload, &nodeManifest); err != nil {
+			return Manifest{}, fmt.Errorf("failed to decode manifest %s: %w", path, err)
+		}
+		manifest.Objects = append(manifest.Objects, nodeManifest.Objects...)
+	}
+	sort.Slice(manifest.Objects, func(i, j int) bool { return manifest.Objects[i].Path < manifest.Objects[j].Path })
+
+	return manifest, nil
+}
+
+// Reconcile compares the manifest of day with the bucket: an object that is
+// gone is missing, one whose size or checksum changed since it was archived
+// is altered.
+func (as *ArchiveStore) Reconcile(ctx context.Context, day time.Time) (ArchiveReport, error) {
+	manifest, err := as.Manifest(ctx, day)
+	if err != nil {
+		return ArchiveReport{}, err
+	}
+
+	report := manifest.Report()
+	report.Verified = true
+	for _, object := range manifest.Objects {
+		info, err := StatObject(ctx, as.storage, object.Path)
+		switch {
+		case errors.Is(err, ErrObjectNotFound):
+			report.Missing = append(report.Missing, object)
+		case err != nil:
+			return ArchiveReport{}, fmt.Errorf("failed to check %s: %w", object.Path, err)
+		case info.Size != object.Size || info.SHA256 != object.SHA256:
+			report.Altered = append(report.Altered, object)
+		}
+	}
+
+	return report, nil
+}
+
+// ArchiveReport sums up a manifest and, once verified against the bucket,
+// lists the objects that are not there as archived.
+type ArchiveReport struct {
+	Date         string           `json:"date"`
+	Keys         int              `json:"keys"`
+	Records      int              `json:"records"`
+	Bytes        int64            `json:"bytes"`
+	FirstEventAt time.Time        `json:"first_event_at,omitzero"`
+	LastEventAt  time.Time        `json:"last_event_at,omitzero"`
+	Objects      []ArchivedObject `json:"objects"`
+	Verified     bool             `json:"verified"`
+	Missing      []ArchivedObject `json:"missing,omitempty"`
+	Altered      []ArchivedObject `json:"altered,omitempty"`
+}
+
+// Complete reports whether every object of the manifest is in the bucket as
+// archived; only meaningful once Verified.
+func (ar ArchiveReport) Complete() bool {
+	return len(ar.Missing) == 0 && len(ar.Altered) == 0
+}
+
+// Report sums up the manifest. Records count the events of each key once: the
+// hourly parts of a key add up, but with ARCHIVE_FORMAT=both its JSON and
+// Parquet objects hold the same events.
+func (m Manifest) Report() ArchiveReport {
+	report := ArchiveReport{Date: m.Date, Objects: m.Objects}
+	// records of each key by whether the object is Parquet
+	formatRecords := make(map[string]map[bool]int)
+	for _, object := range m.Objects {
+		if formatRecords[object.Key] == nil {
+			formatRecords[object.Key] = make(map[bool]int)
+		}
+		formatRecords[object.Key][path.Ext(object.Path) == ".parquet"] += object.Records
+		report.Bytes += object.Size
+		if !object.FirstEventAt.IsZero() && (report.FirstEventAt.IsZero() || object.FirstEventAt.Before(report.FirstEventAt)) {
+			report.FirstEventAt = object.FirstEventAt
+		}
+		if object.LastEventAt.After(report.LastEventAt) {
+			report.LastEventAt = object.LastEventAt
+		}
+	}
+	report.Keys = len(formatRecords)
+	for _, records := range formatRecords {
+		var keyRecords int
+		for _, count := range records {
+			keyRecords = max(keyRecords, count)
+		}
+		report.Records += keyRecords
+	}
+
+	return report
+}
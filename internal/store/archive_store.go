@@ -0,0 +1,188 @@
+package store
+
+//go:generate mockgen -source=archive_store.go -destination=mocks/mock_archive_store.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+)
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrInvalidPath    = errors.New("invalid object path")
+)
+
+// FencingTokenMetadata is the object metadata key holding the leader lease
+// token an archive was written under; a write with a lower token than the
+// object already carries comes from a deposed leader.
+const FencingTokenMetadata = "fencing-token"
+
+// ObjectStorage is the minimal contract an archive backend has to fulfil.
+// Paths are always slash separated, e.g. "audits/user:123/2025-01-15.json".
+type ObjectStorage interface {
+	Put(ctx context.Context, path string, data []byte, expires time.Time) error
+	Get(ctx context.Context, path string) ([]byte, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+func BackupPath(timeNow time.Time) string {
+	return fmt.Sprintf("audits/%d-%02d-%02d.json", timeNow.Year(), timeNow.Month(), timeNow.Day())
+}
+
+// NodeBackupPath is the backup of one cluster node, which only holds the keys
+// it owns; it sits next to the single-node backups.
+func NodeBackupPath(node string, timeNow time.Time) string {
+	return fmt.Sprintf("audits/%d-%02d-%02d.%s.json", timeNow.Year(), timeNow.Month(), timeNow.Day(), node)
+}
+
+// SavePath is the daily object of dataKey, the key encoded by pathSegment so
+// "user:123" is "audits/user%3A123/2025-01-15.json".
+func SavePath(dataKey string, timeNow time.Time) string {
+	return fmt.Sprintf("audits/%s/%d-%02d-%02d.json", pathSegment(dataKey), timeNow.Year(), timeNow.Month(), timeNow.Day())
+}
+
+// pathSegment encodes dataKey by EncodeKey into a single object path segment;
+// "." and "..", which EncodeKey keeps, are encoded as well.
+func pathSegment(dataKey string) string {
+	segment := EncodeKey(Key(dataKey))
+	if segment == "." || segment == ".." {
+		return strings.ReplaceAll(segment, ".", "%2E")
+	}
+	return segment
+}
+
+// ArchiveStore writes backups and daily snapshots to any ObjectStorage,
+// using the same layout and retention as the S3 bucket.
+type ArchiveStore struct {
+	storage   ObjectStorage
+	node      string
+	storeDays int
+}
+
+func NewArchiveStore(storage ObjectStorage) *ArchiveStore {
+	return &ArchiveStore{
+		storage: storage,
+	}
+}
+
+// WithNode writes backups to NodeBackupPath so cluster nodes do not
+// overwrite each other's.
+func (as *ArchiveStore) WithNode(node string) *ArchiveStore {
+	as.node = node
+	return as
+}
+
+// WithRetention keeps snapshots and manifests storeDays instead of
+// BUCKET_EXPIRES_STORE_DAYS, for a tenant with a retention of its own.
+func (as *ArchiveStore) WithRetention(storeDays int) *ArchiveStore {
+	as.storeDays = storeDays
+	return as
+}
+
+// storeExpires is when objects archived at timeNow expire.
+func (as *ArchiveStore) storeExpires(timeNow time.Time) time.Time {
+	storeDays := as.storeDays
+	if storeDays <= 0 {
+		storeDays = cfg.GetConfig().BucketConfig.ExpiresStoreDays
+	}
+	return timeNow.Add(time.Hour * 24 * time.Duration(storeDays))
+}
+
+func (as *ArchiveStore) Backup(ctx context.Context, timeNow time.Time, data []byte) error {
+	cfg := cfg.GetConfig()
+	expires := timeNow.Add(time.Hour * 24 * time.Duration(cfg.BucketConfig.ExpiresBackupDays))
+
+	path := BackupPath(timeNow)
+	if as.node != "" {
+		path = NodeBackupPath(as.node, timeNow)
+	}
+	return as.storage.Put(ctx, path, data, expires)
+}
+
+// Save writes the snapshot of dataKey for the day of timeNow and verifies it.
+// Events archived for that day by an earlier run are kept: a late event joins
+// the object instead of replacing it.
+func (as *ArchiveStore) Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) ([]ArchivedObject, error) {
+	expires := as.storeExpires(timeNow)
+	path := SavePath(dataKey, timeNow)
+
+	var fileData Data
+	if err := json.Unmarshal(data, &fileData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+
+	archived, err := as.storage.Get(ctx, path)
+	switch {
+	case errors.Is(err, ErrObjectNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read archived %s: %w", path, err)
+	default:
+		var archivedData Data
+		if err := json.Unmarshal(archived, &archivedData); err != nil {
+			return nil, fmt.Errorf("failed to decode archived %s: %w", path, err)
+		}
+		fileData = MergeData(archivedData, fileData)
+		if data, err = json.Marshal(fileData); err != nil {
+			return nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+	}
+
+	object, err := putVerified(ctx, as.storage, path, data, expires)
+	if err != nil {
+		return nil, err
+	}
+	object.Key = dataKey
+	object.Date = timeNow.Format(time.DateOnly)
+	object.Records = fileData.Len()
+	object.FirstEventAt, object.LastEventAt = fileData.EventRange()
+
+	return []ArchivedObject{object}, nil
+}
+
+// NewObjectStorage builds the archive backend selected by ARCHIVE_BACKEND.
+func NewObjectStorage(ctx context.Context, conf *cfg.GeneralConfig) (ObjectStorage, error) {
+	archive := conf.ArchiveConfig
+	switch archive.Backend {
+	case "", "s3":
+		return NewS3BucketStore(ctx, S3Config{
+			Bucket:          conf.BucketConfig.Name,
+			Endpoint:        conf.BucketConfig.Endpoint,
+			AccessKeyID:     conf.BucketConfig.AccessKeyID,
+			SecretAccessKey: conf.BucketConfig.SecretAccessKey,
+			Region:          conf.BucketConfig.Region,
+			UsePathStyle:    conf.BucketConfig.UsePathStyle,
+		})
+	case "local":
+		return NewLocalDirStorage(archive.LocalDir)
+	case "gcs":
+		return NewGCSStorage(GCSConfig{
+			Bucket:   archive.GCS.Bucket,
+			Endpoint: archive.GCS.Endpoint,
+			Token:    archive.GCS.Token,
+		}, http.DefaultClient), nil
+	case "azure":
+		return NewAzureBlobStorage(AzureBlobConfig{
+			Endpoint:   archive.Azure.Endpoint,
+			Account:    archive.Azure.Account,
+			AccountKey: archive.Azure.AccountKey,
+			Container:  archive.Azure.Container,
+		}, http.DefaultClient)
+	case "sftp":
+		return DialSFTP(SFTPConfig{
+			Addr:           archive.SFTP.Addr,
+			User:           archive.SFTP.User,
+			Password:       archive.SFTP.Password,
+			KnownHostsFile: archive.SFTP.KnownHostsFile,
+			Dir:            archive.SFTP.Dir,
+		})
+	default:
+		return nil, fmt.Errorf("unknown archive backend: %s", archive.Backend)
+	}
+}
@@ -0,0 +1,340 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestArchiveStore(t *testing.T) {
+	setupTestConfig()
+	fixedTime := time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		call          func(as *ArchiveStore) error
+		setupMock     func(storage *mocks.MockObjectStorage)
+		expectedError bool
+	}{
+		{
+			name: "success - backup writes daily snapshot with backup retention",
+			call: func(as *ArchiveStore) error {
+				return as.Backup(context.Background(), fixedTime, []byte(`{}`))
+			},
+			setupMock: func(storage *mocks.MockObjectStorage) {
+				storage.EXPECT().
+					Put(gomock.Any(), "audits/2025-01-05.json", []byte(`{}`), fixedTime.Add(2*24*time.Hour)).
+					Return(nil)
+			},
+		},
+		{
+			name: "success - cluster node backup gets its own object",
+			call: func(as *ArchiveStore) error {
+				return as.WithNode("node-a").Backup(context.Background(), fixedTime, []byte(`{}`))
+			},
+			setupMock: func(storage *mocks.MockObjectStorage) {
+				storage.EXPECT().
+					Put(gomock.Any(), "audits/2025-01-05.node-a.json", []byte(`{}`), fixedTime.Add(2*24*time.Hour)).
+					Return(nil)
+			},
+		},
+		{
+			name: "success - save writes per key object with store retention",
+			call: func(as *ArchiveStore) error {
+				_, err := as.Save(context.Background(), "user:123", fixedTime, []byte(`{}`))
+				return err
+			},
+			setupMock: func(storage *mocks.MockObjectStorage) {
+				gomock.InOrder(
+					storage.EXPECT().Get(gomock.Any(), "audits/user%3A123/2025-01-05.json").Return(nil, ErrObjectNotFound),
+					storage.EXPECT().
+						Put(gomock.Any(), "audits/user%3A123/2025-01-05.json", []byte(`{}`), fixedTime.Add(365*24*time.Hour)).
+						Return(nil),
+					storage.EXPECT().Get(gomock.Any(), "audits/user%3A123/2025-01-05.json").Return([]byte(`{}`), nil),
+				)
+			},
+		},
+		{
+			name: "success - tenant retention overrides store retention",
+			call: func(as *ArchiveStore) error {
+				_, err := as.WithRetention(30).Save(context.Background(), "user:123", fixedTime, []byte(`{}`))
+				return err
+			},
+			setupMock: func(storage *mocks.MockObjectStorage) {
+				gomock.InOrder(
+					storage.EXPECT().Get(gomock.Any(), "audits/user%3A123/2025-01-05.json").Return(nil, ErrObjectNotFound),
+					storage.EXPECT().
+						Put(gomock.Any(), "audits/user%3A123/2025-01-05.json", []byte(`{}`), fixedTime.Add(30*24*time.Hour)).
+						Return(nil),
+					storage.EXPECT().Get(gomock.Any(), "audits/user%3A123/2025-01-05.json").Return([]byte(`{}`), nil),
+				)
+			},
+		},
+		{
+			name: "error - storage fails",
+			call: func(as *ArchiveStore) error {
+				_, err := as.Save(context.Background(), "user:123", fixedTime, []byte(`{}`))
+				return err
+			},
+			setupMock: func(storage *mocks.MockObjectStorage) {
+				storage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, ErrObjectNotFound)
+				storage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("disk full"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "error - stored object does not match the upload",
+			call: func(as *ArchiveStore) error {
+				_, err := as.Save(context.Background(), "user:123", fixedTime, []byte(`{}`))
+				return err
+			},
+			setupMock: func(storage *mocks.MockObjectStorage) {
+				gomock.InOrder(
+					storage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, ErrObjectNotFound),
+					storage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					storage.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]byte(`{"truncated`), nil),
+				)
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mocks.NewMockObjectStorage(ctrl)
+			tt.setupMock(storage)
+
+			err := tt.call(NewArchiveStore(storage))
+			if (err != nil) != tt.expectedError {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestArchiveStore_SaveKeepsArchivedEvents(t *testing.T) {
+	setupTestConfig()
+	ctx := context.Background()
+	day := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	storage, err := NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	as := NewArchiveStore(storage)
+
+	first := newTestAudit(1, day.Add(time.Hour))
+	late := newTestAudit(2, day.Add(23*time.Hour))
+	for _, event := range []audit.DataAudit{first, late} {
+		payload, _ := json.Marshal(Data{NewDate(day): {event}})
+		if _, err := as.Save(ctx, "order:42", day, payload); err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+	}
+
+	// saving the same events again must not duplicate them
+	payload, _ := json.Marshal(Data{NewDate(day): {first}})
+	objects, err := as.Save(ctx, "order:42", day, payload)
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	archived, err := storage.Get(ctx, "audits/order%3A42/2025-01-05.json")
+	if err != nil {
+		t.Fatalf("failed to get archived object: %v", err)
+	}
+	expected := ArchivedObject{
+		Key:          "order:42",
+		Date:         "2025-01-05",
+		Path:         "audits/order%3A42/2025-01-05.json",
+		Records:      2,
+		Size:         int64(len(archived)),
+		SHA256:       checksum(archived),
+		FirstEventAt: first.Metadata.EventAt,
+		LastEventAt:  late.Metadata.EventAt,
+	}
+	if !reflect.DeepEqual(objects, []ArchivedObject{expected}) {
+		t.Errorf("expected %+v, got %+v", expected, objects)
+	}
+}
+
+func TestArchiveStore_RecordManifest(t *testing.T) {
+	setupTestConfig()
+	ctx := context.Background()
+	day := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	storage, err := NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	as := NewArchiveStore(storage)
+
+	userA := ArchivedObject{Key: "user:a", Date: "2025-01-05", Path: "audits/user:a/2025-01-05.json", Records: 1, Size: 10, SHA256: "aa"}
+	userB := ArchivedObject{Key: "user:b", Date: "2025-01-05", Path: "audits/user:b/2025-01-05.json", Records: 1, Size: 10, SHA256: "bb"}
+	if err := as.RecordManifest(ctx, day, []ArchivedObject{userB, userA}); err != nil {
+		t.Fatalf("failed to record manifest: %v", err)
+	}
+
+	// a retry of user:a replaces its entry
+	userA.Records, userA.SHA256 = 2, "a2"
+	if err := as.RecordManifest(ctx, day, []ArchivedObject{userA}); err != nil {
+		t.Fatalf("failed to record manifest: %v", err)
+	}
+
+	payload, err := storage.Get(ctx, "audits/_manifests/2025-01-05.json")
+	if err != nil {
+		t.Fatalf("failed to get manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+
+	expected := Manifest{Date: "2025-01-05", Objects: []ArchivedObject{userA, userB}}
+	if !reflect.DeepEqual(manifest, expected) {
+		t.Errorf("expected %+v, got %+v", expected, manifest)
+	}
+}
+
+func TestArchiveStore_Reconcile(t *testing.T) {
+	setupTestConfig()
+	ctx := context.Background()
+	day := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	storage, err := NewLocalDirStorage(dir)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	// two cluster nodes archive a key each
+	var first time.Time
+	for i, node := range []string{"node-a", "node-b"} {
+		as := NewArchiveStore(storage).WithNode(node)
+		event := newTestAudit(i, day.Add(time.Duration(i+1)*time.Hour))
+		if i == 0 {
+			first = event.Metadata.EventAt
+		}
+		payload, _ := json.Marshal(Data{NewDate(day): {event}})
+		objects, err := as.Save(ctx, node, day, payload)
+		if err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+		if err := as.RecordManifest(ctx, day, objects); err != nil {
+			t.Fatalf("failed to record manifest: %v", err)
+		}
+	}
+
+	as := NewArchiveStore(storage)
+	if _, err := as.Manifest(ctx, day.AddDate(0, 0, 1)); !errors.Is(err, ErrManifestNotFound) {
+		t.Errorf("expected ErrManifestNotFound, got %v", err)
+	}
+
+	report, err := as.Reconcile(ctx, day)
+	if err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if !report.Complete() || report.Keys != 2 || report.Records != 2 || len(report.Objects) != 2 {
+		t.Errorf("expected a complete report of 2 keys, got %+v", report)
+	}
+	if !report.FirstEventAt.Equal(first) || !report.LastEventAt.Equal(first.Add(time.Hour)) {
+		t.Errorf("unexpected event range %v - %v", report.FirstEventAt, report.LastEventAt)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "audits/node-a/2025-01-05.json")); err != nil {
+		t.Fatalf("failed to remove object: %v", err)
+	}
+	if err := storage.Put(ctx, "audits/node-b/2025-01-05.json", []byte(`{}`), day); err != nil {
+		t.Fatalf("failed to overwrite object: %v", err)
+	}
+
+	report, err = as.Reconcile(ctx, day)
+	if err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].Path != "audits/node-a/2025-01-05.json" {
+		t.Errorf("expected node-a object missing, got %+v", report.Missing)
+	}
+	if len(report.Altered) != 1 || report.Altered[0].Path != "audits/node-b/2025-01-05.json" {
+		t.Errorf("expected node-b object altered, got %+v", report.Altered)
+	}
+}
+
+// testObjectStorage exercises the ObjectStorage contract shared by every backend.
+func testObjectStorage(t *testing.T, storage ObjectStorage) {
+	t.Helper()
+	ctx := context.Background()
+	expires := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	objects := map[string][]byte{
+		"audits/2025-01-05.json":          []byte(`{"backup":true}`),
+		"audits/user:123/2025-01-05.json": []byte(`{"key":"user:123"}`),
+		"audits/user:456/2025-01-05.json": []byte(`{"key":"user:456"}`),
+	}
+	for path, data := range objects {
+		if err := storage.Put(ctx, path, data, expires); err != nil {
+			t.Fatalf("failed to put %s: %v", path, err)
+		}
+	}
+
+	for path, expected := range objects {
+		data, err := storage.Get(ctx, path)
+		if err != nil {
+			t.Fatalf("failed to get %s: %v", path, err)
+		}
+		if string(data) != string(expected) {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	}
+
+	if _, err := storage.Get(ctx, "audits/missing.json"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+
+	paths, err := storage.List(ctx, "audits/user:")
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+
+	expected := []string{"audits/user:123/2025-01-05.json", "audits/user:456/2025-01-05.json"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestLocalDirStorage(t *testing.T) {
+	storage, err := NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	testObjectStorage(t, storage)
+
+	for _, path := range []string{"../escape.json", "audits/../../escape.json", "/etc/escape.json"} {
+		if err := storage.Put(context.Background(), path, []byte(`{}`), time.Time{}); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("expected ErrInvalidPath writing %s, got %v", path, err)
+		}
+		if _, err := storage.Get(context.Background(), path); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("expected ErrInvalidPath reading %s, got %v", path, err)
+		}
+	}
+
+	if got := SavePath("../../escape", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)); got != "audits/..%2F..%2Fescape/2025-01-05.json" {
+		t.Errorf("expected the key to stay one segment, got %s", got)
+	}
+	if got := SavePath("..", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)); got != "audits/%2E%2E/2025-01-05.json" {
+		t.Errorf("expected the key .. to be encoded, got %s", got)
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrVerifyFailed = errors.New("archived object does not match the upload")
+
+// ArchivedObject is an object Save wrote and read back from the storage.
+type ArchivedObject struct {
+	Key          string    `json:"key"`
+	Date         string    `json:"date"`
+	Path         string    `json:"path"`
+	Records      int       `json:"records"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	FirstEventAt time.Time `json:"first_event_at,omitzero"`
+	LastEventAt  time.Time `json:"last_event_at,omitzero"`
+}
+
+type ObjectInfo struct {
+	Size   int64
+	SHA256 string
+}
+
+// Stater is implemented by backends that report the size and checksum of an
+// object without downloading it; StatObject reads the others back.
+type Stater interface {
+	Stat(ctx context.Context, path string) (ObjectInfo, error)
+}
+
+func StatObject(ctx context.Context, storage ObjectStorage, path string) (ObjectInfo, error) {
+	if stater, ok := storage.(Stater); ok {
+		return stater.Stat(ctx, path)
+	}
+
+	data, err := storage.Get(ctx, path)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Size: int64(len(data)), SHA256: checksum(data)}, nil
+}
+
+// putVerified writes data to path and checks the stored object has its size
+// and checksum, so nothing is deleted locally on the word of a lost write.
+func putVerified(ctx context.Context, storage ObjectStorage, path string, data []byte, expires time.Time) (ArchivedObject, error) {
+	if err := storage.Put(ctx, path, data, expires); err != nil {
+		return ArchivedObject{}, err
+	}
+
+	info, err := StatObject(ctx, storage, path)
+	if err != nil {
+		return ArchivedObject{}, fmt.Errorf("failed to verify %s: %w", path, err)
+	}
+
+	object := ArchivedObject{Path: path, Size: int64(len(data)), SHA256: checksum(data)}
+	if info.Size != object.Size || info.SHA256 != object.SHA256 {
+		return ArchivedObject{}, fmt.Errorf("%w: %s has %d bytes sha256 %s, uploaded %d bytes sha256 %s",
+			ErrVerifyFailed, path, info.Size, info.SHA256, object.Size, object.SHA256)
+	}
+
+	return object, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
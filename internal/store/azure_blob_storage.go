@@ -0,0 +1,217 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+const azureAPIVersion = "2021-08-06"
+
+type AzureBlobConfig struct {
+	// Endpoint is the account URL, e.g. https://{account}.blob.core.windows.net
+	// or http://127.0.0.1:10000/devstoreaccount1 for Azurite.
+	Endpoint   string
+	Account    string
+	AccountKey string // base64, as shown in the portal
+	Container  string
+}
+
+// AzureBlobStorage uses the Blob REST API with Shared Key authorization.
+type AzureBlobStorage struct {
+	endpoint  string
+	account   string
+	key       []byte
+	container string
+	client    *http.Client
+}
+
+func NewAzureBlobStorage(azCfg AzureBlobConfig, client *http.Client) (*AzureBlobStorage, error) {
+	key, err := base64.StdEncoding.DecodeString(azCfg.AccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure account key: %w", err)
+	}
+
+	return &AzureBlobStorage{
+		endpoint:  strings.TrimSuffix(azCfg.Endpoint, "/"),
+		account:   azCfg.Account,
+		key:       key,
+		container: azCfg.Container,
+		client:    client,
+	}, nil
+}
+
+func (abs *AzureBlobStorage) blobURL(path string) string {
+	return fmt.Sprintf("%s/%s/%s", abs.endpoint, abs.container, (&url.URL{Path: path}).EscapedPath())
+}
+
+func (abs *AzureBlobStorage) do(req *http.Request, contentLength int) (*http.Response, error) {
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	req.Header.Set("Authorization", "SharedKey "+abs.account+":"+abs.sign(req, contentLength))
+	return abs.client.Do(req)
+}
+
+// sign builds the Shared Key signature described in
+// https://learn.microsoft.com/rest/api/storageservices/authorize-with-shared-key
+func (abs *AzureBlobStorage) sign(req *http.Request, contentLength int) string {
+	length := ""
+	if contentLength > 0 {
+		length = strconv.Itoa(contentLength)
+	}
+
+	var msHeaders []string
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-ms-") {
+			msHeaders = append(msHeaders, lower+":"+strings.TrimSpace(req.Header.Get(name)))
+		}
+	}
+	sort.Strings(msHeaders)
+
+	resource := "/" + abs.account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	var params []string
+	for name := range query {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	for _, name := range params {
+		values := query[name]
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(name) + ":" + strings.Join(values, ",")
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		length,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, superseded by x-ms-date
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		strings.Join(msHeaders, "\n"),
+		resource,
+	}, "\n")
+
+	mac := hmac.New(sha256.New, abs.key)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (abs *AzureBlobStorage) Put(ctx context.Context, path string, data []byte, expires time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, abs.blobURL(path), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create azure request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("x-ms-meta-expires", expires.UTC().Format(time.RFC3339))
+	if token, ok := ctxkey.FencingToken(ctx); ok {
+		req.Header.Set("x-ms-meta-"+FencingTokenMetadata, strconv.FormatUint(token, 10))
+	}
+
+	resp, err := abs.do(req, len(data))
+	if err != nil {
+		return fmt.Errorf("failed to upload to azure: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload to azure: status %d: %s", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+func (abs *AzureBlobStorage) Get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, abs.blobURL(path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure request: %w", err)
+	}
+
+	resp, err := abs.do(req, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download from azure: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to download from azure: status %d: %s", resp.StatusCode, body)
+	}
+}
+
+type azureListResponse struct {
+	Blobs struct {
+		Blob []struct {
+			Name string `xml:"Name"`
+		} `xml:"Blob"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (abs *AzureBlobStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {prefix}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		endpoint := fmt.Sprintf("%s/%s?%s", abs.endpoint, abs.container, query.Encode())
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create azure request: %w", err)
+		}
+
+		resp, err := abs.do(req, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list azure blobs: %w", err)
+		}
+
+		var page azureListResponse
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to list azure blobs: status %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode azure list: %w", err)
+		}
+
+		for _, blob := range page.Blobs.Blob {
+			paths = append(paths, blob.Name)
+		}
+
+		if page.NextMarker == "" {
+			return paths, nil
+		}
+		marker = page.NextMarker
+	}
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newFakeAzureServer mimics Azurite's path style endpoint and rejects requests
+// whose Shared Key signature does not match.
+func newFakeAzureServer(t *testing.T, abs *AzureBlobStorage) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	blobs := make(map[string][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength := int(r.ContentLength)
+		if contentLength < 0 {
+			contentLength = 0
+		}
+		expected := "SharedKey " + abs.account + ":" + abs.sign(r, contentLength)
+		if r.Header.Get("Authorization") != expected {
+			http.Error(w, "signature mismatch", http.StatusForbidden)
+			return
+		}
+
+		containerPrefix := "/" + abs.account + "/" + abs.container
+		name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, containerPrefix), "/")
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPut:
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				http.Error(w, "missing blob type", http.StatusBadRequest)
+				return
+			}
+			blobs[name], _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Query().Get("comp") == "list":
+			var page azureListResponse
+			var names []string
+			for blob := range blobs {
+				if strings.HasPrefix(blob, r.URL.Query().Get("prefix")) {
+					names = append(names, blob)
+				}
+			}
+			sort.Strings(names)
+			for _, blob := range names {
+				page.Blobs.Blob = append(page.Blobs.Blob, struct {
+					Name string `xml:"Name"`
+				}{Name: blob})
+			}
+			_ = xml.NewEncoder(w).Encode(struct {
+				XMLName xml.Name `xml:"EnumerationResults"`
+				azureListResponse
+			}{azureListResponse: page})
+		default:
+			data, ok := blobs[name]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAzureBlobStorage(t *testing.T) {
+	azCfg := AzureBlobConfig{
+		Account:    "devstoreaccount1",
+		AccountKey: base64.StdEncoding.EncodeToString([]byte("azurite-test-key")),
+		Container:  "auditory",
+	}
+
+	signer, err := NewAzureBlobStorage(azCfg, nil)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	server := newFakeAzureServer(t, signer)
+	azCfg.Endpoint = server.URL + "/devstoreaccount1"
+
+	storage, err := NewAzureBlobStorage(azCfg, server.Client())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	testObjectStorage(t, storage)
+}
+
+func TestNewAzureBlobStorage_InvalidKey(t *testing.T) {
+	_, err := NewAzureBlobStorage(AzureBlobConfig{AccountKey: "not base64!"}, nil)
+	if err == nil {
+		t.Errorf("expected error for invalid account key")
+	}
+}
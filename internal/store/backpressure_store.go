@@ -0,0 +1,39 @@
+package store
+
+//go:generate mockgen -source=backpressure_store.go -destination=mocks/mock_backpressure_store.go -package=mocks
+
+import (
+	"context"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+type AuditStore interface {
+	Upsert(ctx context.Context, input audit.DataAudit) error
+}
+
+type PressureGauge interface {
+	Pressure() error
+}
+
+// BackpressureStore refuses audits while the gauge reports pressure, before
+// they reach the wrapped store, so a replicated or primary store is shed too.
+type BackpressureStore struct {
+	store AuditStore
+	gauge PressureGauge
+}
+
+func NewBackpressureStore(store AuditStore, gauge PressureGauge) *BackpressureStore {
+	return &BackpressureStore{
+		store: store,
+		gauge: gauge,
+	}
+}
+
+func (bs *BackpressureStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	if err := bs.gauge.Pressure(); err != nil {
+		return err
+	}
+
+	return bs.store.Upsert(ctx, input)
+}
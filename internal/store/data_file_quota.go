@@ -0,0 +1,396 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+// Quota policies for an audit that does not fit.
+const (
+	QuotaReject     = "reject"
+	QuotaDropOldest = "drop_oldest"
+	QuotaSpill      = "spill"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("local store quota exceeded")
+	ErrBackpressure  = errors.New("local store is near its quota")
+)
+
+// Throttled reports whether err asks the client to retry later.
+func Throttled(err error) bool {
+	return errors.Is(err, ErrBackpressure) || errors.Is(err, ErrQuotaExceeded)
+}
+
+// quota counts the bytes of every file in tmp/ and the oldest day it holds.
+type quota struct {
+	conf cfg.QuotaConfig
+
+	mu    sync.Mutex
+	files map[Key]fileUsage
+	total int64
+}
+
+type fileUsage struct {
+	bytes  int64
+	oldest time.Time
+}
+
+// WithQuota bounds tmp/ by conf, counting the files already there.
+func (dfs *DataFileStore) WithQuota(conf cfg.QuotaConfig) (*DataFileStore, error) {
+	switch conf.Policy {
+	case QuotaReject:
+	case QuotaDropOldest:
+		// ALERT
+		logger.Warn("local store quota drops the oldest local days when full; they were never archived and are lost", "max_bytes", conf.MaxBytes, "max_key_bytes", conf.MaxKeyBytes)
+	case QuotaSpill:
+		if err := os.MkdirAll(conf.SpillDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create spill directory: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown quota policy: %s", conf.Policy)
+	}
+
+	dfs.quota = &quota{conf: conf, files: make(map[Key]fileUsage)}
+
+	keys, err := dfs.Keys(context.Background())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list local files: %w", err)
+	}
+	for _, key := range keys {
+		info, err := os.Stat(dfs.path(Key(key)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", key, err)
+		}
+		data, err := dfs.Get(context.Background(), Key(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		dfs.quota.set(Key(key), data, info.Size())
+	}
+
+	return dfs, nil
+}
+
+// Pressure fails with ErrBackpressure once the local files reach the
+// backpressure ratio of the total quota, so callers can shed load before
+// audits start to be rejected, dropped or spilled.
+func (dfs *DataFileStore) Pressure() error {
+	q := dfs.quota
+	if q == nil || q.conf.MaxBytes <= 0 || q.conf.BackpressureRatio <= 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if float64(q.total) >= q.conf.BackpressureRatio*float64(q.conf.MaxBytes) {
+		return ErrBackpressure
+	}
+	return nil
+}
+
+// RetryAfter is how long a throttled client should wait.
+func (dfs *DataFileStore) RetryAfter() time.Duration {
+	if dfs.quota == nil {
+		return 0
+	}
+	return dfs.quota.conf.RetryAfter
+}
+
+// track records the size of the file of key after a write, 0 once removed.
+func (dfs *DataFileStore) track(key Key, data Data, size int64) {
+	if dfs.quota != nil {
+		dfs.quota.set(key, data, size)
+	}
+}
+
+func (q *quota) set(key Key, data Data, size int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.total += size - q.files[key].bytes
+	if size == 0 {
+		delete(q.files, key)
+	} else {
+		q.files[key] = fileUsage{bytes: size, oldest: oldestDay(data)}
+	}
+	metrics.FileStoreBytes.Set(float64(q.total))
+}
+
+func (q *quota) fits(key Key, size int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.conf.MaxKeyBytes > 0 && int64(size) > q.conf.MaxKeyBytes {
+		return false
+	}
+	return q.conf.MaxBytes <= 0 || q.total-q.files[key].bytes+int64(size) <= q.conf.MaxBytes
+}
+
+// byAge lists the keys other than key, oldest day first.
+func (q *quota) byAge(key Key) []Key {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	keys := make([]Key, 0, len(q.files))
+	for other := range q.files {
+		if other != key {
+			keys = append(keys, other)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return q.files[keys[i]].oldest.Before(q.files[keys[j]].oldest) })
+	return keys
+}
+
+// fit applies the quota policy to fileData, the file of key with input added,
+// already marshalled to payload. It returns what to write, possibly smaller
+// with drop_oldest, or spilled once input went to the spill directory.
+// Called with the lock of key held.
+func (dfs *DataFileStore) fit(ctx context.Context, key Key, fileData Data, input audit.DataAudit, payload []byte) (_ []byte, spilled bool, err error) {
+	q := dfs.quota
+	for !q.fits(key, len(payload)) {
+		switch q.conf.Policy {
+		case QuotaSpill:
+			if err := dfs.spill(key, input); err != nil {
+				return nil, false, err
+			}
+			metrics.FileStoreQuotaEvents.WithLabelValues(metrics.QuotaSpilled).Inc()
+			logger.WarnContext(ctx, "local store quota reached, audit spilled", "key", key)
+			return nil, true, nil
+
+		case QuotaDropOldest:
+			ownDropped, dropped, err := dfs.dropOldest(ctx, key, fileData, len(payload))
+			if err != nil {
+				return nil, false, err
+			}
+			if !dropped {
+				break
+			}
+			if ownDropped {
+				if payload, err = json.Marshal(fileData); err != nil {
+					return nil, false, fmt.Errorf("failed to marshal data: %w", err)
+				}
+			}
+			continue
+		}
+
+		metrics.FileStoreQuotaEvents.WithLabelValues(metrics.QuotaRejected).Inc()
+		return nil, false, ErrQuotaExceeded
+	}
+
+	return payload, false, nil
+}
+
+// dropOldest frees room by dropping the oldest local day: of key itself when
+// it is over its own quota or holds the oldest day, otherwise of the key that
+// does. The day the incoming audit is filed on is never dropped from key, and
+// keys busy with a write of their own are passed over. Archived days already
+// left tmp/, so a dropped day is lost for good: every dropped audit is counted
+// under the dropped action and logged as an ALERT.
+func (dfs *DataFileStore) dropOldest(ctx context.Context, key Key, fileData Data, size int) (ownDropped, dropped bool, err error) {
+	q := dfs.quota
+	today := NewDate(clock.Now())
+
+	ownDate, ownDay := oldestDate(fileData, today)
+	others := q.byAge(key)
+	overKey := q.conf.MaxKeyBytes > 0 && int64(size) > q.conf.MaxKeyBytes
+
+	q.mu.Lock()
+	ownIsOldest := ownDate != "" && (len(others) == 0 || !q.files[others[0]].oldest.Before(ownDay))
+	q.mu.Unlock()
+
+	if ownDate != "" && (overKey || ownIsOldest) {
+		dropEvents(ctx, key, ownDate, len(fileData[ownDate]))
+		delete(fileData, ownDate)
+		return true, true, nil
+	}
+	if overKey {
+		return false, false, nil
+	}
+
+	for _, other := range others {
+		mu := dfs.mu.GetOrCreate(string(other))
+		if !mu.TryLock() {
+			continue
+		}
+		dropped, err := dfs.dropOldestInternal(ctx, other)
+		mu.Unlock()
+		if err != nil || dropped {
+			return false, dropped, err
+		}
+	}
+
+	return false, false, nil
+}
+
+// dropOldestInternal drops the oldest day of key without acquiring lock (for
+// internal use when lock is already held).
+func (dfs *DataFileStore) dropOldestInternal(ctx context.Context, key Key) (bool, error) {
+	fileData, err := dfs.getInternal(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get data: %w", err)
+	}
+
+	date, _ := oldestDate(fileData, "")
+	if date == "" {
+		return false, nil
+	}
+	dropEvents(ctx, key, date, len(fileData[date]))
+	delete(fileData, date)
+
+	if len(fileData) == 0 {
+		return true, dfs.removeInternal(key)
+	}
+	return true, dfs.writeInternal(key, fileData)
+}
+
+func dropEvents(ctx context.Context, key Key, date Date, events int) {
+	metrics.FileStoreQuotaEvents.WithLabelValues(metrics.QuotaDropped).Add(float64(events))
+	// ALERT
+	logger.ErrorContext(ctx, "ALERT: local store quota reached, oldest day dropped", "key", key, "date", date, "events", events)
+}
+
+// oldestDate returns the oldest day of data other than except.
+func oldestDate(data Data, except Date) (Date, time.Time) {
+	var oldest Date
+	var oldestDay time.Time
+	for date := range data {
+		day, err := date.Time()
+		if date == except || err != nil {
+			continue
+		}
+		if oldest == "" || day.Before(oldestDay) {
+			oldest, oldestDay = date, day
+		}
+	}
+	return oldest, oldestDay
+}
+
+func oldestDay(data Data) time.Time {
+	_, day := oldestDate(data, "")
+	return day
+}
+
+func (dfs *DataFileStore) spillPath(key Key) string {
+	return filepath.Join(dfs.quota.conf.SpillDir, EncodeKey(key)+".json")
+}
+
+// spill adds input to the spill file of key; the lock of key guards it as it
+// guards the file in tmp/.
+func (dfs *DataFileStore) spill(key Key, input audit.DataAudit) error {
+	path := dfs.spillPath(key)
+
+	spilled := make(Data)
+	payload, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read spill file: %w", err)
+	default:
+		if err := json.Unmarshal(payload, &spilled); err != nil {
+			return fmt.Errorf("failed to decode spill file: %w", err)
+		}
+	}
+
+	spilled = MergeData(spilled, Data{NewDate(clock.Now()): {input}})
+	if payload, err = json.Marshal(spilled); err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+	if err := os.WriteFile(path, payload, 0644); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	return nil
+}
+
+// DrainSpill moves spilled keys back into tmp/ while they fit, so they are
+// served and archived again. It stops at the first key that does not fit and
+// returns how many were moved.
+func (dfs *DataFileStore) DrainSpill(ctx context.Context) (int, error) {
+	if dfs.quota == nil || dfs.quota.conf.Policy != QuotaSpill {
+		return 0, nil
+	}
+
+	files, err := os.ReadDir(dfs.quota.conf.SpillDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list spill directory: %w", err)
+	}
+
+	drained := 0
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".json")
+		if file.IsDir() || !ok {
+			continue
+		}
+		key, err := DecodeKey(name)
+		if err != nil {
+			continue
+		}
+
+		moved, err := dfs.drainKey(key)
+		if err != nil || !moved {
+			return drained, err
+		}
+		drained++
+	}
+
+	if drained > 0 {
+		logger.InfoContext(ctx, "spilled audits moved back to the local store", "keys", drained)
+	}
+	return drained, nil
+}
+
+func (dfs *DataFileStore) drainKey(key Key) (bool, error) {
+	mu := dfs.mu.GetOrCreate(string(key))
+	mu.Lock()
+	defer mu.Unlock()
+
+	path := dfs.spillPath(key)
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read spill file: %w", err)
+	}
+	var spilled Data
+	if err := json.Unmarshal(payload, &spilled); err != nil {
+		return false, fmt.Errorf("failed to decode spill file: %w", err)
+	}
+
+	fileData, err := dfs.getInternal(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get data: %w", err)
+	}
+	existed := len(fileData) > 0
+	fileData = MergeData(fileData, spilled)
+
+	merged, err := json.Marshal(fileData)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal data: %w", err)
+	}
+	if !dfs.quota.fits(key, len(merged)) {
+		if !existed {
+			// getInternal created it
+			return false, dfs.removeInternal(key)
+		}
+		return false, nil
+	}
+
+	if err := dfs.writeInternal(key, fileData); err != nil {
+		return false, err
+	}
+	if err := os.Remove(path); err != nil {
+		return false, fmt.Errorf("failed to remove spill file: %w", err)
+	}
+	return true, nil
+}
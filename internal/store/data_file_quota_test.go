@@ -0,0 +1,204 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/store/mocks"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/mock/gomock"
+)
+
+func quotaAudit(key, requestID string, eventAt time.Time) audit.DataAudit {
+	return audit.DataAudit{
+		Metadata: audit.MetadataAudit{Key: key, EventName: "user.created", RequestID: requestID, EventAt: eventAt},
+		Data:     map[string]any{"name": "John Doe"},
+	}
+}
+
+func fileSize(t *testing.T, key Key) int64 {
+	t.Helper()
+	info, err := os.Stat(NewFilePath("tmp", key).String())
+	if err != nil {
+		t.Fatalf("failed to stat %s: %v", key, err)
+	}
+	return info.Size()
+}
+
+func TestDataFileStore_Quota(t *testing.T) {
+	yesterday := time.Date(2025, 1, 14, 10, 0, 0, 0, time.UTC)
+	today := yesterday.Add(24 * time.Hour)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+	ctx := context.Background()
+
+	t.Run("error - reject refuses an audit that does not fit", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+		clock.SetNow(today)
+
+		dfs, err := NewDataFileStore().WithQuota(cfg.QuotaConfig{MaxKeyBytes: 10, Policy: QuotaReject})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = dfs.Upsert(ctx, quotaAudit("user:a", "req-1", today))
+		if !errors.Is(err, ErrQuotaExceeded) || !Throttled(err) {
+			t.Errorf("expected ErrQuotaExceeded, got %v", err)
+		}
+	})
+
+	t.Run("success - drop_oldest drops the oldest day of another key", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+
+		clock.SetNow(yesterday)
+		if err := NewDataFileStore().Upsert(ctx, quotaAudit("user:a", "req-1", yesterday)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		clock.SetNow(today)
+		incoming := quotaAudit("user:b", "req-2", today)
+		payload, _ := json.Marshal(Data{NewDate(today): {incoming}})
+
+		dfs, err := NewDataFileStore().WithQuota(cfg.QuotaConfig{MaxBytes: fileSize(t, "user:a") + int64(len(payload)) - 1, Policy: QuotaDropOldest})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		dropped := testutil.ToFloat64(metrics.FileStoreQuotaEvents.WithLabelValues(metrics.QuotaDropped))
+		if err := dfs.Upsert(ctx, incoming); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := testutil.ToFloat64(metrics.FileStoreQuotaEvents.WithLabelValues(metrics.QuotaDropped)); got != dropped+1 {
+			t.Errorf("expected the lost audit to be counted, got %v dropped", got-dropped)
+		}
+
+		if _, err := os.Stat(NewFilePath("tmp", "user:a").String()); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected the file of user:a to be dropped, got %v", err)
+		}
+		data, _ := dfs.Get(ctx, "user:b")
+		if len(data[NewDate(today)]) != 1 {
+			t.Errorf("expected the audit of user:b to be stored, got %+v", data)
+		}
+	})
+
+	t.Run("success - spill keeps the audit aside until it fits again", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+		clock.SetNow(today)
+
+		dfs, err := NewDataFileStore().WithQuota(cfg.QuotaConfig{MaxBytes: 10, Policy: QuotaSpill, SpillDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := dfs.Upsert(ctx, quotaAudit("user:a", "req-1", today)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(dfs.spillPath("user:a")); err != nil {
+			t.Fatalf("expected a spill file, got %v", err)
+		}
+
+		if drained, err := dfs.DrainSpill(ctx); err != nil || drained != 0 {
+			t.Errorf("expected nothing drained while over quota, got %d, %v", drained, err)
+		}
+
+		dfs.quota.conf.MaxBytes = 0
+		if drained, err := dfs.DrainSpill(ctx); err != nil || drained != 1 {
+			t.Fatalf("expected one key drained, got %d, %v", drained, err)
+		}
+		data, _ := dfs.Get(ctx, "user:a")
+		if len(data[NewDate(today)]) != 1 {
+			t.Errorf("expected the spilled audit back in the local store, got %+v", data)
+		}
+		if _, err := os.Stat(dfs.spillPath("user:a")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected the spill file to be removed, got %v", err)
+		}
+	})
+
+	t.Run("error - unknown policy", func(t *testing.T) {
+		if _, err := NewDataFileStore().WithQuota(cfg.QuotaConfig{Policy: "compress"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestDataFileStore_Pressure(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	input := quotaAudit("user:a", "req-1", time.Now())
+	payload, _ := json.Marshal(Data{NewDate(clock.Now()): {input}})
+
+	// one audit takes two thirds of the quota
+	dfs, err := NewDataFileStore().WithQuota(cfg.QuotaConfig{MaxBytes: int64(len(payload)) * 3 / 2, BackpressureRatio: 0.5, Policy: QuotaReject})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := dfs.Pressure(); err != nil {
+		t.Fatalf("expected no pressure on an empty store, got %v", err)
+	}
+
+	if err := dfs.Upsert(ctx, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := dfs.Pressure(); !errors.Is(err, ErrBackpressure) {
+		t.Errorf("expected ErrBackpressure past the ratio, got %v", err)
+	}
+
+	if _, err := dfs.Take(ctx, "user:a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := dfs.Pressure(); err != nil {
+		t.Errorf("expected the pressure to go once the file is gone, got %v", err)
+	}
+}
+
+func TestBackpressureStore_Upsert(t *testing.T) {
+	input := quotaAudit("user:a", "req-1", time.Now())
+
+	tests := []struct {
+		name          string
+		setupMocks    func(store *mocks.MockAuditStore, gauge *mocks.MockPressureGauge)
+		expectedError error
+	}{
+		{
+			name: "success - audit reaches the store",
+			setupMocks: func(store *mocks.MockAuditStore, gauge *mocks.MockPressureGauge) {
+				gauge.EXPECT().Pressure().Return(nil)
+				store.EXPECT().Upsert(gomock.Any(), input).Return(nil)
+			},
+		},
+		{
+			name: "error - audit is refused under pressure",
+			setupMocks: func(store *mocks.MockAuditStore, gauge *mocks.MockPressureGauge) {
+				gauge.EXPECT().Pressure().Return(ErrBackpressure)
+			},
+			expectedError: ErrBackpressure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mocks.NewMockAuditStore(ctrl)
+			gauge := mocks.NewMockPressureGauge(ctrl)
+			tt.setupMocks(store, gauge)
+
+			err := NewBackpressureStore(store, gauge).Upsert(context.Background(), input)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -6,47 +6,247 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
 	"github.com/IsaacDSC/auditory/pkg/clock"
 	"github.com/IsaacDSC/auditory/pkg/mu"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// DefaultDataDir is where the local store keeps its files unless WithDir
+// says otherwise.
+const DefaultDataDir = "tmp"
+
+var ErrInvalidTenant = errors.New("invalid tenant")
+
 type FilePath string
 
-func NewFilePath(key Key) FilePath {
-	return FilePath(fmt.Sprintf("tmp/%s.json", key))
+// NewFilePath is the file of key in dir, its name encoded by EncodeKey.
+func NewFilePath(dir string, key Key) FilePath {
+	return FilePath(filepath.Join(dir, EncodeKey(key)+".json"))
 }
 
 func (fp FilePath) String() string {
 	return string(fp)
 }
 
+// EncodeKey turns key into a portable file name: letters, digits, "-", "_"
+// and "." are kept, every other byte is percent-encoded, so "user:123" is
+// "user%3A123" and a key like "../x" cannot leave the directory.
+func EncodeKey(key Key) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if isKeyChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// DecodeKey reverses EncodeKey; names written before the encoding, such as
+// "user:123", decode to themselves.
+func DecodeKey(name string) (Key, error) {
+	key, err := url.PathUnescape(name)
+	if err != nil {
+		return "", err
+	}
+	return Key(key), nil
+}
+
+func isKeyChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.'
+}
+
+// ValidateTenant accepts tenant names usable as a directory and an object
+// prefix as they are: letters, digits, "-", "_" and ".", but not "." or "..".
+func ValidateTenant(tenant string) error {
+	if tenant == "" || tenant == "." || tenant == ".." || EncodeKey(Key(tenant)) != tenant {
+		return fmt.Errorf("%w: %q", ErrInvalidTenant, tenant)
+	}
+	return nil
+}
+
+// TenantDir is the directory of tenant under the data directory.
+func TenantDir(dataDir, tenant string) string {
+	return filepath.Join(dataDir, "tenants", tenant)
+}
+
 type DataFileStore struct {
-	mu mu.MutexByKey
+	dir   string
+	mu    mu.MutexByKey
+	quota *quota
 }
 
 func NewDataFileStore() *DataFileStore {
 	return &DataFileStore{
-		mu: make(mu.MutexByKey),
+		dir: DefaultDataDir,
+		mu:  make(mu.MutexByKey),
+	}
+}
+
+// WithDir keeps the files in dir, creating it, and renames the files written
+// before keys were encoded.
+func (dfs *DataFileStore) WithDir(dir string) (*DataFileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	dfs.dir = dir
+
+	if err := dfs.migrateNames(); err != nil {
+		return nil, err
+	}
+	return dfs, nil
+}
+
+// ForTenant is a store of its own for tenant, in TenantDir.
+func (dfs *DataFileStore) ForTenant(tenant string) (*DataFileStore, error) {
+	if err := ValidateTenant(tenant); err != nil {
+		return nil, err
 	}
+	return NewDataFileStore().WithDir(TenantDir(dfs.dir, tenant))
+}
+
+// Dir is the directory of the files.
+func (dfs *DataFileStore) Dir() string {
+	return dfs.dir
+}
+
+func (dfs *DataFileStore) path(key Key) string {
+	return NewFilePath(dfs.dir, key).String()
+}
+
+// migrateNames moves files named after the raw key, e.g. "user:123.json",
+// to their encoded name, merging them with any file already there.
+func (dfs *DataFileStore) migrateNames() error {
+	files, err := os.ReadDir(dfs.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list data directory: %w", err)
+	}
+
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".json")
+		if file.IsDir() || !ok {
+			continue
+		}
+		key, err := DecodeKey(name)
+		if err != nil || EncodeKey(key) == name {
+			continue
+		}
+
+		legacy := filepath.Join(dfs.dir, file.Name())
+		payload, err := os.ReadFile(legacy)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", legacy, err)
+		}
+		var data Data
+		if len(payload) > 0 {
+			if err := json.Unmarshal(payload, &data); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", legacy, err)
+			}
+		}
+		if len(data) > 0 {
+			if err := dfs.Merge(context.Background(), key, data); err != nil {
+				return err
+			}
+		}
+		if err := os.Remove(legacy); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", legacy, err)
+		}
+	}
+
+	return nil
 }
 
 type Key string
 
 type Date string
 
+// NewDate is the UTC day of t, the day partitions and StoredHour count in,
+// whatever the zone of the host or of t.
 func NewDate(t time.Time) Date {
+	t = t.UTC()
 	return Date(fmt.Sprintf("%d-%d-%d", t.Year(), t.Month(), t.Day()))
 }
 
+// Time parses the non zero-padded date back into midnight UTC.
+func (d Date) Time() (time.Time, error) {
+	return time.Parse("2006-1-2", string(d))
+}
+
 type Data map[Date][]audit.DataAudit
 
-func (dfs *DataFileStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+// Len counts the events of every day.
+func (d Data) Len() int {
+	var total int
+	for _, events := range d {
+		total += len(events)
+	}
+	return total
+}
+
+// EventRange returns the earliest and the latest event at of every day.
+func (d Data) EventRange() (first, last time.Time) {
+	for _, events := range d {
+		for _, event := range events {
+			eventAt := event.Metadata.EventAt
+			if first.IsZero() || eventAt.Before(first) {
+				first = eventAt
+			}
+			if eventAt.After(last) {
+				last = eventAt
+			}
+		}
+	}
+	return first, last
+}
+
+// MergeData adds the events of src missing from dst, keeping each day sorted
+// by event at desc, and returns dst.
+func MergeData(dst, src Data) Data {
+	if dst == nil {
+		dst = make(Data, len(src))
+	}
+
+	for date, events := range src {
+		existing := dst[date]
+		seen := make(map[string]bool, len(existing))
+		for _, event := range existing {
+			seen[eventID(event)] = true
+		}
+
+		for _, event := range events {
+			if id := eventID(event); !seen[id] {
+				seen[id] = true
+				existing = append(existing, event)
+			}
+		}
+
+		sort.Slice(existing, func(i, j int) bool {
+			return existing[i].Metadata.EventAt.After(existing[j].Metadata.EventAt)
+		})
+		dst[date] = existing
+	}
+
+	return dst
+}
+
+func (dfs *DataFileStore) Upsert(ctx context.Context, input audit.DataAudit) (err error) {
+	_, span := telemetry.Start(ctx, "store.file.upsert", attribute.String("audit.key", input.Metadata.Key))
+	defer func() { telemetry.End(span, err) }()
+	defer prometheus.NewTimer(metrics.FileStoreUpsertDuration).ObserveDuration()
+
 	key := Key(input.Metadata.Key)
 	mu := dfs.mu.GetOrCreate(string(key))
 	mu.Lock()
@@ -77,18 +277,26 @@ func (dfs *DataFileStore) Upsert(ctx context.Context, input audit.DataAudit) err
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	err = os.WriteFile(NewFilePath(key).String(), payload, 0644)
+	if dfs.quota != nil {
+		var spilled bool
+		if payload, spilled, err = dfs.fit(ctx, key, fileData, input, payload); err != nil || spilled {
+			return err
+		}
+	}
+
+	err = os.WriteFile(dfs.path(key), payload, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write data: %w", err)
 	}
+	metrics.FileStoreFileBytes.Observe(float64(len(payload)))
+	dfs.track(key, fileData, int64(len(payload)))
 
 	return nil
 }
 
 // getInternal reads data without acquiring lock (for internal use when lock is already held)
 func (dfs *DataFileStore) getInternal(key Key) (Data, error) {
-	filePath := NewFilePath(key)
-	file, err := os.OpenFile(filePath.String(), os.O_RDWR|os.O_CREATE, 0644)
+	file, err := os.OpenFile(dfs.path(key), os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
 		return Data{}, err
 	}
@@ -112,8 +320,7 @@ func (dfs *DataFileStore) Get(ctx context.Context, key Key) (Data, error) {
 	mu.RLock()
 	defer mu.RUnlock()
 
-	filePath := NewFilePath(key)
-	file, err := os.OpenFile(filePath.String(), os.O_RDWR|os.O_CREATE, 0644)
+	file, err := os.OpenFile(dfs.path(key), os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
 		return Data{}, err
 	}
@@ -133,15 +340,13 @@ func (dfs *DataFileStore) Get(ctx context.Context, key Key) (Data, error) {
 }
 
 func (dfs *DataFileStore) GetAll(ctx context.Context) (map[string]Data, error) {
-	output := make(map[string]Data)
-	files, err := os.ReadDir("tmp")
+	keys, err := dfs.Keys(ctx)
 	if err != nil {
 		return map[string]Data{}, err
 	}
 
-	for _, file := range files {
-		// Strip .json extension to get the key
-		key := strings.TrimSuffix(file.Name(), ".json")
+	output := make(map[string]Data, len(keys))
+	for _, key := range keys {
 		data, err := dfs.Get(ctx, Key(key))
 		if err != nil {
 			return map[string]Data{}, err
@@ -152,37 +357,142 @@ func (dfs *DataFileStore) GetAll(ctx context.Context) (map[string]Data, error) {
 	return output, nil
 }
 
-func (dfs *DataFileStore) DeleteAfterDay(ctx context.Context, timeNow time.Time) error {
-	files, err := os.ReadDir("tmp")
+// Keys lists the keys with a file in the data directory; tenant directories
+// are not walked.
+func (dfs *DataFileStore) Keys(ctx context.Context) ([]string, error) {
+	files, err := os.ReadDir(dfs.dir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, dirEntry := range files {
-		filePath := fmt.Sprintf("tmp/%s", dirEntry.Name())
-		file, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
+	keys := make([]string, 0, len(files))
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".json")
+		if file.IsDir() || !ok {
+			continue
+		}
+		key, err := DecodeKey(name)
 		if err != nil {
-			return err
+			continue
 		}
+		keys = append(keys, string(key))
+	}
 
-		var data Data
-		err = json.NewDecoder(file).Decode(&data)
-		file.Close()
-		if err != nil {
-			// If file is empty, skip it
-			if errors.Is(err, io.EOF) {
-				continue
-			}
-			return err
+	return keys, nil
+}
+
+// Take removes the file of key and returns what it held, so a write racing
+// with a handoff either lands before it and moves along or creates a new file.
+func (dfs *DataFileStore) Take(ctx context.Context, key Key) (Data, error) {
+	mu := dfs.mu.GetOrCreate(string(key))
+	mu.Lock()
+	defer mu.Unlock()
+
+	data, err := dfs.getInternal(key)
+	if err != nil {
+		return Data{}, fmt.Errorf("failed to get data: %w", err)
+	}
+	if err := dfs.removeInternal(key); err != nil {
+		return Data{}, err
+	}
+
+	return data, nil
+}
+
+// Merge adds the events of data to the file of key, keeping each day sorted
+// by event at desc. Events already present are skipped, so a handoff retried
+// after a lost response does not duplicate them.
+func (dfs *DataFileStore) Merge(ctx context.Context, key Key, data Data) error {
+	mu := dfs.mu.GetOrCreate(string(key))
+	mu.Lock()
+	defer mu.Unlock()
+
+	fileData, err := dfs.getInternal(key)
+	if err != nil {
+		return fmt.Errorf("failed to get data: %w", err)
+	}
+
+	return dfs.writeInternal(key, MergeData(fileData, data))
+}
+
+// Remove deletes the events of sealed from the file of key, and the file once
+// it is empty. Events that arrived after sealed was read are kept. With the
+// spill quota policy the freed room takes spilled audits back.
+func (dfs *DataFileStore) Remove(ctx context.Context, key Key, sealed Data) error {
+	if err := dfs.removeSealed(key, sealed); err != nil {
+		return err
+	}
+
+	if _, err := dfs.DrainSpill(ctx); err != nil {
+		logger.ErrorContext(ctx, "failed to drain spilled audits", "error", err)
+	}
+	return nil
+}
+
+func (dfs *DataFileStore) removeSealed(key Key, sealed Data) error {
+	mu := dfs.mu.GetOrCreate(string(key))
+	mu.Lock()
+	defer mu.Unlock()
+
+	fileData, err := dfs.getInternal(key)
+	if err != nil {
+		return fmt.Errorf("failed to get data: %w", err)
+	}
+
+	for date, events := range sealed {
+		remove := make(map[string]bool, len(events))
+		for _, event := range events {
+			remove[eventID(event)] = true
 		}
 
-		if data[NewDate(timeNow)] == nil {
-			err = os.Remove(filePath)
-			if err != nil {
-				return err
+		kept := fileData[date][:0]
+		for _, event := range fileData[date] {
+			if !remove[eventID(event)] {
+				kept = append(kept, event)
 			}
 		}
+		if len(kept) == 0 {
+			delete(fileData, date)
+		} else {
+			fileData[date] = kept
+		}
 	}
 
+	if len(fileData) == 0 {
+		return dfs.removeInternal(key)
+	}
+	return dfs.writeInternal(key, fileData)
+}
+
+// removeInternal deletes the file of key without acquiring lock (for internal use when lock is already held)
+func (dfs *DataFileStore) removeInternal(key Key) error {
+	if err := os.Remove(dfs.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove data: %w", err)
+	}
+	dfs.track(key, nil, 0)
+	return nil
+}
+
+// writeInternal rewrites the file of key without acquiring lock (for internal use when lock is already held)
+func (dfs *DataFileStore) writeInternal(key Key, fileData Data) error {
+	payload, err := json.Marshal(fileData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	if err := os.WriteFile(dfs.path(key), payload, 0644); err != nil {
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+	dfs.track(key, fileData, int64(len(payload)))
+
 	return nil
 }
+
+// eventID is the ID stamped at ingestion. Events stored before ids existed
+// fall back to their idempotency key plus their time.
+func eventID(event audit.DataAudit) string {
+	if event.Metadata.ID != "" {
+		return event.Metadata.ID
+	}
+	return fmt.Sprintf("%s-%s-%s-%s-%d", event.Metadata.Key, event.Metadata.EventName, event.Metadata.RequestID, event.Metadata.CorrelationID, event.Metadata.EventAt.UnixNano())
+}
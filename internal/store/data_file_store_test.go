@@ -3,7 +3,9 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -37,7 +39,12 @@ func TestNewFilePath(t *testing.T) {
 		{
 			name:     "success - key with colon",
 			key:      Key("user:123"),
-			expected: "tmp/user:123.json",
+			expected: "tmp/user%3A123.json",
+		},
+		{
+			name:     "success - key with slashes stays in the directory",
+			key:      Key("../../etc/x"),
+			expected: "tmp/..%2F..%2Fetc%2Fx.json",
 		},
 		{
 			name:     "success - empty key",
@@ -48,7 +55,7 @@ func TestNewFilePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := NewFilePath(tt.key)
+			result := NewFilePath("tmp", tt.key)
 			if result.String() != tt.expected {
 				t.Errorf("expected %q, got %q", tt.expected, result.String())
 			}
@@ -77,6 +84,11 @@ func TestNewDate(t *testing.T) {
 			time:     time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
 			expected: Date("2025-1-5"),
 		},
+		{
+			name:     "success - local time keeps the UTC day",
+			time:     time.Date(2025, 1, 15, 22, 0, 0, 0, time.FixedZone("BRT", -3*60*60)),
+			expected: Date("2025-1-16"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -270,6 +282,27 @@ func TestDataFileStore_GetAll(t *testing.T) {
 			expectedLen:   0,
 			expectedError: false,
 		},
+		{
+			name: "success - get all skips tenant directories and other files",
+			setupFiles: func(t *testing.T) {
+				files := map[string]string{
+					"tmp/user%3A1.json":              `{"2025-1-15":[]}`,
+					"tmp/user:2.json":                `{"2025-1-15":[]}`,
+					"tmp/notes.txt":                  "notes",
+					"tmp/tenants/acme/user%3A3.json": `{"2025-1-15":[]}`,
+				}
+				for path, content := range files {
+					if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+						t.Fatalf("failed to create directory: %v", err)
+					}
+					if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+						t.Fatalf("failed to write file: %v", err)
+					}
+				}
+			},
+			expectedLen:   2,
+			expectedError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -298,6 +331,154 @@ func TestDataFileStore_GetAll(t *testing.T) {
 	}
 }
 
-// NOTE: TestDataFileStore_GetAll with multiple files is skipped because there's a bug
-// in GetAll: it passes file.Name() (e.g., "user123.json") to Get(), which then calls
-// NewFilePath() adding ".json" again, resulting in "tmp/user123.json.json"
+func TestDataFileStore_TakeAndMerge(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	fixedTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	clock.SetNow(fixedTime)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	ctx := context.Background()
+	dfs := NewDataFileStore()
+	first := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.created", RequestID: "req-1", EventAt: fixedTime}}
+	second := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.updated", RequestID: "req-2", EventAt: fixedTime.Add(time.Minute)}}
+
+	if err := dfs.Upsert(ctx, first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys, err := dfs.Keys(ctx)
+	if err != nil || len(keys) != 1 || keys[0] != "user:123" {
+		t.Fatalf("expected key user:123, got %v %v", keys, err)
+	}
+
+	taken, err := dfs.Take(ctx, "user:123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(taken[NewDate(fixedTime)]) != 1 {
+		t.Fatalf("expected one event taken, got %v", taken)
+	}
+	if _, err := os.Stat(NewFilePath("tmp", "user:123").String()); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be removed, got %v", err)
+	}
+
+	// merging the taken data twice with a new event keeps each event once
+	if err := dfs.Upsert(ctx, second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 2 {
+		if err := dfs.Merge(ctx, "user:123", taken); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	merged, err := dfs.Get(ctx, "user:123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := merged[NewDate(fixedTime)]
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Metadata.EventName != "user.updated" || events[1].Metadata.EventName != "user.created" {
+		t.Errorf("expected events sorted by event at desc, got %v", events)
+	}
+}
+
+func TestDataFileStore_Remove(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	yesterday := time.Date(2025, 1, 14, 10, 0, 0, 0, time.UTC)
+	today := yesterday.Add(24 * time.Hour)
+
+	ctx := context.Background()
+	dfs := NewDataFileStore()
+	sealed := audit.DataAudit{Metadata: audit.MetadataAudit{ID: "01-sealed", Key: "user:123", EventName: "user.created", RequestID: "req-1", EventAt: yesterday}}
+	late := audit.DataAudit{Metadata: audit.MetadataAudit{ID: "02-late", Key: "user:123", EventName: "user.updated", RequestID: "req-2", EventAt: yesterday.Add(time.Hour)}}
+	current := audit.DataAudit{Metadata: audit.MetadataAudit{ID: "03-current", Key: "user:123", EventName: "user.deleted", RequestID: "req-3", EventAt: today}}
+	// the same request sent again, without an event at, is another event
+	twin := sealed
+	twin.Metadata.ID = "04-twin"
+
+	if err := dfs.Merge(ctx, "user:123", Data{NewDate(yesterday): {sealed}, NewDate(today): {current}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// an event of the sealed day arriving after it was read
+	if err := dfs.Merge(ctx, "user:123", Data{NewDate(yesterday): {late, twin}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := dfs.Remove(ctx, "user:123", Data{NewDate(yesterday): {sealed}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := dfs.Get(ctx, "user:123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := data[NewDate(yesterday)]; len(got) != 2 || got[0].Metadata.ID != "02-late" || got[1].Metadata.ID != "04-twin" {
+		t.Errorf("expected only the late events of the sealed day, got %v", got)
+	}
+	if got := data[NewDate(today)]; len(got) != 1 {
+		t.Errorf("expected today's event to be kept, got %v", got)
+	}
+
+	if err := dfs.Remove(ctx, "user:123", data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(NewFilePath("tmp", "user:123").String()); !os.IsNotExist(err) {
+		t.Errorf("expected file to be removed once empty, got %v", err)
+	}
+}
+
+func TestDecodeKey(t *testing.T) {
+	for _, key := range []Key{"user:123", "../../etc/x", "tenant/user 1", "ação:1", "100%"} {
+		decoded, err := DecodeKey(EncodeKey(key))
+		if err != nil || decoded != key {
+			t.Errorf("expected %q back, got %q, %v", key, decoded, err)
+		}
+	}
+}
+
+func TestDataFileStore_WithDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "user:123.json"), []byte(`{"2025-1-15":[{"metadata":{"key":"user:123","request_id":"req-1"}}]}`), 0644); err != nil {
+		t.Fatalf("failed to write legacy file: %v", err)
+	}
+
+	dfs, err := NewDataFileStore().WithDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "user:123.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the legacy file to be renamed, got %v", err)
+	}
+	data, err := dfs.Get(context.Background(), "user:123")
+	if err != nil || len(data["2025-1-15"]) != 1 {
+		t.Errorf("expected the legacy events under the encoded name, got %+v, %v", data, err)
+	}
+
+	tenantStore, err := dfs.ForTenant("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenantStore.Dir() != filepath.Join(dir, "tenants", "acme") {
+		t.Errorf("unexpected tenant directory %s", tenantStore.Dir())
+	}
+	keys, _ := tenantStore.Keys(context.Background())
+	if len(keys) != 0 {
+		t.Errorf("expected the tenant store not to see the shared keys, got %v", keys)
+	}
+
+	for _, tenant := range []string{"", "..", "acme/../x", "a:b"} {
+		if _, err := dfs.ForTenant(tenant); !errors.Is(err, ErrInvalidTenant) {
+			t.Errorf("expected ErrInvalidTenant for %q, got %v", tenant, err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const keySize = 32 // AES-256
+
+var (
+	ErrKeyNotFound  = errors.New("encryption key not found")
+	ErrKeyDestroyed = errors.New("encryption key destroyed")
+)
+
+// FileKeyStore keeps one data encryption key per Metadata.Key on local disk.
+// Destroying a key leaves a tombstone so the subject's previous ciphertext can
+// never be decrypted again, even if new events arrive for the same key.
+type FileKeyStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewFileKeyStore(dir string) (*FileKeyStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keys directory: %w", err)
+	}
+
+	fks := &FileKeyStore{dir: dir}
+	if err := fks.migrateNames(); err != nil {
+		return nil, err
+	}
+	return fks, nil
+}
+
+// keyPath and tombstonePath name the files after EncodeKey, so a key like
+// "../x" cannot leave the directory.
+func (fks *FileKeyStore) keyPath(key string) string {
+	return filepath.Join(fks.dir, fmt.Sprintf("%s.key", EncodeKey(Key(key))))
+}
+
+func (fks *FileKeyStore) tombstonePath(key string) string {
+	return filepath.Join(fks.dir, fmt.Sprintf("%s.destroyed", EncodeKey(Key(key))))
+}
+
+// migrateNames renames the keys and tombstones written before keys were
+// encoded, e.g. "user:123.key"; an encoded file already there wins.
+func (fks *FileKeyStore) migrateNames() error {
+	files, err := os.ReadDir(fks.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list keys directory: %w", err)
+	}
+
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		name := strings.TrimSuffix(file.Name(), ext)
+		if file.IsDir() || (ext != ".key" && ext != ".destroyed") {
+			continue
+		}
+		key, err := DecodeKey(name)
+		if err != nil || EncodeKey(key) == name {
+			continue
+		}
+
+		legacy := filepath.Join(fks.dir, file.Name())
+		target := filepath.Join(fks.dir, EncodeKey(key)+ext)
+		if _, err := os.Stat(target); err == nil {
+			err = os.Remove(legacy)
+		} else {
+			err = os.Rename(legacy, target)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", legacy, err)
+		}
+	}
+
+	return nil
+}
+
+func (fks *FileKeyStore) Get(key string) ([]byte, error) {
+	fks.mu.Lock()
+	defer fks.mu.Unlock()
+
+	return fks.getInternal(key)
+}
+
+// getInternal reads the key without acquiring lock (for internal use when lock is already held)
+func (fks *FileKeyStore) getInternal(key string) ([]byte, error) {
+	dek, err := os.ReadFile(fks.keyPath(key))
+	if err == nil {
+		return dek, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+
+	if _, err := os.Stat(fks.tombstonePath(key)); err == nil {
+		return nil, ErrKeyDestroyed
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// GetOrCreate returns the active key for the subject, generating a new one when
+// none exists or the previous one was destroyed.
+func (fks *FileKeyStore) GetOrCreate(key string) ([]byte, error) {
+	fks.mu.Lock()
+	defer fks.mu.Unlock()
+
+	dek, err := fks.getInternal(key)
+	if err == nil {
+		return dek, nil
+	}
+
+	if !errors.Is(err, ErrKeyNotFound) && !errors.Is(err, ErrKeyDestroyed) {
+		return nil, err
+	}
+
+	dek = make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	if err := os.WriteFile(fks.keyPath(key), dek, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write key: %w", err)
+	}
+
+	return dek, nil
+}
+
+func (fks *FileKeyStore) Destroy(key string) error {
+	fks.mu.Lock()
+	defer fks.mu.Unlock()
+
+	if _, err := fks.getInternal(key); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(fks.tombstonePath(key), nil, 0600); err != nil {
+		return fmt.Errorf("failed to write tombstone: %w", err)
+	}
+
+	if err := os.Remove(fks.keyPath(key)); err != nil {
+		return fmt.Errorf("failed to remove key: %w", err)
+	}
+
+	return nil
+}
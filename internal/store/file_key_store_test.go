@@ -0,0 +1,142 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileKeyStore_GetOrCreate(t *testing.T) {
+	fks, err := NewFileKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create key store: %v", err)
+	}
+
+	first, err := fks.GetOrCreate("user:123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(first) != keySize {
+		t.Errorf("expected key size %d, got %d", keySize, len(first))
+	}
+
+	second, err := fks.GetOrCreate("user:123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected same key on second call")
+	}
+}
+
+func TestFileKeyStore_Destroy(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T, fks *FileKeyStore)
+		expectedError error
+	}{
+		{
+			name: "success - destroy existing key",
+			setup: func(t *testing.T, fks *FileKeyStore) {
+				if _, err := fks.GetOrCreate("user:123"); err != nil {
+					t.Fatalf("failed to create key: %v", err)
+				}
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "error - key not found",
+			setup:         func(t *testing.T, fks *FileKeyStore) {},
+			expectedError: ErrKeyNotFound,
+		},
+		{
+			name: "error - key already destroyed",
+			setup: func(t *testing.T, fks *FileKeyStore) {
+				if _, err := fks.GetOrCreate("user:123"); err != nil {
+					t.Fatalf("failed to create key: %v", err)
+				}
+				if err := fks.Destroy("user:123"); err != nil {
+					t.Fatalf("failed to destroy key: %v", err)
+				}
+			},
+			expectedError: ErrKeyDestroyed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fks, err := NewFileKeyStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create key store: %v", err)
+			}
+			tt.setup(t, fks)
+
+			err = fks.Destroy("user:123")
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+
+			if _, err := fks.Get("user:123"); !errors.Is(err, ErrKeyDestroyed) && !errors.Is(err, ErrKeyNotFound) {
+				t.Errorf("expected key to be unavailable, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFileKeyStore_GetOrCreateAfterDestroy(t *testing.T) {
+	fks, err := NewFileKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create key store: %v", err)
+	}
+
+	old, _ := fks.GetOrCreate("user:123")
+	if err := fks.Destroy("user:123"); err != nil {
+		t.Fatalf("failed to destroy key: %v", err)
+	}
+
+	renewed, err := fks.GetOrCreate("user:123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if bytes.Equal(old, renewed) {
+		t.Errorf("expected a new key after destroy")
+	}
+}
+
+func TestFileKeyStore_EncodedNames(t *testing.T) {
+	dir := t.TempDir()
+	legacy := []byte("0123456789abcdef0123456789abcdef")
+	if err := os.WriteFile(filepath.Join(dir, "user:123.key"), legacy, 0600); err != nil {
+		t.Fatalf("failed to write legacy key: %v", err)
+	}
+
+	fks, err := NewFileKeyStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create key store: %v", err)
+	}
+
+	dek, err := fks.Get("user:123")
+	if err != nil || !bytes.Equal(dek, legacy) {
+		t.Errorf("expected the legacy key under its encoded name, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "user%3A123.key")); err != nil {
+		t.Errorf("expected an encoded file name, got %v", err)
+	}
+
+	for _, key := range []string{"../../escape", "tenant/user:1"} {
+		if _, err := fks.GetOrCreate(key); err != nil {
+			t.Fatalf("unexpected error for %q: %v", key, err)
+		}
+		if err := fks.Destroy(key); err != nil {
+			t.Fatalf("unexpected error for %q: %v", key, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "..", "escape.destroyed")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no file outside the keys directory, got %v", err)
+	}
+}
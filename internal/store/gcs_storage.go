@@ -0,0 +1,149 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultGCSEndpoint = "https://storage.googleapis.com"
+
+type GCSConfig struct {
+	Bucket   string
+	Endpoint string // Optional: for fake-gcs-server
+	Token    string // OAuth2 bearer token, empty for emulators
+}
+
+// GCSStorage talks to the GCS JSON API, which is also served by emulators such
+// as fake-gcs-server. Per object expiration is not part of the media upload,
+// so retention is expected to come from bucket lifecycle rules.
+type GCSStorage struct {
+	bucket   string
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+func NewGCSStorage(gcsCfg GCSConfig, client *http.Client) *GCSStorage {
+	endpoint := gcsCfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGCSEndpoint
+	}
+
+	return &GCSStorage{
+		bucket:   gcsCfg.Bucket,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    gcsCfg.Token,
+		client:   client,
+	}
+}
+
+func (gs *GCSStorage) do(req *http.Request) (*http.Response, error) {
+	if gs.token != "" {
+		req.Header.Set("Authorization", "Bearer "+gs.token)
+	}
+	return gs.client.Do(req)
+}
+
+func (gs *GCSStorage) Put(ctx context.Context, path string, data []byte, expires time.Time) error {
+	query := url.Values{"uploadType": {"media"}, "name": {path}}
+	endpoint := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", gs.endpoint, url.PathEscape(gs.bucket), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create GCS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := gs.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to GCS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload to GCS: status %d: %s", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+func (gs *GCSStorage) Get(ctx context.Context, path string) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", gs.endpoint, url.PathEscape(gs.bucket), url.PathEscape(path))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS request: %w", err)
+	}
+
+	resp, err := gs.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download from GCS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to download from GCS: status %d: %s", resp.StatusCode, body)
+	}
+}
+
+type gcsListResponse struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func (gs *GCSStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+	pageToken := ""
+	for {
+		query := url.Values{"prefix": {prefix}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		endpoint := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", gs.endpoint, url.PathEscape(gs.bucket), query.Encode())
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCS request: %w", err)
+		}
+
+		resp, err := gs.do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GCS objects: %w", err)
+		}
+
+		var page gcsListResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to list GCS objects: status %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode GCS list: %w", err)
+		}
+
+		for _, item := range page.Items {
+			paths = append(paths, item.Name)
+		}
+
+		if page.NextPageToken == "" {
+			return paths, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
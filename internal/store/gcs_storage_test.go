@@ -0,0 +1,86 @@
+package store
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newFakeGCSServer serves the subset of the JSON API used by GCSStorage, the
+// same way fake-gcs-server does.
+func newFakeGCSServer(t *testing.T, bucket string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /upload/storage/v1/b/{bucket}/o", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("bucket") != bucket || r.URL.Query().Get("uploadType") != "media" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		objects[r.URL.Query().Get("name")] = body
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{"name": r.URL.Query().Get("name")})
+	})
+	mux.HandleFunc("GET /storage/v1/b/{bucket}/o/{object...}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		data, ok := objects[r.PathValue("object")]
+		mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("GET /storage/v1/b/{bucket}/o", func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get("prefix")
+		var names []string
+		mu.Lock()
+		for name := range objects {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+		mu.Unlock()
+		sort.Strings(names)
+
+		// one item per page to exercise pagination
+		page := gcsListResponse{}
+		start := 0
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			start = sort.SearchStrings(names, token)
+		}
+		if start < len(names) {
+			page.Items = append(page.Items, struct {
+				Name string `json:"name"`
+			}{Name: names[start]})
+		}
+		if start+1 < len(names) {
+			page.NextPageToken = names[start+1]
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGCSStorage(t *testing.T) {
+	server := newFakeGCSServer(t, "auditory-bucket")
+
+	storage := NewGCSStorage(GCSConfig{
+		Bucket:   "auditory-bucket",
+		Endpoint: server.URL,
+	}, server.Client())
+
+	testObjectStorage(t, storage)
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LocalDirStorage archives objects into a directory tree, typically a mounted
+// volume on on-prem installations. Expiration is not enforced here; retention
+// should be handled by the volume's own housekeeping.
+type LocalDirStorage struct {
+	root string
+}
+
+func NewLocalDirStorage(root string) (*LocalDirStorage, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	return &LocalDirStorage{root: root}, nil
+}
+
+// fullPath is path under root; paths that would resolve outside it, such as
+// "../x" or "/etc/x", are refused.
+func (lds *LocalDirStorage) fullPath(path string) (string, error) {
+	local := filepath.FromSlash(path)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, path)
+	}
+	return filepath.Join(lds.root, local), nil
+}
+
+func (lds *LocalDirStorage) Put(ctx context.Context, path string, data []byte, expires time.Time) error {
+	fullPath, err := lds.fullPath(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	// write to a temporary file first so readers never see a partial object
+	tmpPath := fullPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	return nil
+}
+
+func (lds *LocalDirStorage) Get(ctx context.Context, path string) ([]byte, error) {
+	fullPath, err := lds.fullPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to read archive file: %w", err)
+	}
+
+	return data, nil
+}
+
+func (lds *LocalDirStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(lds.root, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(fullPath, ".tmp") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(lds.root, fullPath)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.ToSlash(rel)
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive directory: %w", err)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
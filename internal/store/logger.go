@@ -0,0 +1,5 @@
+package store
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("store")
@@ -33,6 +33,13 @@ func (mi *MemIdempotency) Get(key string) (time.Time, bool) {
 	return ttl, ok
 }
 
+// Len returns the number of keys currently held.
+func (mi *MemIdempotency) Len() int {
+	mi.mu.RLock()
+	defer mi.mu.RUnlock()
+	return len(mi.store)
+}
+
 // reset expired keys every ttl duration
 func (mi *MemIdempotency) Reset() {
 	mi.mu.Lock()
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: backpressure_store.go
+//
+// Generated by this command:
+//
+//	mockgen -source=backpressure_store.go -destination=mocks/mock_backpressure_store.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockAuditStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockAuditStoreMockRecorder) Upsert(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockAuditStore)(nil).Upsert), ctx, input)
+}
+
+// MockPressureGauge is a mock of PressureGauge interface.
+type MockPressureGauge struct {
+	ctrl     *gomock.Controller
+	recorder *MockPressureGaugeMockRecorder
+	isgomock struct{}
+}
+
+// MockPressureGaugeMockRecorder is the mock recorder for MockPressureGauge.
+type MockPressureGaugeMockRecorder struct {
+	mock *MockPressureGauge
+}
+
+// NewMockPressureGauge creates a new mock instance.
+func NewMockPressureGauge(ctrl *gomock.Controller) *MockPressureGauge {
+	mock := &MockPressureGauge{ctrl: ctrl}
+	mock.recorder = &MockPressureGaugeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPressureGauge) EXPECT() *MockPressureGaugeMockRecorder {
+	return m.recorder
+}
+
+// Pressure mocks base method.
+func (m *MockPressureGauge) Pressure() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pressure")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pressure indicates an expected call of Pressure.
+func (mr *MockPressureGaugeMockRecorder) Pressure() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pressure", reflect.TypeOf((*MockPressureGauge)(nil).Pressure))
+}
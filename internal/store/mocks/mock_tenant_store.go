@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tenant_store.go
+//
+// Generated by this command:
+//
+//	mockgen -source=tenant_store.go -destination=mocks/mock_tenant_store.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTenantAuditStore is a mock of TenantAuditStore interface.
+type MockTenantAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantAuditStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTenantAuditStoreMockRecorder is the mock recorder for MockTenantAuditStore.
+type MockTenantAuditStoreMockRecorder struct {
+	mock *MockTenantAuditStore
+}
+
+// NewMockTenantAuditStore creates a new mock instance.
+func NewMockTenantAuditStore(ctrl *gomock.Controller) *MockTenantAuditStore {
+	mock := &MockTenantAuditStore{ctrl: ctrl}
+	mock.recorder = &MockTenantAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantAuditStore) EXPECT() *MockTenantAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// Pressure mocks base method.
+func (m *MockTenantAuditStore) Pressure() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pressure")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pressure indicates an expected call of Pressure.
+func (mr *MockTenantAuditStoreMockRecorder) Pressure() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pressure", reflect.TypeOf((*MockTenantAuditStore)(nil).Pressure))
+}
+
+// Upsert mocks base method.
+func (m *MockTenantAuditStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockTenantAuditStoreMockRecorder) Upsert(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockTenantAuditStore)(nil).Upsert), ctx, input)
+}
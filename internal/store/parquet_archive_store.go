@@ -0,0 +1,291 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/parquet-go/parquet-go"
+)
+
+// ParquetRow is the columnar schema of exported audits. Columns are only ever
+// appended so existing queries keep working; http_* columns are null for
+// events that are not data plane exchanges.
+type ParquetRow struct {
+	Key           string    `parquet:"key,dict"`
+	EventName     string    `parquet:"event_name,dict"`
+	RequestID     string    `parquet:"request_id"`
+	CorrelationID string    `parquet:"correlation_id"`
+	EventAt       time.Time `parquet:"event_at,timestamp(microsecond)"`
+	EventDate     string    `parquet:"event_date,dict"`
+	Data          string    `parquet:"data,json"`
+
+	HttpMethod          *string `parquet:"http_method,optional,dict"`
+	HttpPath            *string `parquet:"http_path,optional"`
+	HttpQuery           *string `parquet:"http_query,optional"`
+	HttpRequestHeaders  *string `parquet:"http_request_headers,optional,json"`
+	HttpRequestBody     []byte  `parquet:"http_request_body,optional"`
+	HttpStatusCode      *int32  `parquet:"http_status_code,optional"`
+	HttpResponseHeaders *string `parquet:"http_response_headers,optional,json"`
+	HttpResponseBody    []byte  `parquet:"http_response_body,optional"`
+
+	TraceID *string `parquet:"trace_id,optional"`
+	SpanID  *string `parquet:"span_id,optional"`
+
+	EventID *string `parquet:"event_id,optional"`
+}
+
+// ParquetPath is the export of dataKey, encoded by pathSegment, for date.
+func ParquetPath(dataKey string, date time.Time) string {
+	return fmt.Sprintf("exports/parquet/key=%s/date=%s/audits.parquet", pathSegment(dataKey), date.Format(time.DateOnly))
+}
+
+// ParquetArchiveStore writes, for every key handed to Save, one Parquet file
+// per day found in the data, Hive partitioned by key and zero-padded date.
+// With keepJSON the regular JSON objects are written as well. Backups are
+// always JSON.
+type ParquetArchiveStore struct {
+	storage  ObjectStorage
+	archive  *ArchiveStore
+	keepJSON bool
+}
+
+func NewParquetArchiveStore(storage ObjectStorage, keepJSON bool) *ParquetArchiveStore {
+	return &ParquetArchiveStore{
+		storage:  storage,
+		archive:  NewArchiveStore(storage),
+		keepJSON: keepJSON,
+	}
+}
+
+// WithNode writes backups to NodeBackupPath, see ArchiveStore.WithNode.
+func (pas *ParquetArchiveStore) WithNode(node string) *ParquetArchiveStore {
+	pas.archive.WithNode(node)
+	return pas
+}
+
+// WithRetention sets the retention of snapshots, see ArchiveStore.WithRetention.
+func (pas *ParquetArchiveStore) WithRetention(storeDays int) *ParquetArchiveStore {
+	pas.archive.WithRetention(storeDays)
+	return pas
+}
+
+func (pas *ParquetArchiveStore) Backup(ctx context.Context, timeNow time.Time, data []byte) error {
+	return pas.archive.Backup(ctx, timeNow, data)
+}
+
+func (pas *ParquetArchiveStore) RecordManifest(ctx context.Context, day time.Time, objects []ArchivedObject) error {
+	return pas.archive.RecordManifest(ctx, day, objects)
+}
+
+// Save writes and verifies one Parquet file per day of data; rows already
+// archived in it by an earlier run are kept, as in ArchiveStore.Save.
+func (pas *ParquetArchiveStore) Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) ([]ArchivedObject, error) {
+	var objects []ArchivedObject
+	if pas.keepJSON {
+		jsonObjects, err := pas.archive.Save(ctx, dataKey, timeNow, data)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, jsonObjects...)
+	}
+
+	var fileData Data
+	if err := json.Unmarshal(data, &fileData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+
+	expires := pas.archive.storeExpires(timeNow)
+
+	for date, audits := range fileData {
+		day, err := date.Time()
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", date, err)
+		}
+		path := ParquetPath(dataKey, day)
+
+		rows, err := newParquetRows(date, audits)
+		if err != nil {
+			return nil, err
+		}
+
+		archived, err := pas.storage.Get(ctx, path)
+		switch {
+		case errors.Is(err, ErrObjectNotFound):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read archived %s: %w", path, err)
+		default:
+			archivedRows, err := parquet.Read[ParquetRow](bytes.NewReader(archived), int64(len(archived)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode archived %s: %w", path, err)
+			}
+			rows = mergeParquetRows(archivedRows, rows)
+		}
+
+		payload, err := encodeParquetRows(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		object, err := putVerified(ctx, pas.storage, path, payload, expires)
+		if err != nil {
+			return nil, err
+		}
+		object.Key = dataKey
+		object.Date = day.Format(time.DateOnly)
+		object.Records = len(rows)
+		if len(rows) > 0 {
+			// encodeParquetRows sorted them by event at
+			object.FirstEventAt, object.LastEventAt = rows[0].EventAt, rows[len(rows)-1].EventAt
+		}
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+// EncodeParquet converts the audits of every day of data to rows ordered by
+// event time.
+func EncodeParquet(data Data) ([]byte, error) {
+	var rows []ParquetRow
+	for date, audits := range data {
+		dateRows, err := newParquetRows(date, audits)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, dateRows...)
+	}
+	return encodeParquetRows(rows)
+}
+
+// newParquetRows converts the audits stored on date; date is their
+// event_date, since data plane exchanges carry no event at.
+func newParquetRows(date Date, audits []audit.DataAudit) ([]ParquetRow, error) {
+	day, err := date.Time()
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+
+	rows := make([]ParquetRow, 0, len(audits))
+	for _, input := range audits {
+		row, err := newParquetRow(day, input)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// mergeParquetRows adds the rows missing from archived, identified the way
+// MergeData identifies events.
+func mergeParquetRows(archived, rows []ParquetRow) []ParquetRow {
+	rowID := func(row ParquetRow) string {
+		if row.EventID != nil {
+			return *row.EventID
+		}
+		return fmt.Sprintf("%s-%s-%s-%s-%d", row.Key, row.EventName, row.RequestID, row.CorrelationID, row.EventAt.UnixNano())
+	}
+
+	seen := make(map[string]bool, len(archived))
+	for _, row := range archived {
+		seen[rowID(row)] = true
+	}
+	for _, row := range rows {
+		if id := rowID(row); !seen[id] {
+			seen[id] = true
+			archived = append(archived, row)
+		}
+	}
+	return archived
+}
+
+func encodeParquetRows(rows []ParquetRow) ([]byte, error) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].EventAt.Equal(rows[j].EventAt) {
+			return rows[i].EventAt.Before(rows[j].EventAt)
+		}
+		var left, right string
+		if rows[i].EventID != nil {
+			left = *rows[i].EventID
+		}
+		if rows[j].EventID != nil {
+			right = *rows[j].EventID
+		}
+		return left < right
+	})
+
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, rows, parquet.Compression(&parquet.Zstd)); err != nil {
+		return nil, fmt.Errorf("failed to write parquet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newParquetRow(day time.Time, input audit.DataAudit) (ParquetRow, error) {
+	data, err := json.Marshal(input.Data)
+	if err != nil {
+		return ParquetRow{}, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	// a zero time overflows the column, audits without event at take the
+	// time they were stored
+	eventAt := input.Metadata.EventAt.UTC()
+	if eventAt.IsZero() {
+		eventAt = day
+		if storedAt, ok := audit.IDTime(input.Metadata.ID); ok {
+			eventAt = storedAt
+		}
+	}
+	row := ParquetRow{
+		Key:           input.Metadata.Key,
+		EventName:     input.Metadata.EventName,
+		RequestID:     input.Metadata.RequestID,
+		CorrelationID: input.Metadata.CorrelationID,
+		EventAt:       eventAt,
+		EventDate:     day.Format(time.DateOnly),
+		Data:          string(data),
+	}
+	if input.Metadata.ID != "" {
+		row.EventID = stringPtr(input.Metadata.ID)
+	}
+	if input.Metadata.TraceID != "" {
+		row.TraceID = stringPtr(input.Metadata.TraceID)
+		row.SpanID = stringPtr(input.Metadata.SpanID)
+	}
+
+	if input.Metadata.EventName != audit.HttpAuditEvent {
+		return row, nil
+	}
+
+	var exchange audit.HttpAudit
+	if err := json.Unmarshal(data, &exchange); err != nil {
+		// encrypted or foreign payloads keep only the raw data column
+		return row, nil
+	}
+
+	requestHeaders, _ := json.Marshal(exchange.Request.Headers)
+	responseHeaders, _ := json.Marshal(exchange.Response.Headers)
+	statusCode := int32(exchange.Response.StatusCode)
+
+	row.HttpMethod = &exchange.Request.Method
+	row.HttpPath = &exchange.Request.Path
+	row.HttpQuery = &exchange.Request.Query
+	row.HttpRequestHeaders = stringPtr(string(requestHeaders))
+	row.HttpRequestBody = exchange.Request.Body
+	row.HttpStatusCode = &statusCode
+	row.HttpResponseHeaders = stringPtr(string(responseHeaders))
+	row.HttpResponseBody = exchange.Response.Body
+
+	return row, nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetArchiveStore_Save(t *testing.T) {
+	setupTestConfig()
+	ctx := context.Background()
+	timeNow := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	data := Data{
+		Date("2025-1-5"): []audit.DataAudit{
+			{
+				// data plane exchanges carry no event at, only the id stamped
+				// when they were stored at 00:30
+				Metadata: audit.MetadataAudit{ID: "01JGSXWRT00000000000000000", Key: "client-a", EventName: audit.HttpAuditEvent, RequestID: "req-2", CorrelationID: "corr-1"},
+				Data: audit.HttpAudit{
+					Request:  audit.RequestAudit{Method: "POST", Path: "/orders", Headers: map[string][]string{"X-Client-ID": {"client-a"}}, Body: []byte(`{"id":1}`)},
+					Response: audit.ResponseAudit{StatusCode: 201, Body: []byte(`ok`)},
+				},
+			},
+			{
+				Metadata: audit.MetadataAudit{Key: "client-a", EventName: "order.created", RequestID: "req-1", CorrelationID: "corr-1", EventAt: timeNow.Add(time.Hour)},
+				Data:     map[string]any{"id": 1},
+			},
+		},
+		Date("2025-1-4"): []audit.DataAudit{
+			{
+				Metadata: audit.MetadataAudit{Key: "client-a", EventName: "order.created", RequestID: "req-0", CorrelationID: "corr-0", EventAt: timeNow.Add(-time.Hour)},
+				Data:     map[string]any{"id": 0},
+			},
+		},
+	}
+	payload, _ := json.Marshal(data)
+
+	tests := []struct {
+		name          string
+		keepJSON      bool
+		expectedPaths []string
+	}{
+		{
+			name:     "success - parquet only",
+			keepJSON: false,
+			expectedPaths: []string{
+				"exports/parquet/key=client-a/date=2025-01-04/audits.parquet",
+				"exports/parquet/key=client-a/date=2025-01-05/audits.parquet",
+			},
+		},
+		{
+			name:     "success - parquet alongside json",
+			keepJSON: true,
+			expectedPaths: []string{
+				"audits/client-a/2025-01-05.json",
+				"exports/parquet/key=client-a/date=2025-01-04/audits.parquet",
+				"exports/parquet/key=client-a/date=2025-01-05/audits.parquet",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := NewLocalDirStorage(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create storage: %v", err)
+			}
+
+			pas := NewParquetArchiveStore(storage, tt.keepJSON)
+			objects, err := pas.Save(ctx, "client-a", timeNow, payload)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			paths, _ := storage.List(ctx, "")
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Errorf("expected paths %v, got %v", tt.expectedPaths, paths)
+			}
+
+			// every object written is reported for the manifest
+			var archivedPaths []string
+			for _, object := range objects {
+				archivedPaths = append(archivedPaths, object.Path)
+			}
+			sort.Strings(archivedPaths)
+			if !reflect.DeepEqual(archivedPaths, tt.expectedPaths) {
+				t.Errorf("expected archived objects %v, got %v", tt.expectedPaths, archivedPaths)
+			}
+
+			file, _ := storage.Get(ctx, "exports/parquet/key=client-a/date=2025-01-05/audits.parquet")
+			rows, err := parquet.Read[ParquetRow](bytes.NewReader(file), int64(len(file)))
+			if err != nil {
+				t.Fatalf("failed to read parquet: %v", err)
+			}
+
+			if len(rows) != 2 {
+				t.Fatalf("expected 2 rows, got %d", len(rows))
+			}
+
+			// rows are ordered by event time
+			if rows[1].RequestID != "req-1" || rows[1].HttpMethod != nil {
+				t.Errorf("expected plain audit last, got %+v", rows[1])
+			}
+
+			// event_date is the day the exchange was stored
+			httpRow := rows[0]
+			if httpRow.EventDate != "2025-01-05" || !httpRow.EventAt.Equal(timeNow.Add(30*time.Minute)) {
+				t.Errorf("unexpected event time columns: %s %v", httpRow.EventDate, httpRow.EventAt)
+			}
+			if httpRow.EventID == nil || *httpRow.EventID != "01JGSXWRT00000000000000000" {
+				t.Errorf("expected the event id column, got %v", httpRow.EventID)
+			}
+			if httpRow.HttpMethod == nil || *httpRow.HttpMethod != "POST" {
+				t.Errorf("expected http_method POST, got %v", httpRow.HttpMethod)
+			}
+			if httpRow.HttpStatusCode == nil || *httpRow.HttpStatusCode != 201 {
+				t.Errorf("expected http_status_code 201, got %v", httpRow.HttpStatusCode)
+			}
+			if string(httpRow.HttpRequestBody) != `{"id":1}` {
+				t.Errorf("expected request body, got %s", httpRow.HttpRequestBody)
+			}
+		})
+	}
+}
+
+func TestParquetArchiveStore_SaveInvalidData(t *testing.T) {
+	setupTestConfig()
+	storage, _ := NewLocalDirStorage(t.TempDir())
+
+	_, err := NewParquetArchiveStore(storage, false).Save(context.Background(), "client-a", time.Now(), []byte(`not json`))
+	if err == nil {
+		t.Errorf("expected error for invalid data")
+	}
+
+	if _, err := storage.Get(context.Background(), "audits/client-a"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected nothing written, got %v", err)
+	}
+}
@@ -0,0 +1,378 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"github.com/IsaacDSC/auditory/pkg/mu"
+)
+
+// Archive layouts: daily writes one object per key and day, hourly appends
+// parts to Hive partitions by key, date and hour.
+const (
+	LayoutDaily  = "daily"
+	LayoutHourly = "hourly"
+)
+
+// PartitionPrefix is the Hive partition of dataKey, encoded by pathSegment, for
+// the UTC hour of timeNow, e.g. "audits/key=user%3A123/date=2025-01-15/hour=09/".
+func PartitionPrefix(dataKey string, timeNow time.Time) string {
+	timeNow = timeNow.UTC()
+	return fmt.Sprintf("audits/key=%s/date=%s/hour=%02d/", pathSegment(dataKey), timeNow.Format(time.DateOnly), timeNow.Hour())
+}
+
+func PartPath(dataKey string, timeNow time.Time, part int) string {
+	return fmt.Sprintf("%spart-%d.jsonl", PartitionPrefix(dataKey, timeNow), part)
+}
+
+// PartitionedArchiveStore archives each key incrementally: every run appends
+// a part-N.jsonl object, one event per line, to the partition of each UTC
+// hour the events were stored in, with the events not archived there yet. A
+// busy key is spread over small objects and analytics engines can prune by
+// key, date and hour. Backups and manifests are those of ArchiveStore.
+type PartitionedArchiveStore struct {
+	storage ObjectStorage
+	archive *ArchiveStore
+
+	// indexes remembers the ids of the parts already read, so a run only
+	// downloads the parts written since the previous one
+	mu      sync.Mutex
+	keyMu   mu.MutexByKey
+	indexes map[string]*partitionIndex
+}
+
+type partitionIndex struct {
+	hour  time.Time
+	parts map[string]bool
+	seen  map[string]bool
+}
+
+func NewPartitionedArchiveStore(storage ObjectStorage) *PartitionedArchiveStore {
+	return &PartitionedArchiveStore{
+		storage: storage,
+		archive: NewArchiveStore(storage),
+		keyMu:   make(mu.MutexByKey),
+		indexes: make(map[string]*partitionIndex),
+	}
+}
+
+// WithNode writes backups to NodeBackupPath, see ArchiveStore.WithNode.
+func (pas *PartitionedArchiveStore) WithNode(node string) *PartitionedArchiveStore {
+	pas.archive.WithNode(node)
+	return pas
+}
+
+// WithRetention sets the retention of parts, see ArchiveStore.WithRetention.
+func (pas *PartitionedArchiveStore) WithRetention(storeDays int) *PartitionedArchiveStore {
+	pas.archive.WithRetention(storeDays)
+	return pas
+}
+
+func (pas *PartitionedArchiveStore) Backup(ctx context.Context, timeNow time.Time, data []byte) error {
+	return pas.archive.Backup(ctx, timeNow, data)
+}
+
+func (pas *PartitionedArchiveStore) RecordManifest(ctx context.Context, day time.Time, objects []ArchivedObject) error {
+	return pas.archive.RecordManifest(ctx, day, objects)
+}
+
+// Save writes and verifies one new part per hour of data. Events a previous
+// run already put in the partition are skipped, so a retry after a failed
+// local removal does not duplicate them; an hour with nothing new gets no
+// part.
+func (pas *PartitionedArchiveStore) Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) ([]ArchivedObject, error) {
+	var fileData Data
+	if err := json.Unmarshal(data, &fileData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+
+	pas.mu.Lock()
+	keyMu := pas.keyMu.GetOrCreate(dataKey)
+	pas.mu.Unlock()
+	keyMu.Lock()
+	defer keyMu.Unlock()
+
+	expires := pas.archive.storeExpires(timeNow)
+
+	byHour := make(map[time.Time][]audit.DataAudit)
+	for date, events := range fileData {
+		for _, event := range events {
+			hour := StoredHour(date, event)
+			byHour[hour] = append(byHour[hour], event)
+		}
+	}
+
+	hours := make([]time.Time, 0, len(byHour))
+	for hour := range byHour {
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Before(hours[j]) })
+
+	var objects []ArchivedObject
+	for _, hour := range hours {
+		object, written, err := pas.savePart(ctx, dataKey, hour, byHour[hour], expires)
+		if err != nil {
+			return nil, err
+		}
+		if written {
+			objects = append(objects, object)
+		}
+	}
+
+	pas.evict(clock.Now())
+	return objects, nil
+}
+
+// StoredHour is the UTC hour the event was stored in: the time of its id,
+// or, for events stored before ids, the hour of its event at within the
+// stored day and otherwise midnight.
+func StoredHour(date Date, event audit.DataAudit) time.Time {
+	day, _ := date.Time()
+	for _, at := range []time.Time{idTime(event), event.Metadata.EventAt.UTC()} {
+		if !at.IsZero() && NewDate(at) == date {
+			return at.Truncate(time.Hour)
+		}
+	}
+	return day
+}
+
+func idTime(event audit.DataAudit) time.Time {
+	at, _ := audit.IDTime(event.Metadata.ID)
+	return at
+}
+
+func (pas *PartitionedArchiveStore) savePart(ctx context.Context, dataKey string, hour time.Time, events []audit.DataAudit, expires time.Time) (ArchivedObject, bool, error) {
+	prefix := PartitionPrefix(dataKey, hour)
+	index, next, err := pas.index(ctx, prefix, hour)
+	if err != nil {
+		return ArchivedObject{}, false, err
+	}
+
+	var fresh []audit.DataAudit
+	for _, event := range events {
+		if id := eventID(event); !index.seen[id] {
+			index.seen[id] = true
+			fresh = append(fresh, event)
+		}
+	}
+	if len(fresh) == 0 {
+		return ArchivedObject{}, false, nil
+	}
+	sort.Slice(fresh, func(i, j int) bool {
+		return eventID(fresh[i]) < eventID(fresh[j])
+	})
+
+	payload, err := encodeLines(fresh)
+	if err != nil {
+		pas.forget(prefix)
+		return ArchivedObject{}, false, err
+	}
+
+	path := PartPath(dataKey, hour, next)
+	object, err := putVerified(ctx, pas.storage, path, payload, expires)
+	if err != nil {
+		// the part may or may not be there, the next run reads it again
+		pas.forget(prefix)
+		return ArchivedObject{}, false, err
+	}
+	index.parts[path] = true
+
+	first, last := fresh[0].Metadata.EventAt, fresh[0].Metadata.EventAt
+	for _, event := range fresh {
+		if eventAt := event.Metadata.EventAt; eventAt.Before(first) {
+			first = eventAt
+		} else if eventAt.After(last) {
+			last = eventAt
+		}
+	}
+	object.Key = dataKey
+	object.Date = hour.Format(time.DateOnly)
+	object.Records = len(fresh)
+	object.FirstEventAt, object.LastEventAt = first, last
+
+	return object, true, nil
+}
+
+// index lists the partition and reads the parts it has not read yet, which
+// after the first run of a partition are only those another node wrote. It
+// returns the number of the next part.
+func (pas *PartitionedArchiveStore) index(ctx context.Context, prefix string, hour time.Time) (*partitionIndex, int, error) {
+	paths, err := pas.storage.List(ctx, prefix)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+
+	pas.mu.Lock()
+	index, ok := pas.indexes[prefix]
+	if !ok {
+		index = &partitionIndex{hour: hour, parts: make(map[string]bool), seen: make(map[string]bool)}
+		pas.indexes[prefix] = index
+	}
+	pas.mu.Unlock()
+
+	next := 0
+	for _, path := range paths {
+		part, ok := partNumber(strings.TrimPrefix(path, prefix))
+		if !ok {
+			continue
+		}
+		next = max(next, part+1)
+		if index.parts[path] {
+			continue
+		}
+
+		payload, err := pas.storage.Get(ctx, path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read archived %s: %w", path, err)
+		}
+		archived, err := decodeLines(payload)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode archived %s: %w", path, err)
+		}
+		for _, event := range archived {
+			index.seen[eventID(event)] = true
+		}
+		index.parts[path] = true
+	}
+
+	return index, next, nil
+}
+
+func (pas *PartitionedArchiveStore) forget(prefix string) {
+	pas.mu.Lock()
+	defer pas.mu.Unlock()
+	delete(pas.indexes, prefix)
+}
+
+// evict drops the indexes of partitions older than a day: no new event is
+// stored in them, only a retry after a failed removal reads them again.
+func (pas *PartitionedArchiveStore) evict(timeNow time.Time) {
+	pas.mu.Lock()
+	defer pas.mu.Unlock()
+
+	for prefix, index := range pas.indexes {
+		if index.hour.Before(timeNow.Add(-24 * time.Hour)) {
+			delete(pas.indexes, prefix)
+		}
+	}
+}
+
+// partNumber parses "part-N.jsonl".
+func partNumber(name string) (int, bool) {
+	number, ok := strings.CutPrefix(name, "part-")
+	if !ok {
+		return 0, false
+	}
+	number, ok = strings.CutSuffix(number, ".jsonl")
+	if !ok {
+		return 0, false
+	}
+	part, err := strconv.Atoi(number)
+	return part, err == nil && part >= 0
+}
+
+func encodeLines(events []audit.DataAudit) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return nil, fmt.Errorf("failed to marshal event: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeLines(payload []byte) ([]audit.DataAudit, error) {
+	var events []audit.DataAudit
+	for line := range bytes.Lines(payload) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var event audit.DataAudit
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// ParseArchivePath returns the key and the day of an object Store archived,
+// in either layout: audits/{key}/{date}.json or
+// audits/key={key}/date={date}/hour={HH}/part-{N}.jsonl. Backups, manifests
+// and Parquet exports are not. Keys are decoded by DecodeKey; objects written
+// before keys were encoded, a key with "/" spanning several segments, are
+// read as well.
+func ParseArchivePath(path string) (key string, day time.Time, ok bool) {
+	rest, ok := strings.CutPrefix(path, "audits/")
+	if !ok || strings.HasPrefix(path, ManifestPrefix) {
+		return "", time.Time{}, false
+	}
+
+	if strings.HasPrefix(rest, "key=") {
+		parts := strings.Split(rest, "/")
+		if len(parts) < 4 {
+			return "", time.Time{}, false
+		}
+		last := len(parts) - 3
+		name, keyOK := strings.CutPrefix(strings.Join(parts[:last], "/"), "key=")
+		date, dateOK := strings.CutPrefix(parts[last], "date=")
+		_, hourOK := strings.CutPrefix(parts[last+1], "hour=")
+		_, partOK := partNumber(parts[last+2])
+		day, err := time.Parse(time.DateOnly, date)
+		if !keyOK || !dateOK || !hourOK || !partOK || err != nil {
+			return "", time.Time{}, false
+		}
+		return decodedKey(name, day)
+	}
+
+	slash := strings.LastIndex(rest, "/")
+	if slash <= 0 {
+		return "", time.Time{}, false
+	}
+	date, isJSON := strings.CutSuffix(rest[slash+1:], ".json")
+	if !isJSON {
+		return "", time.Time{}, false
+	}
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return decodedKey(rest[:slash], day)
+}
+
+func decodedKey(name string, day time.Time) (string, time.Time, bool) {
+	key, err := DecodeKey(name)
+	if err != nil || key == "" {
+		return "", time.Time{}, false
+	}
+	return string(key), day, true
+}
+
+// DecodeArchive reads an object ParseArchivePath accepted: daily objects are
+// already Data, the events of a part are filed under the day of its partition.
+func DecodeArchive(path string, day time.Time, payload []byte) (Data, error) {
+	if strings.HasSuffix(path, ".jsonl") {
+		events, err := decodeLines(payload)
+		if err != nil {
+			return nil, err
+		}
+		return Data{NewDate(day): events}, nil
+	}
+
+	var data Data
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,222 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+// countingStorage counts the objects read, verifications aside.
+type countingStorage struct {
+	ObjectStorage
+	gets int
+}
+
+func (cs *countingStorage) Get(ctx context.Context, path string) ([]byte, error) {
+	cs.gets++
+	return cs.ObjectStorage.Get(ctx, path)
+}
+
+func (cs *countingStorage) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	return StatObject(ctx, cs.ObjectStorage, path)
+}
+
+func TestPartitionedArchiveStore_Save(t *testing.T) {
+	setupTestConfig()
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	// events are partitioned by the hour they were stored in, the time of
+	// their id, not by the event at their client sent
+	event := func(requestID string, storedAt time.Time) audit.DataAudit {
+		clock.SetNow(storedAt)
+		return audit.DataAudit{Metadata: audit.MetadataAudit{ID: audit.NewID(), Key: "user:123", EventName: "user.created", RequestID: requestID, EventAt: storedAt}}
+	}
+	first := event("req-1", day.Add(9*time.Hour+10*time.Minute))
+	second := event("req-2", day.Add(9*time.Hour+40*time.Minute))
+	third := event("req-3", day.Add(10*time.Hour))
+	third.Metadata.EventAt = time.Time{}
+
+	local, err := NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	storage := &countingStorage{ObjectStorage: local}
+	pas := NewPartitionedArchiveStore(storage)
+	ctx := context.Background()
+
+	save := func(events ...audit.DataAudit) []ArchivedObject {
+		t.Helper()
+		payload, _ := json.Marshal(Data{NewDate(day): events})
+		objects, err := pas.Save(ctx, "user:123", day, payload)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return objects
+	}
+
+	objects := save(second, first, third)
+	if len(objects) != 2 {
+		t.Fatalf("expected one part per hour, got %+v", objects)
+	}
+	if objects[0].Path != "audits/key=user%3A123/date=2025-01-15/hour=09/part-0.jsonl" || objects[0].Records != 2 || objects[0].Date != "2025-01-15" {
+		t.Errorf("unexpected first part %+v", objects[0])
+	}
+	if !objects[0].FirstEventAt.Equal(first.Metadata.EventAt) || !objects[0].LastEventAt.Equal(second.Metadata.EventAt) {
+		t.Errorf("expected the event range of the hour, got %v - %v", objects[0].FirstEventAt, objects[0].LastEventAt)
+	}
+	if objects[1].Path != "audits/key=user%3A123/date=2025-01-15/hour=10/part-0.jsonl" {
+		t.Errorf("unexpected second part %+v", objects[1])
+	}
+
+	// the same request without event at sent again is another event
+	late := first
+	late.Metadata.ID = event("req-1", day.Add(9*time.Hour+50*time.Minute)).Metadata.ID
+	objects = save(first, late)
+	if len(objects) != 1 || objects[0].Path != "audits/key=user%3A123/date=2025-01-15/hour=09/part-1.jsonl" || objects[0].Records != 1 {
+		t.Errorf("expected a new part with the late event only, got %+v", objects)
+	}
+	if storage.gets != 0 {
+		t.Errorf("expected the parts this store wrote not to be read back, got %d reads", storage.gets)
+	}
+
+	// a store that did not write the parts reads each of them once
+	pas = NewPartitionedArchiveStore(storage)
+	for range 2 {
+		if objects = save(first, late, third); len(objects) != 0 {
+			t.Errorf("expected no part without new events, got %+v", objects)
+		}
+	}
+	if storage.gets != 3 {
+		t.Errorf("expected each of the 3 parts to be read once, got %d reads", storage.gets)
+	}
+
+	if objects = save(first, late, third); len(objects) != 0 {
+		t.Errorf("expected no part without new events, got %+v", objects)
+	}
+
+	payload, err := storage.Get(ctx, "audits/key=user%3A123/date=2025-01-15/hour=09/part-0.jsonl")
+	if err != nil {
+		t.Fatalf("failed to read part: %v", err)
+	}
+	data, err := DecodeArchive("audits/key=user%3A123/date=2025-01-15/hour=09/part-0.jsonl", day, payload)
+	if err != nil {
+		t.Fatalf("failed to decode part: %v", err)
+	}
+	events := data[NewDate(day)]
+	if len(events) != 2 || events[0].Metadata.RequestID != "req-1" || events[1].Metadata.RequestID != "req-2" {
+		t.Errorf("expected the part to hold its events in order, got %+v", events)
+	}
+}
+
+func TestParseArchivePath(t *testing.T) {
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		path        string
+		expectedKey string
+		expectedOK  bool
+	}{
+		{name: "success - daily object", path: "audits/user:123/2025-01-15.json", expectedKey: "user:123", expectedOK: true},
+		{name: "success - hourly part", path: "audits/key=user:123/date=2025-01-15/hour=09/part-3.jsonl", expectedKey: "user:123", expectedOK: true},
+		{name: "success - encoded dot key", path: "audits/%2E%2E/2025-01-15.json", expectedKey: "..", expectedOK: true},
+		{name: "success - encoded daily object", path: "audits/tenant%2Fuser%3A1/2025-01-15.json", expectedKey: "tenant/user:1", expectedOK: true},
+		{name: "success - encoded hourly part", path: "audits/key=tenant%2Fuser%3A1/date=2025-01-15/hour=09/part-0.jsonl", expectedKey: "tenant/user:1", expectedOK: true},
+		{name: "success - daily object of a key with a slash written before encoding", path: "audits/tenant/user:1/2025-01-15.json", expectedKey: "tenant/user:1", expectedOK: true},
+		{name: "success - hourly part of a key with a slash written before encoding", path: "audits/key=tenant/user:1/date=2025-01-15/hour=09/part-0.jsonl", expectedKey: "tenant/user:1", expectedOK: true},
+		{name: "error - daily backup", path: "audits/2025-01-15.json"},
+		{name: "error - cluster node backup", path: "audits/2025-01-15.node-a.json"},
+		{name: "error - manifest", path: "audits/_manifests/2025-01-15.json"},
+		{name: "error - parquet export", path: "exports/parquet/key=user:123/date=2025-01-15/audits.parquet"},
+		{name: "error - hourly file that is not a part", path: "audits/key=user:123/date=2025-01-15/hour=09/notes.txt"},
+		{name: "error - invalid date", path: "audits/user:123/2025-1-15.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, parsedDay, ok := ParseArchivePath(tt.path)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok %v, got %v", tt.expectedOK, ok)
+			}
+			if ok && (key != tt.expectedKey || !parsedDay.Equal(day)) {
+				t.Errorf("expected %s on %v, got %s on %v", tt.expectedKey, day, key, parsedDay)
+			}
+		})
+	}
+}
+
+func TestStoredHour(t *testing.T) {
+	brt := time.FixedZone("BRT", -3*60*60)
+	// 22h in BRT is 01h of the next UTC day
+	storedAt := time.Date(2025, 1, 15, 22, 30, 0, 0, brt)
+
+	tests := []struct {
+		name     string
+		event    audit.DataAudit
+		expected time.Time
+	}{
+		{
+			name:     "success - hour of the event at, stored by a local clock",
+			event:    audit.DataAudit{Metadata: audit.MetadataAudit{EventAt: storedAt}},
+			expected: time.Date(2025, 1, 16, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "success - event at of another day falls back to midnight",
+			event:    audit.DataAudit{Metadata: audit.MetadataAudit{EventAt: storedAt.Add(-24 * time.Hour)}},
+			expected: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StoredHour(NewDate(storedAt), tt.event); !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestManifest_Report(t *testing.T) {
+	tests := []struct {
+		name            string
+		objects         []ArchivedObject
+		expectedKeys    int
+		expectedRecords int
+	}{
+		{
+			name: "success - hourly parts of a key add up",
+			objects: []ArchivedObject{
+				{Key: "user:a", Path: "audits/key=user:a/date=2025-01-05/hour=09/part-0.jsonl", Records: 2},
+				{Key: "user:a", Path: "audits/key=user:a/date=2025-01-05/hour=09/part-1.jsonl", Records: 1},
+				{Key: "user:b", Path: "audits/key=user:b/date=2025-01-05/hour=10/part-0.jsonl", Records: 4},
+			},
+			expectedKeys:    2,
+			expectedRecords: 7,
+		},
+		{
+			name: "success - json and parquet copies count once",
+			objects: []ArchivedObject{
+				{Key: "user:a", Path: "audits/user:a/2025-01-05.json", Records: 3},
+				{Key: "user:a", Path: "exports/parquet/key=user:a/date=2025-01-05/audits.parquet", Records: 3},
+			},
+			expectedKeys:    1,
+			expectedRecords: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Manifest{Date: "2025-01-05", Objects: tt.objects}.Report()
+			if report.Keys != tt.expectedKeys || report.Records != tt.expectedRecords {
+				t.Errorf("expected %d keys and %d records, got %d and %d", tt.expectedKeys, tt.expectedRecords, report.Keys, report.Records)
+			}
+		})
+	}
+}
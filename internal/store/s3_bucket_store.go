@@ -5,18 +5,33 @@ package store
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"time"
 
-	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type S3Client interface {
 	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
 }
 
 type S3BucketStore struct {
@@ -71,42 +86,127 @@ func NewS3BucketStoreWithClient(bucket string, client S3Client) *S3BucketStore {
 }
 
 func (s3bs *S3BucketStore) Backup(ctx context.Context, timeNow time.Time, data []byte) error {
-	key := fmt.Sprintf("audits/%d-%02d-%02d.json", timeNow.Year(), timeNow.Month(), timeNow.Day())
-	// expires in 2 days
-
-	cfg := cfg.GetConfig()
-	expires := timeNow.Add(time.Hour * 24 * time.Duration(cfg.BucketConfig.ExpiresBackupDays))
-
-	_, err := s3bs.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s3bs.bucket),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(data),
-		ContentType: aws.String("application/json"),
-		Expires:     aws.Time(expires),
-	})
+	return NewArchiveStore(s3bs).Backup(ctx, timeNow, data)
+}
+
+func (s3bs *S3BucketStore) Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) ([]ArchivedObject, error) {
+	return NewArchiveStore(s3bs).Save(ctx, dataKey, timeNow, data)
+}
+
+func (s3bs *S3BucketStore) RecordManifest(ctx context.Context, day time.Time, objects []ArchivedObject) error {
+	return NewArchiveStore(s3bs).RecordManifest(ctx, day, objects)
+}
+
+func (s3bs *S3BucketStore) Put(ctx context.Context, path string, data []byte, expires time.Time) (err error) {
+	ctx, span := telemetry.Start(ctx, "store.s3.put",
+		attribute.String("s3.bucket", s3bs.bucket),
+		attribute.String("s3.key", path),
+		attribute.Int("s3.size", len(data)),
+	)
+	defer func() { telemetry.End(span, err) }()
+	defer prometheus.NewTimer(metrics.S3UploadDuration).ObserveDuration()
+
+	// S3 rejects the upload when the body does not match the checksum, and
+	// keeps it for Stat
+	sum := sha256.Sum256(data)
+	input := &s3.PutObjectInput{
+		Bucket:            aws.String(s3bs.bucket),
+		Key:               aws.String(path),
+		Body:              bytes.NewReader(data),
+		ContentType:       aws.String("application/json"),
+		Expires:           aws.Time(expires),
+		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+		ChecksumSHA256:    aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+	}
+	if token, ok := ctxkey.FencingToken(ctx); ok {
+		input.Metadata = map[string]string{FencingTokenMetadata: strconv.FormatUint(token, 10)}
+	}
+
+	_, err = s3bs.client.PutObject(ctx, input)
 	if err != nil {
+		metrics.S3UploadErrors.Inc()
 		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
+	metrics.S3UploadBytes.Add(float64(len(data)))
+
+	return nil
+}
 
+// Ping checks the bucket exists and the credentials can reach it.
+func (s3bs *S3BucketStore) Ping(ctx context.Context) error {
+	if _, err := s3bs.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s3bs.bucket)}); err != nil {
+		return fmt.Errorf("failed to reach bucket %s: %w", s3bs.bucket, err)
+	}
 	return nil
 }
 
-func (s3bs *S3BucketStore) Save(ctx context.Context, dataKey string, timeNow time.Time, data []byte) error {
-	cfg := cfg.GetConfig()
-	expires := timeNow.Add(time.Hour * 24 * time.Duration(cfg.BucketConfig.ExpiresStoreDays))
+// Stat reads the size and the SHA-256 S3 computed on upload with HeadObject.
+// Objects stored without a checksum, by an older release or a backend that
+// does not keep them, are read back instead.
+func (s3bs *S3BucketStore) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	output, err := s3bs.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(s3bs.bucket),
+		Key:          aws.String(path),
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to stat S3 object: %w", err)
+	}
+
+	sum, err := base64.StdEncoding.DecodeString(aws.ToString(output.ChecksumSHA256))
+	if err != nil || len(sum) != sha256.Size {
+		data, err := s3bs.Get(ctx, path)
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		return ObjectInfo{Size: int64(len(data)), SHA256: checksum(data)}, nil
+	}
 
-	key := fmt.Sprintf("audits/%s/%d-%02d-%02d.json", dataKey, timeNow.Year(), timeNow.Month(), timeNow.Day())
+	return ObjectInfo{Size: aws.ToInt64(output.ContentLength), SHA256: hex.EncodeToString(sum)}, nil
+}
 
-	_, err := s3bs.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s3bs.bucket),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(data),
-		ContentType: aws.String("application/json"),
-		Expires:     aws.Time(expires),
+func (s3bs *S3BucketStore) Get(ctx context.Context, path string) ([]byte, error) {
+	output, err := s3bs.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s3bs.bucket),
+		Key:    aws.String(path),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to upload to S3: %w", err)
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to download from S3: %w", err)
 	}
+	defer output.Body.Close()
 
-	return nil
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read S3 object: %w", err)
+	}
+
+	return data, nil
+}
+
+func (s3bs *S3BucketStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+	paginator := s3.NewListObjectsV2Paginator(s3bs.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s3bs.bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+		for _, object := range page.Contents {
+			paths = append(paths, aws.ToString(object.Key))
+		}
+	}
+
+	return paths, nil
 }
@@ -1,14 +1,20 @@
 package store
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/IsaacDSC/auditory/internal/cfg"
 	"github.com/IsaacDSC/auditory/internal/store/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"go.uber.org/mock/gomock"
 )
 
@@ -111,9 +117,31 @@ func TestS3BucketStore_Backup(t *testing.T) {
 	}
 }
 
+// expectVerifiedSave expects Save to find no archived object, upload data
+// and read back its size and checksum.
+func expectVerifiedSave(client *mocks.MockS3Client, data []byte) {
+	sum := sha256.Sum256(data)
+	gomock.InOrder(
+		client.EXPECT().
+			GetObject(gomock.Any(), gomock.Any()).
+			Return(nil, &types.NoSuchKey{}),
+		client.EXPECT().
+			PutObject(gomock.Any(), gomock.Any()).
+			Return(&s3.PutObjectOutput{}, nil),
+		client.EXPECT().
+			HeadObject(gomock.Any(), gomock.Any()).
+			Return(&s3.HeadObjectOutput{
+				ContentLength:  aws.Int64(int64(len(data))),
+				ChecksumSHA256: aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+			}, nil),
+	)
+}
+
 func TestS3BucketStore_Save(t *testing.T) {
 	setupTestConfig()
 	fixedTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	userData := []byte(`{"2025-1-15":[{"data":{"name":"John"},"metadata":{"key":"user:123"}}]}`)
+	orderData := []byte(`{"2025-1-15":[{"data":{"total":"100.00"},"metadata":{"key":"order:456"}}]}`)
 
 	tests := []struct {
 		name          string
@@ -129,11 +157,9 @@ func TestS3BucketStore_Save(t *testing.T) {
 			bucket:  "test-bucket",
 			dataKey: "user:123",
 			timeNow: fixedTime,
-			data:    []byte(`{"name":"John"}`),
+			data:    userData,
 			setupMock: func(client *mocks.MockS3Client) {
-				client.EXPECT().
-					PutObject(gomock.Any(), gomock.Any()).
-					Return(&s3.PutObjectOutput{}, nil)
+				expectVerifiedSave(client, userData)
 			},
 			expectedError: nil,
 		},
@@ -142,11 +168,9 @@ func TestS3BucketStore_Save(t *testing.T) {
 			bucket:  "test-bucket",
 			dataKey: "order:456",
 			timeNow: fixedTime,
-			data:    []byte(`{"total":"100.00"}`),
+			data:    orderData,
 			setupMock: func(client *mocks.MockS3Client) {
-				client.EXPECT().
-					PutObject(gomock.Any(), gomock.Any()).
-					Return(&s3.PutObjectOutput{}, nil)
+				expectVerifiedSave(client, orderData)
 			},
 			expectedError: nil,
 		},
@@ -155,11 +179,33 @@ func TestS3BucketStore_Save(t *testing.T) {
 			bucket:  "test-bucket",
 			dataKey: "user:789",
 			timeNow: fixedTime,
-			data:    []byte{},
+			data:    []byte(`{}`),
 			setupMock: func(client *mocks.MockS3Client) {
-				client.EXPECT().
-					PutObject(gomock.Any(), gomock.Any()).
-					Return(&s3.PutObjectOutput{}, nil)
+				expectVerifiedSave(client, []byte(`{}`))
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "success - object without checksum is read back",
+			bucket:  "test-bucket",
+			dataKey: "user:123",
+			timeNow: fixedTime,
+			data:    userData,
+			setupMock: func(client *mocks.MockS3Client) {
+				gomock.InOrder(
+					client.EXPECT().
+						GetObject(gomock.Any(), gomock.Any()).
+						Return(nil, &types.NoSuchKey{}),
+					client.EXPECT().
+						PutObject(gomock.Any(), gomock.Any()).
+						Return(&s3.PutObjectOutput{}, nil),
+					client.EXPECT().
+						HeadObject(gomock.Any(), gomock.Any()).
+						Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(int64(len(userData)))}, nil),
+					client.EXPECT().
+						GetObject(gomock.Any(), gomock.Any()).
+						Return(&s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(userData))}, nil),
+				)
 			},
 			expectedError: nil,
 		},
@@ -168,8 +214,11 @@ func TestS3BucketStore_Save(t *testing.T) {
 			bucket:  "test-bucket",
 			dataKey: "user:123",
 			timeNow: fixedTime,
-			data:    []byte(`{"name":"John"}`),
+			data:    userData,
 			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().
+					GetObject(gomock.Any(), gomock.Any()).
+					Return(nil, &types.NoSuchKey{})
 				client.EXPECT().
 					PutObject(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("s3 connection timeout"))
@@ -181,14 +230,36 @@ func TestS3BucketStore_Save(t *testing.T) {
 			bucket:  "restricted-bucket",
 			dataKey: "user:123",
 			timeNow: fixedTime,
-			data:    []byte(`{"name":"John"}`),
+			data:    userData,
 			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().
+					GetObject(gomock.Any(), gomock.Any()).
+					Return(nil, &types.NoSuchKey{})
 				client.EXPECT().
 					PutObject(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("access denied"))
 			},
 			expectedError: errors.New("failed to upload to S3: access denied"),
 		},
+		{
+			name:    "error - uploaded object is missing",
+			bucket:  "test-bucket",
+			dataKey: "user:123",
+			timeNow: fixedTime,
+			data:    userData,
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().
+					GetObject(gomock.Any(), gomock.Any()).
+					Return(nil, &types.NoSuchKey{})
+				client.EXPECT().
+					PutObject(gomock.Any(), gomock.Any()).
+					Return(&s3.PutObjectOutput{}, nil)
+				client.EXPECT().
+					HeadObject(gomock.Any(), gomock.Any()).
+					Return(nil, &types.NotFound{})
+			},
+			expectedError: errors.New("failed to verify audits/user%3A123/2025-01-15.json: object not found"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -200,7 +271,7 @@ func TestS3BucketStore_Save(t *testing.T) {
 			tt.setupMock(mockClient)
 
 			s3Store := NewS3BucketStoreWithClient(tt.bucket, mockClient)
-			err := s3Store.Save(context.Background(), tt.dataKey, tt.timeNow, tt.data)
+			_, err := s3Store.Save(context.Background(), tt.dataKey, tt.timeNow, tt.data)
 
 			if tt.expectedError != nil {
 				if err == nil {
@@ -219,6 +290,52 @@ func TestS3BucketStore_Save(t *testing.T) {
 	}
 }
 
+func TestS3BucketStore_Ping(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(client *mocks.MockS3Client)
+		wantErr   bool
+	}{
+		{
+			name: "success - bucket reachable",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().
+					HeadBucket(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *s3.HeadBucketInput, _ ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+						if *input.Bucket != "test-bucket" {
+							t.Errorf("expected test-bucket, got %s", *input.Bucket)
+						}
+						return &s3.HeadBucketOutput{}, nil
+					})
+			},
+		},
+		{
+			name: "error - bucket unreachable",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().
+					HeadBucket(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mocks.NewMockS3Client(ctrl)
+			tt.setupMock(mockClient)
+
+			err := NewS3BucketStoreWithClient("test-bucket", mockClient).Ping(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestS3Config(t *testing.T) {
 	tests := []struct {
 		name     string
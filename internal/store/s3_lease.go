@@ -0,0 +1,133 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+type s3LeaseRecord struct {
+	Holder    string    `json:"holder"`
+	Token     uint64    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// S3Lease is a leader lease kept in a single bucket object. Every write is
+// conditional on the ETag read just before (or on the object not existing), so
+// two replicas racing for an expired lease cannot both win. Expiry compares
+// the replicas' clocks, which must stay well within the lease TTL of each other.
+type S3Lease struct {
+	bucket string
+	key    string
+	client S3Client
+}
+
+func NewS3Lease(s3bs *S3BucketStore, key string) *S3Lease {
+	return &S3Lease{bucket: s3bs.bucket, key: key, client: s3bs.client}
+}
+
+// Acquire takes or renews the lease for holder. A holder taking over from
+// another gets the previous token plus one; renewals keep the token.
+func (sl *S3Lease) Acquire(ctx context.Context, holder string, ttl time.Duration) (uint64, bool, error) {
+	current, etag, err := sl.read(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	now := clock.Now()
+	next := s3LeaseRecord{Holder: holder, Token: 1, ExpiresAt: now.Add(ttl)}
+	if current != nil {
+		if current.Holder != holder && now.Before(current.ExpiresAt) {
+			return 0, false, nil
+		}
+		next.Token = current.Token
+		if current.Holder != holder {
+			next.Token++
+		}
+	}
+
+	won, err := sl.write(ctx, next, etag)
+	if err != nil || !won {
+		return 0, false, err
+	}
+	return next.Token, true, nil
+}
+
+// Release expires the lease right away when holder still owns it, keeping the
+// token so the next leader still gets a higher one.
+func (sl *S3Lease) Release(ctx context.Context, holder string) error {
+	current, etag, err := sl.read(ctx)
+	if err != nil || current == nil || current.Holder != holder {
+		return err
+	}
+
+	current.ExpiresAt = clock.Now()
+	_, err = sl.write(ctx, *current, etag)
+	return err
+}
+
+func (sl *S3Lease) read(ctx context.Context) (*s3LeaseRecord, string, error) {
+	output, err := sl.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(sl.bucket),
+		Key:    aws.String(sl.key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read lease: %w", err)
+	}
+	defer output.Body.Close()
+
+	payload, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read lease: %w", err)
+	}
+
+	var record s3LeaseRecord
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return nil, "", fmt.Errorf("failed to decode lease: %w", err)
+	}
+	return &record, aws.ToString(output.ETag), nil
+}
+
+// write stores record if the object still has etag, or does not exist when
+// etag is empty. It reports false when another replica wrote first.
+func (sl *S3Lease) write(ctx context.Context, record s3LeaseRecord, etag string) (bool, error) {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode lease: %w", err)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(sl.bucket),
+		Key:         aws.String(sl.key),
+		Body:        bytes.NewReader(payload),
+		ContentType: aws.String("application/json"),
+	}
+	if etag == "" {
+		input.IfNoneMatch = aws.String("*")
+	} else {
+		input.IfMatch = aws.String(etag)
+	}
+
+	if _, err := sl.client.PutObject(ctx, input); err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "PreconditionFailed" || apiErr.ErrorCode() == "ConditionalRequestConflict") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to write lease: %w", err)
+	}
+	return true, nil
+}
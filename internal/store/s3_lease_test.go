@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/store/mocks"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"go.uber.org/mock/gomock"
+)
+
+func leaseObject(body string) *s3.GetObjectOutput {
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(body)), ETag: aws.String(`"etag-1"`)}
+}
+
+func TestS3Lease_Acquire(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	oldNow := clock.Now
+	clock.SetNow(now)
+	defer func() { clock.Now = oldNow }()
+
+	tests := []struct {
+		name             string
+		setupMock        func(client *mocks.MockS3Client)
+		expectedToken    uint64
+		expectedAcquired bool
+		expectedErr      bool
+	}{
+		{
+			name: "success - creates the lease when none exists",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(nil, &types.NoSuchKey{})
+				client.EXPECT().PutObject(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, input *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+						if aws.ToString(input.IfNoneMatch) != "*" || input.IfMatch != nil {
+							t.Errorf("expected create-only write, got IfNoneMatch=%v IfMatch=%v", input.IfNoneMatch, input.IfMatch)
+						}
+						return &s3.PutObjectOutput{}, nil
+					})
+			},
+			expectedToken:    1,
+			expectedAcquired: true,
+		},
+		{
+			name: "success - renews own lease keeping the token",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().GetObject(gomock.Any(), gomock.Any()).
+					Return(leaseObject(`{"holder":"replica-a","token":4,"expires_at":"2026-01-10T12:00:10Z"}`), nil)
+				client.EXPECT().PutObject(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, input *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+						if aws.ToString(input.IfMatch) != `"etag-1"` {
+							t.Errorf("expected write conditional on the read ETag, got %v", input.IfMatch)
+						}
+						return &s3.PutObjectOutput{}, nil
+					})
+			},
+			expectedToken:    4,
+			expectedAcquired: true,
+		},
+		{
+			name: "success - takes over an expired lease with the next token",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().GetObject(gomock.Any(), gomock.Any()).
+					Return(leaseObject(`{"holder":"replica-b","token":4,"expires_at":"2026-01-10T11:59:00Z"}`), nil)
+				client.EXPECT().PutObject(gomock.Any(), gomock.Any()).Return(&s3.PutObjectOutput{}, nil)
+			},
+			expectedToken:    5,
+			expectedAcquired: true,
+		},
+		{
+			name: "success - does not touch a lease another replica holds",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().GetObject(gomock.Any(), gomock.Any()).
+					Return(leaseObject(`{"holder":"replica-b","token":4,"expires_at":"2026-01-10T12:00:10Z"}`), nil)
+			},
+			expectedAcquired: false,
+		},
+		{
+			name: "success - loses the race to another replica",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().GetObject(gomock.Any(), gomock.Any()).
+					Return(leaseObject(`{"holder":"replica-b","token":4,"expires_at":"2026-01-10T11:59:00Z"}`), nil)
+				client.EXPECT().PutObject(gomock.Any(), gomock.Any()).
+					Return(nil, &smithy.GenericAPIError{Code: "PreconditionFailed"})
+			},
+			expectedAcquired: false,
+		},
+		{
+			name: "error - bucket unavailable",
+			setupMock: func(client *mocks.MockS3Client) {
+				client.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mocks.NewMockS3Client(ctrl)
+			tt.setupMock(client)
+
+			lease := NewS3Lease(NewS3BucketStoreWithClient("test-bucket", client), "leases/control-plane.json")
+			token, acquired, err := lease.Acquire(context.Background(), "replica-a", 15*time.Second)
+
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got: %v", tt.expectedErr, err)
+			}
+			if token != tt.expectedToken || acquired != tt.expectedAcquired {
+				t.Errorf("expected token %d acquired %v, got %d %v", tt.expectedToken, tt.expectedAcquired, token, acquired)
+			}
+		})
+	}
+}
+
+func TestS3BucketStore_Put_FencingToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockS3Client(ctrl)
+	client.EXPECT().PutObject(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+			if got := input.Metadata[FencingTokenMetadata]; got != "42" {
+				t.Errorf("expected fencing token 42 in metadata, got %q", got)
+			}
+			return &s3.PutObjectOutput{}, nil
+		})
+
+	ctx := ctxkey.SetFencingToken(context.Background(), 42)
+	if err := NewS3BucketStoreWithClient("test-bucket", client).Put(ctx, "audits/2026-01-10.json", []byte("[]"), time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type SFTPConfig struct {
+	Addr           string // host:port
+	User           string
+	Password       string
+	KnownHostsFile string // defaults to ~/.ssh/known_hosts
+	Dir            string // remote root directory
+}
+
+// SFTPStorage archives objects on a remote host over SFTP. Like the local
+// directory backend it does not enforce expiration.
+type SFTPStorage struct {
+	client *sftp.Client
+	root   string
+}
+
+func DialSFTP(sftpCfg SFTPConfig) (*SFTPStorage, error) {
+	knownHostsFile := sftpCfg.KnownHostsFile
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve known hosts: %w", err)
+		}
+		knownHostsFile = path.Join(home, ".ssh", "known_hosts")
+	}
+
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known hosts: %w", err)
+	}
+
+	conn, err := ssh.Dial("tcp", sftpCfg.Addr, &ssh.ClientConfig{
+		User:            sftpCfg.User,
+		Auth:            []ssh.AuthMethod{ssh.Password(sftpCfg.Password)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         10 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to sftp server: %w", err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start sftp session: %w", err)
+	}
+
+	return NewSFTPStorage(client, sftpCfg.Dir), nil
+}
+
+func NewSFTPStorage(client *sftp.Client, root string) *SFTPStorage {
+	return &SFTPStorage{
+		client: client,
+		root:   root,
+	}
+}
+
+// fullPath is objectPath under root, refused when it would resolve outside
+// it, as in LocalDirStorage.
+func (ss *SFTPStorage) fullPath(objectPath string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(objectPath)) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, objectPath)
+	}
+	return path.Join(ss.root, objectPath), nil
+}
+
+func (ss *SFTPStorage) Put(ctx context.Context, objectPath string, data []byte, expires time.Time) error {
+	fullPath, err := ss.fullPath(objectPath)
+	if err != nil {
+		return err
+	}
+	if err := ss.client.MkdirAll(path.Dir(fullPath)); err != nil {
+		return fmt.Errorf("failed to create sftp directory: %w", err)
+	}
+
+	tmpPath := fullPath + ".tmp"
+	file, err := ss.client.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create sftp file: %w", err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write sftp file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write sftp file: %w", err)
+	}
+
+	if err := ss.client.PosixRename(tmpPath, fullPath); err != nil {
+		return fmt.Errorf("failed to write sftp file: %w", err)
+	}
+
+	return nil
+}
+
+func (ss *SFTPStorage) Get(ctx context.Context, objectPath string) ([]byte, error) {
+	fullPath, err := ss.fullPath(objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := ss.client.Open(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to open sftp file: %w", err)
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+func (ss *SFTPStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+	walker := ss.client.Walk(ss.root)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to list sftp directory: %w", err)
+		}
+		if walker.Stat().IsDir() || strings.HasSuffix(walker.Path(), ".tmp") {
+			continue
+		}
+
+		objectPath := strings.TrimPrefix(strings.TrimPrefix(walker.Path(), ss.root), "/")
+		if strings.HasPrefix(objectPath, prefix) {
+			paths = append(paths, objectPath)
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (ss *SFTPStorage) Close() error {
+	return ss.client.Close()
+}
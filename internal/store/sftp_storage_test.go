@@ -0,0 +1,26 @@
+package store
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pkg/sftp"
+)
+
+func TestSFTPStorage(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+
+	server := sftp.NewRequestServer(serverConn, sftp.InMemHandler())
+	go func() { _ = server.Serve() }()
+	t.Cleanup(func() { server.Close() })
+
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	if err != nil {
+		t.Fatalf("failed to create sftp client: %v", err)
+	}
+
+	storage := NewSFTPStorage(client, "/auditory")
+	t.Cleanup(func() { storage.Close() })
+
+	testObjectStorage(t, storage)
+}
@@ -0,0 +1,268 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+
+	defaultBatchSize = 500
+)
+
+// SQLAuditStore keeps audits in a relational table with indexed metadata
+// columns and the payload as JSON. On PostgreSQL the table is range
+// partitioned by day and partitions are created on demand; SQLite has no
+// partitioning and relies on the event_date index instead.
+type SQLAuditStore struct {
+	db         *sql.DB
+	dialect    string
+	batchSize  int
+	partitions sync.Map // event_date -> struct{}, postgres only
+}
+
+// OpenSQLAuditStore opens the database, applies pending migrations and returns
+// a ready to use store.
+func OpenSQLAuditStore(ctx context.Context, dialect, dsn string, batchSize int) (*SQLAuditStore, error) {
+	var driver string
+	switch dialect {
+	case DialectPostgres:
+		driver = "pgx"
+	case DialectSQLite:
+		driver = "sqlite"
+	default:
+		return nil, fmt.Errorf("unknown sql dialect: %s", dialect)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if dialect == DialectSQLite {
+		// sqlite allows a single writer, serialize through one connection
+		db.SetMaxOpenConns(1)
+	}
+
+	sas := NewSQLAuditStore(db, dialect, batchSize)
+	if err := sas.Migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return sas, nil
+}
+
+func NewSQLAuditStore(db *sql.DB, dialect string, batchSize int) *SQLAuditStore {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &SQLAuditStore{
+		db:        db,
+		dialect:   dialect,
+		batchSize: batchSize,
+	}
+}
+
+func (sas *SQLAuditStore) Migrate(ctx context.Context) error {
+	if _, err := sas.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		var applied int
+		err := sas.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM schema_migrations WHERE version = "+sas.placeholder(1), m.version,
+		).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("failed to read schema_migrations: %w", err)
+		}
+		if applied > 0 {
+			continue
+		}
+
+		statements := m.sqlite
+		if sas.dialect == DialectPostgres {
+			statements = m.postgres
+		}
+
+		if err := sas.inTx(ctx, func(tx *sql.Tx) error {
+			for _, statement := range statements {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx,
+				fmt.Sprintf("INSERT INTO schema_migrations (version, applied_at) VALUES (%s, %s)", sas.placeholder(1), sas.placeholder(2)),
+				m.version, clock.Now().Format(time.RFC3339),
+			)
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", m.version, err)
+		}
+	}
+
+	return nil
+}
+
+func (sas *SQLAuditStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	return sas.InsertBatch(ctx, []audit.DataAudit{input})
+}
+
+// InsertBatch writes the audits in multi-row statements of batchSize rows.
+// Rows already stored (same day and event id, see eventID) are skipped, so
+// feeding the same data twice is harmless.
+func (sas *SQLAuditStore) InsertBatch(ctx context.Context, inputs []audit.DataAudit) error {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	if err := sas.ensurePartitions(ctx, inputs); err != nil {
+		return err
+	}
+
+	return sas.inTx(ctx, func(tx *sql.Tx) error {
+		for start := 0; start < len(inputs); start += sas.batchSize {
+			end := min(start+sas.batchSize, len(inputs))
+			if err := sas.insertChunk(ctx, tx, inputs[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (sas *SQLAuditStore) insertChunk(ctx context.Context, tx *sql.Tx, inputs []audit.DataAudit) error {
+	const columns = 10
+	rows := make([]string, 0, len(inputs))
+	args := make([]any, 0, len(inputs)*columns)
+
+	for i, input := range inputs {
+		data, err := json.Marshal(input.Data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal data: %w", err)
+		}
+
+		eventAt := eventTime(input)
+		placeholders := make([]string, columns)
+		for c := range placeholders {
+			placeholders[c] = sas.placeholder(i*columns + c + 1)
+		}
+		rows = append(rows, "("+strings.Join(placeholders, ", ")+")")
+
+		args = append(args,
+			input.Metadata.Key,
+			input.Metadata.EventName,
+			input.Metadata.RequestID,
+			input.Metadata.CorrelationID,
+			sas.timeValue(eventAt),
+			eventAt.Format(time.DateOnly),
+			string(data),
+			nullString(input.Metadata.TraceID),
+			nullString(input.Metadata.SpanID),
+			eventID(input),
+		)
+	}
+
+	query := "INSERT INTO audits (key, event_name, request_id, correlation_id, event_at, event_date, data, trace_id, span_id, event_id) VALUES " +
+		strings.Join(rows, ", ") + " ON CONFLICT DO NOTHING"
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to insert audits: %w", err)
+	}
+
+	return nil
+}
+
+// ensurePartitions creates the daily partitions needed by the batch. It runs
+// outside the insert transaction so a concurrent creator does not abort it.
+func (sas *SQLAuditStore) ensurePartitions(ctx context.Context, inputs []audit.DataAudit) error {
+	if sas.dialect != DialectPostgres {
+		return nil
+	}
+
+	for _, input := range inputs {
+		day := eventTime(input).Truncate(24 * time.Hour)
+		date := day.Format(time.DateOnly)
+		if _, ok := sas.partitions.Load(date); ok {
+			continue
+		}
+
+		statement := fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS audits_%s PARTITION OF audits FOR VALUES FROM ('%s') TO ('%s')",
+			day.Format("20060102"), date, day.AddDate(0, 0, 1).Format(time.DateOnly),
+		)
+		if _, err := sas.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create partition %s: %w", date, err)
+		}
+		sas.partitions.Store(date, struct{}{})
+	}
+
+	return nil
+}
+
+func (sas *SQLAuditStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := sas.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (sas *SQLAuditStore) placeholder(n int) string {
+	if sas.dialect == DialectPostgres {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// timeValue stores sqlite timestamps as sortable RFC3339 text.
+func (sas *SQLAuditStore) timeValue(t time.Time) any {
+	if sas.dialect == DialectPostgres {
+		return t
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func (sas *SQLAuditStore) Close() error {
+	return sas.db.Close()
+}
+
+// eventTime is the event at, or for audits without one the time they were
+// stored, so feeding them again lands on the same day.
+func eventTime(input audit.DataAudit) time.Time {
+	if !input.Metadata.EventAt.IsZero() {
+		return input.Metadata.EventAt.UTC()
+	}
+	if storedAt, ok := audit.IDTime(input.Metadata.ID); ok {
+		return storedAt
+	}
+	return clock.Now().UTC()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
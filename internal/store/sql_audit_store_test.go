@@ -0,0 +1,204 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+func newTestSQLiteStore(t *testing.T, batchSize int) *SQLAuditStore {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "auditory.db")
+
+	sas, err := OpenSQLAuditStore(context.Background(), DialectSQLite, dsn, batchSize)
+	if err != nil {
+		t.Fatalf("failed to open sql store: %v", err)
+	}
+	t.Cleanup(func() { sas.Close() })
+
+	return sas
+}
+
+func newTestAudit(i int, eventAt time.Time) audit.DataAudit {
+	return audit.DataAudit{
+		Metadata: audit.MetadataAudit{
+			Key:           "order:42",
+			EventName:     "order.updated",
+			RequestID:     fmt.Sprintf("req-%d", i),
+			CorrelationID: "corr-1",
+			EventAt:       eventAt,
+		},
+		Data: map[string]any{"status": "paid", "index": i},
+	}
+}
+
+func withID(input audit.DataAudit, id string) audit.DataAudit {
+	input.Metadata.ID = id
+	return input
+}
+
+func TestSQLAuditStore_Migrate(t *testing.T) {
+	sas := newTestSQLiteStore(t, 0)
+
+	// running migrations again must be a no-op
+	if err := sas.Migrate(context.Background()); err != nil {
+		t.Fatalf("unexpected error on second migrate: %v", err)
+	}
+
+	var versions int
+	if err := sas.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&versions); err != nil {
+		t.Fatalf("failed to count migrations: %v", err)
+	}
+
+	if versions != len(migrations) {
+		t.Errorf("expected %d applied migrations, got %d", len(migrations), versions)
+	}
+}
+
+func TestSQLAuditStore_MigrateEventID(t *testing.T) {
+	ctx := context.Background()
+	all := migrations
+	defer func() { migrations = all }()
+
+	// a database created before event ids keeps its rows
+	migrations = all[:2]
+	sas := newTestSQLiteStore(t, 0)
+	if _, err := sas.db.Exec(`INSERT INTO audits (key, event_name, request_id, correlation_id, event_at, event_date, data)
+		VALUES ('order:42', 'order.updated', 'req-1', 'corr-1', '2025-01-15T10:00:00Z', '2025-01-15', '{}')`); err != nil {
+		t.Fatalf("failed to insert legacy row: %v", err)
+	}
+
+	migrations = all
+	if err := sas.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the same request again is a new event once it has its own id
+	input := withID(newTestAudit(1, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)), "01JHM5C0000000000000000001")
+	if err := sas.Upsert(ctx, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rows int
+	if err := sas.db.QueryRow("SELECT COUNT(*) FROM audits").Scan(&rows); err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	if rows != 2 {
+		t.Errorf("expected the legacy row and the new event, got %d rows", rows)
+	}
+}
+
+func TestSQLAuditStore_InsertBatch(t *testing.T) {
+	day1 := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 1, 16, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		batchSize     int
+		inputs        []audit.DataAudit
+		expectedRows  int
+		expectedDates map[string]int
+	}{
+		{
+			name:          "success - single audit",
+			batchSize:     10,
+			inputs:        []audit.DataAudit{newTestAudit(1, day1)},
+			expectedRows:  1,
+			expectedDates: map[string]int{"2025-01-15": 1},
+		},
+		{
+			name:      "success - more audits than batch size",
+			batchSize: 2,
+			inputs: []audit.DataAudit{
+				newTestAudit(1, day1), newTestAudit(2, day1), newTestAudit(3, day1),
+				newTestAudit(4, day2), newTestAudit(5, day2),
+			},
+			expectedRows:  5,
+			expectedDates: map[string]int{"2025-01-15": 3, "2025-01-16": 2},
+		},
+		{
+			name:          "success - duplicates are ignored",
+			batchSize:     10,
+			inputs:        []audit.DataAudit{newTestAudit(1, day1), newTestAudit(1, day1)},
+			expectedRows:  1,
+			expectedDates: map[string]int{"2025-01-15": 1},
+		},
+		{
+			name:      "success - events sharing a request are told apart by their id",
+			batchSize: 10,
+			inputs: []audit.DataAudit{
+				withID(newTestAudit(1, day1), "01JHM5C0000000000000000001"),
+				withID(newTestAudit(1, day1), "01JHM5C0000000000000000002"),
+				withID(newTestAudit(1, day1), "01JHM5C0000000000000000002"),
+			},
+			expectedRows:  2,
+			expectedDates: map[string]int{"2025-01-15": 2},
+		},
+		{
+			name:         "success - empty batch",
+			batchSize:    10,
+			inputs:       nil,
+			expectedRows: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sas := newTestSQLiteStore(t, tt.batchSize)
+
+			if err := sas.InsertBatch(context.Background(), tt.inputs); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var rows int
+			if err := sas.db.QueryRow("SELECT COUNT(*) FROM audits").Scan(&rows); err != nil {
+				t.Fatalf("failed to count rows: %v", err)
+			}
+			if rows != tt.expectedRows {
+				t.Errorf("expected %d rows, got %d", tt.expectedRows, rows)
+			}
+
+			for date, expected := range tt.expectedDates {
+				var count int
+				if err := sas.db.QueryRow("SELECT COUNT(*) FROM audits WHERE event_date = ?", date).Scan(&count); err != nil {
+					t.Fatalf("failed to count rows: %v", err)
+				}
+				if count != expected {
+					t.Errorf("expected %d rows on %s, got %d", expected, date, count)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLAuditStore_Upsert(t *testing.T) {
+	sas := newTestSQLiteStore(t, 0)
+	input := newTestAudit(1, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+
+	if err := sas.Upsert(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var status string
+	err := sas.db.QueryRow(
+		"SELECT json_extract(data, '$.status') FROM audits WHERE key = ? AND request_id = ?",
+		"order:42", "req-1",
+	).Scan(&status)
+	if err != nil {
+		t.Fatalf("failed to query data: %v", err)
+	}
+
+	if status != "paid" {
+		t.Errorf("expected status paid, got %q", status)
+	}
+}
+
+func TestOpenSQLAuditStore_UnknownDialect(t *testing.T) {
+	if _, err := OpenSQLAuditStore(context.Background(), "oracle", "", 0); err == nil {
+		t.Errorf("expected error for unknown dialect")
+	}
+}
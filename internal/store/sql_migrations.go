@@ -0,0 +1,105 @@
+package store
+
+type migration struct {
+	version  int
+	postgres []string
+	sqlite   []string
+}
+
+// migrations are applied in order and recorded in schema_migrations. Never edit
+// an applied migration, append a new one instead.
+var migrations = []migration{
+	{
+		version: 1,
+		postgres: []string{
+			`CREATE TABLE IF NOT EXISTS audits (
+				id             BIGSERIAL,
+				key            TEXT        NOT NULL,
+				event_name     TEXT        NOT NULL,
+				request_id     TEXT        NOT NULL,
+				correlation_id TEXT        NOT NULL,
+				event_at       TIMESTAMPTZ NOT NULL,
+				event_date     DATE        NOT NULL,
+				data           JSONB,
+				created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+				PRIMARY KEY (event_date, id),
+				UNIQUE (event_date, key, event_name, request_id, correlation_id)
+			) PARTITION BY RANGE (event_date)`,
+			`CREATE INDEX IF NOT EXISTS audits_key_event_at_idx ON audits (key, event_at)`,
+			`CREATE INDEX IF NOT EXISTS audits_event_name_idx ON audits (event_name)`,
+			`CREATE INDEX IF NOT EXISTS audits_request_id_idx ON audits (request_id)`,
+			`CREATE INDEX IF NOT EXISTS audits_correlation_id_idx ON audits (correlation_id)`,
+			`CREATE INDEX IF NOT EXISTS audits_data_idx ON audits USING GIN (data)`,
+		},
+		sqlite: []string{
+			`CREATE TABLE IF NOT EXISTS audits (
+				id             INTEGER PRIMARY KEY AUTOINCREMENT,
+				key            TEXT NOT NULL,
+				event_name     TEXT NOT NULL,
+				request_id     TEXT NOT NULL,
+				correlation_id TEXT NOT NULL,
+				event_at       TEXT NOT NULL,
+				event_date     TEXT NOT NULL,
+				data           TEXT CHECK (data IS NULL OR json_valid(data)),
+				created_at     TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (event_date, key, event_name, request_id, correlation_id)
+			)`,
+			`CREATE INDEX IF NOT EXISTS audits_key_event_at_idx ON audits (key, event_at)`,
+			`CREATE INDEX IF NOT EXISTS audits_event_date_idx ON audits (event_date)`,
+			`CREATE INDEX IF NOT EXISTS audits_event_name_idx ON audits (event_name)`,
+			`CREATE INDEX IF NOT EXISTS audits_request_id_idx ON audits (request_id)`,
+			`CREATE INDEX IF NOT EXISTS audits_correlation_id_idx ON audits (correlation_id)`,
+		},
+	},
+	{
+		version: 2,
+		postgres: []string{
+			`ALTER TABLE audits ADD COLUMN IF NOT EXISTS trace_id TEXT`,
+			`ALTER TABLE audits ADD COLUMN IF NOT EXISTS span_id TEXT`,
+			`CREATE INDEX IF NOT EXISTS audits_trace_id_idx ON audits (trace_id)`,
+		},
+		sqlite: []string{
+			`ALTER TABLE audits ADD COLUMN trace_id TEXT`,
+			`ALTER TABLE audits ADD COLUMN span_id TEXT`,
+			`CREATE INDEX IF NOT EXISTS audits_trace_id_idx ON audits (trace_id)`,
+		},
+	},
+	{
+		// rows are told apart by the event id stamped at ingestion: the same
+		// request sent twice is two events once its idempotency key expires
+		version: 3,
+		postgres: []string{
+			`ALTER TABLE audits ADD COLUMN IF NOT EXISTS event_id TEXT`,
+			`ALTER TABLE audits DROP CONSTRAINT IF EXISTS audits_event_date_key_event_name_request_id_correlation_id_key`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS audits_event_id_idx ON audits (event_date, event_id)`,
+		},
+		// sqlite cannot drop a table constraint, the table is rebuilt
+		sqlite: []string{
+			`CREATE TABLE audits_v3 (
+				id             INTEGER PRIMARY KEY AUTOINCREMENT,
+				key            TEXT NOT NULL,
+				event_name     TEXT NOT NULL,
+				request_id     TEXT NOT NULL,
+				correlation_id TEXT NOT NULL,
+				event_at       TEXT NOT NULL,
+				event_date     TEXT NOT NULL,
+				data           TEXT CHECK (data IS NULL OR json_valid(data)),
+				created_at     TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				trace_id       TEXT,
+				span_id        TEXT,
+				event_id       TEXT
+			)`,
+			`INSERT INTO audits_v3 (id, key, event_name, request_id, correlation_id, event_at, event_date, data, created_at, trace_id, span_id)
+				SELECT id, key, event_name, request_id, correlation_id, event_at, event_date, data, created_at, trace_id, span_id FROM audits`,
+			`DROP TABLE audits`,
+			`ALTER TABLE audits_v3 RENAME TO audits`,
+			`CREATE INDEX IF NOT EXISTS audits_key_event_at_idx ON audits (key, event_at)`,
+			`CREATE INDEX IF NOT EXISTS audits_event_date_idx ON audits (event_date)`,
+			`CREATE INDEX IF NOT EXISTS audits_event_name_idx ON audits (event_name)`,
+			`CREATE INDEX IF NOT EXISTS audits_request_id_idx ON audits (request_id)`,
+			`CREATE INDEX IF NOT EXISTS audits_correlation_id_idx ON audits (correlation_id)`,
+			`CREATE INDEX IF NOT EXISTS audits_trace_id_idx ON audits (trace_id)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS audits_event_id_idx ON audits (event_date, event_id)`,
+		},
+	},
+}
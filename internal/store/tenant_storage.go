@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+// PrefixedStorage keeps every object of storage under prefix, so a tenant
+// sharing a bucket sees the same paths as one with a bucket of its own.
+type PrefixedStorage struct {
+	storage ObjectStorage
+	prefix  string
+}
+
+func NewPrefixedStorage(storage ObjectStorage, prefix string) *PrefixedStorage {
+	return &PrefixedStorage{
+		storage: storage,
+		prefix:  prefix,
+	}
+}
+
+func (ps *PrefixedStorage) Put(ctx context.Context, path string, data []byte, expires time.Time) error {
+	return ps.storage.Put(ctx, ps.prefix+path, data, expires)
+}
+
+func (ps *PrefixedStorage) Get(ctx context.Context, path string) ([]byte, error) {
+	return ps.storage.Get(ctx, ps.prefix+path)
+}
+
+func (ps *PrefixedStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	paths, err := ps.storage.List(ctx, ps.prefix+prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, path := range paths {
+		paths[i] = strings.TrimPrefix(path, ps.prefix)
+	}
+	return paths, nil
+}
+
+func (ps *PrefixedStorage) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	return StatObject(ctx, ps.storage, ps.prefix+path)
+}
+
+// Ping reaches the wrapped storage when it can be pinged.
+func (ps *PrefixedStorage) Ping(ctx context.Context) error {
+	if pinger, ok := ps.storage.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// TenantPrefix is where tenant archives in a storage it may share: its
+// configured prefix, none with a bucket of its own, "tenants/{tenant}/"
+// otherwise.
+func TenantPrefix(conf cfg.StorageConfig, tenant string) string {
+	if prefix, ok := conf.TenantPrefixes[tenant]; ok {
+		return prefix
+	}
+	if conf.TenantBuckets[tenant] != "" {
+		return ""
+	}
+	return fmt.Sprintf("tenants/%s/", tenant)
+}
+
+// NewTenantStorage opens the archive storage of tenant: the shared backend
+// pointed at the tenant bucket when one is configured, under TenantPrefix.
+func NewTenantStorage(ctx context.Context, conf *cfg.GeneralConfig, tenant string) (ObjectStorage, error) {
+	if err := ValidateTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	tenantConf := *conf
+	if bucket := conf.StorageConfig.TenantBuckets[tenant]; bucket != "" {
+		switch conf.ArchiveConfig.Backend {
+		case "", "s3":
+			tenantConf.BucketConfig.Name = bucket
+		case "gcs":
+			tenantConf.ArchiveConfig.GCS.Bucket = bucket
+		case "azure":
+			tenantConf.ArchiveConfig.Azure.Container = bucket
+		default:
+			return nil, fmt.Errorf("tenant %s: archive backend %s has no buckets, use a prefix", tenant, conf.ArchiveConfig.Backend)
+		}
+	}
+
+	storage, err := NewObjectStorage(ctx, &tenantConf)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix := TenantPrefix(conf.StorageConfig, tenant); prefix != "" {
+		return NewPrefixedStorage(storage, prefix), nil
+	}
+	return storage, nil
+}
+
+// TenantArchives serves the manifests of the archive of the caller's tenant,
+// the shared one without tenant.
+type TenantArchives struct {
+	shared  *ArchiveStore
+	tenants map[string]*ArchiveStore
+}
+
+func NewTenantArchives(shared *ArchiveStore) *TenantArchives {
+	return &TenantArchives{
+		shared:  shared,
+		tenants: make(map[string]*ArchiveStore),
+	}
+}
+
+func (ta *TenantArchives) Add(tenant string, archive *ArchiveStore) *TenantArchives {
+	ta.tenants[tenant] = archive
+	return ta
+}
+
+func (ta *TenantArchives) Manifest(ctx context.Context, day time.Time) (Manifest, error) {
+	archive, err := ta.archive(ctx)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return archive.Manifest(ctx, day)
+}
+
+func (ta *TenantArchives) Reconcile(ctx context.Context, day time.Time) (ArchiveReport, error) {
+	archive, err := ta.archive(ctx)
+	if err != nil {
+		return ArchiveReport{}, err
+	}
+	return archive.Reconcile(ctx, day)
+}
+
+func (ta *TenantArchives) archive(ctx context.Context) (*ArchiveStore, error) {
+	tenant, _ := ctxkey.Tenant(ctx)
+	if tenant == "" {
+		return ta.shared, nil
+	}
+
+	archive, ok := ta.tenants[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTenant, tenant)
+	}
+	return archive, nil
+}
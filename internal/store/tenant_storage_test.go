@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+)
+
+func TestPrefixedStorage(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	prefixed := NewPrefixedStorage(storage, "tenants/acme/")
+
+	if err := prefixed.Put(ctx, "audits/user:1/2025-01-15.json", []byte("{}"), time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := storage.Get(ctx, "tenants/acme/audits/user:1/2025-01-15.json"); err != nil {
+		t.Errorf("expected the object under the prefix, got %v", err)
+	}
+	paths, err := prefixed.List(ctx, "audits/")
+	if err != nil || !slices.Equal(paths, []string{"audits/user:1/2025-01-15.json"}) {
+		t.Errorf("expected the path without the prefix, got %v, %v", paths, err)
+	}
+	if shared, _ := storage.List(ctx, "audits/"); len(shared) != 0 {
+		t.Errorf("expected nothing in the shared namespace, got %v", shared)
+	}
+	if info, err := prefixed.Stat(ctx, "audits/user:1/2025-01-15.json"); err != nil || info.Size != 2 {
+		t.Errorf("unexpected stat %+v, %v", info, err)
+	}
+}
+
+func TestTenantPrefix(t *testing.T) {
+	conf := cfg.StorageConfig{
+		TenantBuckets:  map[string]string{"acme": "acme-audits", "globex": "globex-audits"},
+		TenantPrefixes: map[string]string{"globex": "auditory/", "initech": "initech/"},
+	}
+
+	tests := []struct {
+		name     string
+		tenant   string
+		expected string
+	}{
+		{name: "success - own bucket without prefix", tenant: "acme", expected: ""},
+		{name: "success - own bucket with prefix", tenant: "globex", expected: "auditory/"},
+		{name: "success - shared bucket with prefix", tenant: "initech", expected: "initech/"},
+		{name: "success - shared bucket by default", tenant: "umbrella", expected: "tenants/umbrella/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if prefix := TenantPrefix(conf, tt.tenant); prefix != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, prefix)
+			}
+		})
+	}
+}
+
+func TestTenantArchives_Manifest(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalDirStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	acme := NewArchiveStore(NewPrefixedStorage(storage, "tenants/acme/")).WithRetention(30)
+	if err := acme.RecordManifest(ctx, day, []ArchivedObject{{Key: "user:1", Date: "2025-01-15"}}); err != nil {
+		t.Fatalf("failed to record manifest: %v", err)
+	}
+	archives := NewTenantArchives(NewArchiveStore(storage)).Add("acme", acme)
+
+	if manifest, err := archives.Manifest(ctxkey.SetTenant(ctx, "acme"), day); err != nil || len(manifest.Objects) != 1 {
+		t.Errorf("expected the manifest of acme, got %+v, %v", manifest, err)
+	}
+	if _, err := archives.Manifest(ctx, day); !errors.Is(err, ErrManifestNotFound) {
+		t.Errorf("expected the shared archive to have no manifest, got %v", err)
+	}
+	if _, err := archives.Manifest(ctxkey.SetTenant(ctx, "globex"), day); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("expected ErrUnknownTenant, got %v", err)
+	}
+}
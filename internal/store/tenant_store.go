@@ -0,0 +1,84 @@
+package store
+
+//go:generate mockgen -source=tenant_store.go -destination=mocks/mock_tenant_store.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/cfg"
+)
+
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+type TenantAuditStore interface {
+	AuditStore
+	PressureGauge
+}
+
+// TenantStore routes each audit to the store of its tenant, audits without a
+// tenant to the shared store, and sheds them while that store is under
+// pressure, so one tenant filling its quota does not throttle the others.
+type TenantStore struct {
+	shared  TenantAuditStore
+	tenants map[string]TenantAuditStore
+}
+
+func NewTenantStore(shared TenantAuditStore) *TenantStore {
+	return &TenantStore{
+		shared:  shared,
+		tenants: make(map[string]TenantAuditStore),
+	}
+}
+
+func (ts *TenantStore) Add(tenant string, store TenantAuditStore) *TenantStore {
+	ts.tenants[tenant] = store
+	return ts
+}
+
+func (ts *TenantStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	target := ts.shared
+	if tenant := input.Metadata.Tenant; tenant != "" {
+		store, ok := ts.tenants[tenant]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownTenant, tenant)
+		}
+		target = store
+	}
+
+	if err := target.Pressure(); err != nil {
+		return err
+	}
+
+	return target.Upsert(ctx, input)
+}
+
+// TenantQuota is the quota of tenant: quota with its own MaxBytes when one is
+// configured, spilling to a directory of its own.
+func TenantQuota(quota cfg.QuotaConfig, tenants cfg.TenantConfig, tenant string) cfg.QuotaConfig {
+	if maxBytes, ok := tenants.MaxBytes[tenant]; ok {
+		quota.MaxBytes = maxBytes
+	}
+	quota.SpillDir = filepath.Join(quota.SpillDir, "tenants", tenant)
+	return quota
+}
+
+// OpenTenantStores opens the local store of each tenant of storage next to
+// shared, bounded by its TenantQuota.
+func OpenTenantStores(shared *DataFileStore, storage cfg.StorageConfig, quota cfg.QuotaConfig, tenants cfg.TenantConfig) (map[string]*DataFileStore, error) {
+	stores := make(map[string]*DataFileStore, len(storage.Tenants))
+	for _, tenant := range storage.Tenants {
+		dfs, err := shared.ForTenant(tenant)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+		if dfs, err = dfs.WithQuota(TenantQuota(quota, tenants, tenant)); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+		stores[tenant] = dfs
+	}
+	return stores, nil
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/store/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTenantStore_Upsert(t *testing.T) {
+	eventAt := time.Now()
+	tenantAudit := func(tenant string) audit.DataAudit {
+		input := quotaAudit("user:a", "req-1", eventAt)
+		input.Metadata.Tenant = tenant
+		return input
+	}
+
+	tests := []struct {
+		name          string
+		input         audit.DataAudit
+		setupMocks    func(shared, acme *mocks.MockTenantAuditStore)
+		expectedError error
+	}{
+		{
+			name:  "success - audit without tenant reaches the shared store",
+			input: tenantAudit(""),
+			setupMocks: func(shared, acme *mocks.MockTenantAuditStore) {
+				shared.EXPECT().Pressure().Return(nil)
+				shared.EXPECT().Upsert(gomock.Any(), tenantAudit("")).Return(nil)
+			},
+		},
+		{
+			name:  "success - audit reaches the store of its tenant",
+			input: tenantAudit("acme"),
+			setupMocks: func(shared, acme *mocks.MockTenantAuditStore) {
+				acme.EXPECT().Pressure().Return(nil)
+				acme.EXPECT().Upsert(gomock.Any(), tenantAudit("acme")).Return(nil)
+			},
+		},
+		{
+			name:  "error - tenant store under pressure",
+			input: tenantAudit("acme"),
+			setupMocks: func(shared, acme *mocks.MockTenantAuditStore) {
+				acme.EXPECT().Pressure().Return(ErrBackpressure)
+			},
+			expectedError: ErrBackpressure,
+		},
+		{
+			name:          "error - unknown tenant",
+			input:         tenantAudit("globex"),
+			setupMocks:    func(shared, acme *mocks.MockTenantAuditStore) {},
+			expectedError: ErrUnknownTenant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			shared := mocks.NewMockTenantAuditStore(ctrl)
+			acme := mocks.NewMockTenantAuditStore(ctrl)
+			tt.setupMocks(shared, acme)
+
+			err := NewTenantStore(shared).Add("acme", acme).Upsert(context.Background(), tt.input)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestOpenTenantStores(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	shared, err := NewDataFileStore().WithDir("tmp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quota := cfg.QuotaConfig{MaxBytes: 1000, Policy: QuotaSpill, SpillDir: t.TempDir()}
+	tenants := cfg.TenantConfig{MaxBytes: map[string]int64{"acme": 10}}
+
+	stores, err := OpenTenantStores(shared, cfg.StorageConfig{Tenants: []string{"acme", "globex"}}, quota, tenants)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := stores["acme"].Dir(); got != TenantDir("tmp", "acme") {
+		t.Errorf("expected the store of acme in its tenant directory, got %s", got)
+	}
+	if got := stores["acme"].quota.conf; got.MaxBytes != 10 || got.SpillDir != filepath.Join(quota.SpillDir, "tenants", "acme") {
+		t.Errorf("expected the quota of acme, got %+v", got)
+	}
+	if got := stores["globex"].quota.conf.MaxBytes; got != 1000 {
+		t.Errorf("expected globex to keep the shared quota, got %d", got)
+	}
+
+	if _, err := OpenTenantStores(shared, cfg.StorageConfig{Tenants: []string{"../acme"}}, quota, tenants); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("expected ErrInvalidTenant, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+const (
+	defaultHistorySize = 1024
+	defaultBufferSize  = 256
+)
+
+type Event struct {
+	ID    uint64          `json:"id"`
+	Audit audit.DataAudit `json:"audit"`
+}
+
+// Filter selects the audits of a subscription. Tenant is always matched, so
+// a subscriber only sees the audits of its own tenant, or the shared ones.
+type Filter struct {
+	Tenant    string
+	Key       string
+	EventName string
+}
+
+func (f Filter) Match(input audit.DataAudit) bool {
+	if f.Tenant != input.Metadata.Tenant {
+		return false
+	}
+	if f.Key != "" && f.Key != input.Metadata.Key {
+		return false
+	}
+	if f.EventName != "" && f.EventName != input.Metadata.EventName {
+		return false
+	}
+	return true
+}
+
+// Subscription delivers matching events on C. When the subscriber cannot keep
+// up and its buffer fills, the broker closes C and sets Lagged instead of
+// blocking publishers; the client is expected to reconnect with the last id
+// it received.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter Filter
+	lagged bool
+	gap    bool
+}
+
+func (s *Subscription) Lagged() bool {
+	return s.lagged
+}
+
+// Gap reports that events after the resume point are no longer retained, or
+// that the resume point comes from before a restart: the backlog is not all
+// the client missed and it should resync, e.g. from the archive.
+func (s *Subscription) Gap() bool {
+	return s.gap
+}
+
+// Broker fans audits out to live subscribers and keeps a bounded history so
+// reconnecting clients can resume from a Last-Event-ID.
+type Broker struct {
+	mu          sync.Mutex
+	nextID      uint64
+	history     []Event
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+}
+
+func NewBroker(historySize, bufferSize int) *Broker {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	return &Broker{
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (b *Broker) Publish(input audit.DataAudit) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := Event{ID: b.nextID, Audit: input}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Match(input) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			sub.lagged = true
+			b.closeInternal(sub)
+		}
+	}
+
+	return event
+}
+
+// Subscribe registers a subscriber and returns the retained events after
+// lastEventID that match the filter. Both happen under the same lock so no
+// event falls between the backlog and the live feed.
+func (b *Broker) Subscribe(filter Filter, lastEventID uint64) (*Subscription, []Event) {
+	ch := make(chan Event, b.bufferSize)
+	sub := &Subscription{C: ch, ch: ch, filter: filter}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []Event
+	if lastEventID > 0 {
+		// ids restart with the process and the history keeps the last ones
+		sub.gap = lastEventID > b.nextID || (len(b.history) > 0 && b.history[0].ID > lastEventID+1)
+		for _, event := range b.history {
+			if event.ID > lastEventID && filter.Match(event.Audit) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	b.subscribers[sub] = struct{}{}
+	return sub, backlog
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeInternal(sub)
+}
+
+// closeInternal removes the subscriber without acquiring lock (for internal use when lock is already held)
+func (b *Broker) closeInternal(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
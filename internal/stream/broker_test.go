@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+func newTestAudit(key, eventName string) audit.DataAudit {
+	return audit.DataAudit{Metadata: audit.MetadataAudit{Key: key, EventName: eventName}}
+}
+
+func TestBroker_Filter(t *testing.T) {
+	broker := NewBroker(0, 0)
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []uint64
+	}{
+		{name: "success - no filter receives everything", filter: Filter{}, expected: []uint64{1, 2, 3}},
+		{name: "success - by key", filter: Filter{Key: "user:1"}, expected: []uint64{1, 2}},
+		{name: "success - by key and event", filter: Filter{Key: "user:1", EventName: "user.deleted"}, expected: []uint64{2}},
+		{name: "success - by tenant", filter: Filter{Tenant: "acme"}, expected: []uint64{4}},
+	}
+
+	subs := make([]*Subscription, len(tests))
+	for i, tt := range tests {
+		subs[i], _ = broker.Subscribe(tt.filter, 0)
+	}
+
+	broker.Publish(newTestAudit("user:1", "user.created"))
+	broker.Publish(newTestAudit("user:1", "user.deleted"))
+	broker.Publish(newTestAudit("user:2", "user.created"))
+	tenantAudit := newTestAudit("user:1", "user.created")
+	tenantAudit.Metadata.Tenant = "acme"
+	broker.Publish(tenantAudit)
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker.Unsubscribe(subs[i])
+
+			var received []uint64
+			for event := range subs[i].C {
+				received = append(received, event.ID)
+			}
+
+			if len(received) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, received)
+			}
+			for j := range received {
+				if received[j] != tt.expected[j] {
+					t.Errorf("expected %v, got %v", tt.expected, received)
+				}
+			}
+		})
+	}
+}
+
+func TestBroker_Resume(t *testing.T) {
+	broker := NewBroker(3, 0)
+	for range 5 {
+		broker.Publish(newTestAudit("user:1", "user.updated"))
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID uint64
+		expected    []uint64
+		expectedGap bool
+	}{
+		{name: "success - no resume point, no backlog", lastEventID: 0, expected: nil},
+		{name: "success - resume inside history", lastEventID: 3, expected: []uint64{4, 5}},
+		{name: "success - resume just before the history", lastEventID: 2, expected: []uint64{3, 4, 5}},
+		{name: "success - resume older than history returns what is kept with a gap", lastEventID: 1, expected: []uint64{3, 4, 5}, expectedGap: true},
+		{name: "success - up to date", lastEventID: 5, expected: nil},
+		{name: "success - resume point from before a restart is a gap", lastEventID: 9, expected: nil, expectedGap: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog := broker.Subscribe(Filter{}, tt.lastEventID)
+			defer broker.Unsubscribe(sub)
+
+			if sub.Gap() != tt.expectedGap {
+				t.Errorf("expected gap %v, got %v", tt.expectedGap, sub.Gap())
+			}
+
+			if len(backlog) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, backlog)
+			}
+			for i, event := range backlog {
+				if event.ID != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, backlog)
+				}
+			}
+		})
+	}
+}
+
+func TestBroker_SlowSubscriberIsDisconnected(t *testing.T) {
+	broker := NewBroker(0, 2)
+	slow, _ := broker.Subscribe(Filter{}, 0)
+	fast, _ := broker.Subscribe(Filter{}, 0)
+
+	for range 3 {
+		broker.Publish(newTestAudit("user:1", "user.updated"))
+		<-fast.C
+	}
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+
+	if received != 2 {
+		t.Errorf("expected buffered events before disconnect, got %d", received)
+	}
+	if !slow.Lagged() {
+		t.Errorf("expected slow subscriber to be flagged as lagged")
+	}
+	if fast.Lagged() {
+		t.Errorf("expected fast subscriber to stay connected")
+	}
+
+	broker.Unsubscribe(fast)
+}
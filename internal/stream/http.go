@@ -0,0 +1,178 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+const heartbeatPeriod = 15 * time.Second
+
+// ParseRequest reads the filter and the resume point. Last-Event-ID is the
+// header sent by EventSource on reconnect; last_event_id is accepted as a
+// query parameter for WebSocket clients. The tenant is the one resolved for
+// the request.
+func ParseRequest(r *http.Request) (Filter, uint64, error) {
+	tenant, _ := ctxkey.Tenant(r.Context())
+	filter := Filter{
+		Tenant:    tenant,
+		Key:       r.URL.Query().Get("key"),
+		EventName: r.URL.Query().Get("event_name"),
+	}
+
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw == "" {
+		return filter, 0, nil
+	}
+
+	lastEventID, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return Filter{}, 0, fmt.Errorf("invalid last event id: %s", raw)
+	}
+
+	return filter, lastEventID, nil
+}
+
+func ServeSSE(broker *Broker, w http.ResponseWriter, r *http.Request) {
+	filter, lastEventID, err := ParseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// streams outlive the server write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub, backlog := broker.Subscribe(filter, lastEventID)
+	defer broker.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if sub.Gap() {
+		if _, err := fmt.Fprint(w, "event: reset\ndata: events after Last-Event-ID are no longer retained, resync\n\n"); err != nil {
+			return
+		}
+	}
+	for _, event := range backlog {
+		if err := writeSSE(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.C:
+			if !ok {
+				if sub.Lagged() {
+					_, _ = fmt.Fprint(w, "event: lagged\ndata: subscriber too slow, reconnect with Last-Event-ID\n\n")
+					_ = rc.Flush()
+				}
+				return
+			}
+			if err := writeSSE(w, event); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event Event) error {
+	payload, err := json.Marshal(event.Audit)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: audit\ndata: %s\n\n", event.ID, payload)
+	return err
+}
+
+// Reset is the WebSocket message sent ahead of the backlog when the stream
+// resumes with a gap, see Subscription.Gap.
+type Reset struct {
+	Reset       bool   `json:"reset"`
+	LastEventID uint64 `json:"last_event_id"`
+}
+
+func ServeWebSocket(broker *Broker, w http.ResponseWriter, r *http.Request) {
+	filter, lastEventID, err := ParseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	sub, backlog := broker.Subscribe(filter, lastEventID)
+	defer broker.Unsubscribe(sub)
+
+	// the stream is one way, reading only handles pings and the close frame
+	ctx := conn.CloseRead(r.Context())
+
+	if sub.Gap() {
+		if err := wsjson.Write(ctx, conn, Reset{Reset: true, LastEventID: lastEventID}); err != nil {
+			return
+		}
+	}
+	for _, event := range backlog {
+		if err := writeWebSocket(ctx, conn, event); err != nil {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				if sub.Lagged() {
+					_ = conn.Close(websocket.StatusTryAgainLater, "subscriber too slow, reconnect with last_event_id")
+				}
+				return
+			}
+			if err := writeWebSocket(ctx, conn, event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeWebSocket(ctx context.Context, conn *websocket.Conn, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return wsjson.Write(ctx, conn, event)
+}
@@ -0,0 +1,164 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/ctxkey"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		header      string
+		tenant      string
+		expected    Filter
+		expectedID  uint64
+		expectedErr bool
+	}{
+		{name: "success - filters", url: "/?key=user:1&event_name=user.deleted", expected: Filter{Key: "user:1", EventName: "user.deleted"}},
+		{name: "success - header resume", url: "/", header: "42", expectedID: 42},
+		{name: "success - query resume", url: "/?last_event_id=7", expectedID: 7},
+		{name: "success - tenant of the request", url: "/?key=user:1", tenant: "acme", expected: Filter{Tenant: "acme", Key: "user:1"}},
+		{name: "error - invalid id", url: "/?last_event_id=abc", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			if tt.tenant != "" {
+				req = req.WithContext(ctxkey.SetTenant(req.Context(), tt.tenant))
+			}
+
+			filter, lastEventID, err := ParseRequest(req)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if filter != tt.expected || lastEventID != tt.expectedID {
+				t.Errorf("expected %+v/%d, got %+v/%d", tt.expected, tt.expectedID, filter, lastEventID)
+			}
+		})
+	}
+}
+
+func TestServeSSE(t *testing.T) {
+	broker := NewBroker(0, 0)
+	broker.Publish(newTestAudit("user:1", "user.created"))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeSSE(broker, w, r)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// without Last-Event-ID only events published after connecting are sent
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?key=user:1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected event stream, got %q", ct)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		broker.Publish(newTestAudit("user:2", "user.created"))
+		broker.Publish(newTestAudit("user:1", "user.deleted"))
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if lines[0] != "id: 3" || lines[1] != "event: audit" || !strings.Contains(lines[2], `"event_name":"user.deleted"`) {
+		t.Errorf("unexpected event: %v", lines)
+	}
+}
+
+func TestServeWebSocket(t *testing.T) {
+	broker := NewBroker(0, 0)
+	broker.Publish(newTestAudit("user:1", "user.created"))
+	broker.Publish(newTestAudit("user:1", "user.updated"))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWebSocket(broker, w, r)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"?key=user:1&last_event_id=1", nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.CloseNow()
+
+	var event Event
+	if err := wsjson.Read(ctx, conn, &event); err != nil {
+		t.Fatalf("failed to read backlog: %v", err)
+	}
+	if event.ID != 2 || event.Audit.Metadata.EventName != "user.updated" {
+		t.Errorf("expected resumed event 2, got %+v", event)
+	}
+
+	broker.Publish(newTestAudit("user:1", "user.deleted"))
+	if err := wsjson.Read(ctx, conn, &event); err != nil {
+		t.Fatalf("failed to read live event: %v", err)
+	}
+	if event.ID != 3 {
+		t.Errorf("expected live event 3, got %+v", event)
+	}
+
+	// a resume point the history no longer covers is announced first
+	gapped := NewBroker(1, 0)
+	gapped.Publish(newTestAudit("user:1", "user.created"))
+	gapped.Publish(newTestAudit("user:1", "user.updated"))
+	gapped.Publish(newTestAudit("user:1", "user.deleted"))
+
+	gappedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWebSocket(gapped, w, r)
+	}))
+	defer gappedServer.Close()
+
+	conn, _, err = websocket.Dial(ctx, "ws"+strings.TrimPrefix(gappedServer.URL, "http")+"?key=user:1&last_event_id=1", nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.CloseNow()
+
+	var reset Reset
+	if err := wsjson.Read(ctx, conn, &reset); err != nil {
+		t.Fatalf("failed to read reset: %v", err)
+	}
+	if !reset.Reset || reset.LastEventID != 1 {
+		t.Errorf("expected a reset from event 1, got %+v", reset)
+	}
+	if err := wsjson.Read(ctx, conn, &event); err != nil || event.ID != 3 {
+		t.Errorf("expected the retained event 3 after the reset, got %+v %v", event, err)
+	}
+}
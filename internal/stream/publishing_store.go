@@ -0,0 +1,39 @@
+package stream
+
+//go:generate mockgen -source=publishing_store.go -destination=mocks/mock_publishing_store.go -package=mocks
+
+import (
+	"context"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+type AuditStore interface {
+	Upsert(ctx context.Context, input audit.DataAudit) error
+}
+
+type Publisher interface {
+	Publish(input audit.DataAudit) Event
+}
+
+// PublishingStore publishes every audit the wrapped store accepted.
+type PublishingStore struct {
+	store     AuditStore
+	publisher Publisher
+}
+
+func NewPublishingStore(store AuditStore, publisher Publisher) *PublishingStore {
+	return &PublishingStore{
+		store:     store,
+		publisher: publisher,
+	}
+}
+
+func (ps *PublishingStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	if err := ps.store.Upsert(ctx, input); err != nil {
+		return err
+	}
+
+	ps.publisher.Publish(input)
+	return nil
+}
@@ -0,0 +1,53 @@
+package stream_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/stream"
+	"github.com/IsaacDSC/auditory/internal/stream/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublishingStore_Upsert(t *testing.T) {
+	input := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:1", EventName: "user.created"}}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(store *mocks.MockAuditStore, publisher *mocks.MockPublisher)
+		expectedError bool
+	}{
+		{
+			name: "success - accepted audit is published",
+			setupMocks: func(store *mocks.MockAuditStore, publisher *mocks.MockPublisher) {
+				store.EXPECT().Upsert(gomock.Any(), input).Return(nil)
+				publisher.EXPECT().Publish(input).Return(stream.Event{ID: 1, Audit: input})
+			},
+		},
+		{
+			name: "error - rejected audit is not published",
+			setupMocks: func(store *mocks.MockAuditStore, publisher *mocks.MockPublisher) {
+				store.EXPECT().Upsert(gomock.Any(), input).Return(errors.New("write failed"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mocks.NewMockAuditStore(ctrl)
+			publisher := mocks.NewMockPublisher(ctrl)
+			tt.setupMocks(store, publisher)
+
+			err := stream.NewPublishingStore(store, publisher).Upsert(context.Background(), input)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
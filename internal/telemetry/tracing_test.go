@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExtractAndIDs(t *testing.T) {
+	tests := []struct {
+		name            string
+		traceparent     string
+		expectedTraceID string
+		expectedSpanID  string
+	}{
+		{
+			name:            "success - parses a valid traceparent",
+			traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedSpanID:  "00f067aa0ba902b7",
+		},
+		{
+			name:        "error - malformed traceparent is ignored",
+			traceparent: "00-4bf92f3577b34da6-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "error - zero trace id is ignored",
+			traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name: "error - missing header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.traceparent != "" {
+				header.Set("traceparent", tt.traceparent)
+			}
+
+			traceID, spanID := IDs(Extract(context.Background(), header))
+			if traceID != tt.expectedTraceID || spanID != tt.expectedSpanID {
+				t.Errorf("expected %q/%q, got %q/%q", tt.expectedTraceID, tt.expectedSpanID, traceID, spanID)
+			}
+		})
+	}
+}
+
+func TestStartContinuesRemoteTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx, span := Start(Extract(context.Background(), header), "audit.ingest")
+	traceID, spanID := IDs(ctx)
+	End(span, errors.New("disk full"))
+
+	if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the remote trace id, got %s", traceID)
+	}
+	if spanID == "00f067aa0ba902b7" {
+		t.Error("expected a child span id")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("expected remote parent, got %s", spans[0].Parent.SpanID())
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected error status, got %v", spans[0].Status.Code)
+	}
+}
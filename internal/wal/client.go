@@ -0,0 +1,70 @@
+package wal
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/telemetry"
+)
+
+// SecretHeader carries the WAL secret on audits forwarded to the leader.
+const SecretHeader = "X-WAL-Secret"
+
+// Client forwards audits to the leader through POST /wal/audits.
+type Client struct {
+	peers  map[string]string
+	secret string
+	http   *http.Client
+}
+
+func NewClient(peers map[string]string, secret string, httpClient *http.Client) *Client {
+	trimmed := make(map[string]string, len(peers))
+	for node, url := range peers {
+		trimmed[node] = strings.TrimSuffix(url, "/")
+	}
+	return &Client{peers: trimmed, secret: secret, http: httpClient}
+}
+
+// Authorized reports whether r carries the WAL secret.
+func Authorized(r *http.Request, secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretHeader)), []byte(secret)) == 1
+}
+
+func (c *Client) Forward(ctx context.Context, node string, input audit.DataAudit) error {
+	url, ok := c.peers[node]
+	if !ok {
+		return fmt.Errorf("unknown node %s", node)
+	}
+
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/wal/audits", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SecretHeader, c.secret)
+	telemetry.Inject(ctx, req.Header)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach node %s: %w", node, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("node %s answered status %d: %s", node, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
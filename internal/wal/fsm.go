@@ -0,0 +1,149 @@
+package wal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	OpAudit    = "audit"
+	OpRemove   = "remove"
+	OpArchived = "archived"
+)
+
+// LocalStore is the node's copy of the not yet archived audits, tmp/.
+type LocalStore interface {
+	Keys(ctx context.Context) ([]string, error)
+	Take(ctx context.Context, key store.Key) (store.Data, error)
+	Merge(ctx context.Context, key store.Key, data store.Data) error
+	GetAll(ctx context.Context) (map[string]store.Data, error)
+	Remove(ctx context.Context, key store.Key, sealed store.Data) error
+}
+
+// Command is one entry of the log. The leader fixes the day an audit belongs
+// to, so every replica files it under the same date, and the exact events it
+// archived, so every replica removes the same ones.
+type Command struct {
+	Op     string           `json:"op"`
+	Date   store.Date       `json:"date,omitempty"`
+	Audit  *audit.DataAudit `json:"audit,omitempty"`
+	Key    store.Key        `json:"key,omitempty"`
+	Sealed store.Data       `json:"sealed,omitempty"`
+}
+
+// FSM applies committed entries to the local store. Applying an audit twice,
+// as happens when the log is replayed on restart, is a no-op because Merge
+// skips events already present.
+type FSM struct {
+	store    LocalStore
+	archived chan struct{}
+}
+
+func NewFSM(localStore LocalStore) *FSM {
+	return &FSM{store: localStore, archived: make(chan struct{}, 1)}
+}
+
+// Archived fires after an OpArchived entry was applied: everything before it
+// is in the bucket and the log up to it can be compacted into a snapshot.
+func (f *FSM) Archived() <-chan struct{} {
+	return f.archived
+}
+
+func (f *FSM) Apply(log *raft.Log) any {
+	var cmd Command
+	if err := json.Unmarshal(log.Data, &cmd); err != nil {
+		return fmt.Errorf("failed to decode wal entry %d: %w", log.Index, err)
+	}
+
+	ctx := context.Background()
+	switch cmd.Op {
+	case OpAudit:
+		if cmd.Audit == nil {
+			return fmt.Errorf("wal entry %d has no audit", log.Index)
+		}
+		key := store.Key(cmd.Audit.Metadata.Key)
+		if err := f.store.Merge(ctx, key, store.Data{cmd.Date: {*cmd.Audit}}); err != nil {
+			logger.Error("failed to apply audit", "index", log.Index, "key", key, "error", err)
+			return err
+		}
+	case OpRemove:
+		if err := f.store.Remove(ctx, cmd.Key, cmd.Sealed); err != nil {
+			logger.Error("failed to remove archived audits", "index", log.Index, "key", cmd.Key, "error", err)
+			return err
+		}
+	case OpArchived:
+		select {
+		case f.archived <- struct{}{}:
+		default:
+		}
+	default:
+		return fmt.Errorf("unknown wal operation %q", cmd.Op)
+	}
+
+	return nil
+}
+
+// Snapshot captures the audits not archived yet; it runs on the FSM goroutine,
+// so reading the store here sees exactly the applied prefix of the log.
+func (f *FSM) Snapshot() (raft.FSMSnapshot, error) {
+	data, err := f.store.GetAll(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local store: %w", err)
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return &snapshot{payload: payload}, nil
+}
+
+// Restore replaces the local store with the snapshot, used when a replica
+// fell too far behind or lost its disk.
+func (f *FSM) Restore(reader io.ReadCloser) error {
+	defer reader.Close()
+
+	var data map[string]store.Data
+	if err := json.NewDecoder(reader).Decode(&data); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	ctx := context.Background()
+	keys, err := f.store.Keys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list local keys: %w", err)
+	}
+	for _, key := range keys {
+		if _, err := f.store.Take(ctx, store.Key(key)); err != nil {
+			return fmt.Errorf("failed to clear key %s: %w", key, err)
+		}
+	}
+
+	for key, keyData := range data {
+		if err := f.store.Merge(ctx, store.Key(key), keyData); err != nil {
+			return fmt.Errorf("failed to restore key %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+type snapshot struct {
+	payload []byte
+}
+
+func (s *snapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := sink.Write(s.payload); err != nil {
+		_ = sink.Cancel()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return sink.Close()
+}
+
+func (s *snapshot) Release() {}
@@ -0,0 +1,388 @@
+package wal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+)
+
+// memLocalStore is the in-memory tmp/ of one test node; the real
+// DataFileStore writes to a fixed directory every node would share.
+type memLocalStore struct {
+	mu   sync.Mutex
+	data map[string]store.Data
+}
+
+func newMemLocalStore() *memLocalStore {
+	return &memLocalStore{data: make(map[string]store.Data)}
+}
+
+func (m *memLocalStore) Keys(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.data))
+	for key := range m.data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (m *memLocalStore) Take(ctx context.Context, key store.Key) (store.Data, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data := m.data[string(key)]
+	delete(m.data, string(key))
+	return data, nil
+}
+
+func (m *memLocalStore) Merge(ctx context.Context, key store.Key, data store.Data) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.data[string(key)]
+	if !ok {
+		existing = make(store.Data)
+		m.data[string(key)] = existing
+	}
+	for date, events := range data {
+		for _, event := range events {
+			if !containsEvent(existing[date], event) {
+				existing[date] = append(existing[date], event)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memLocalStore) GetAll(ctx context.Context) (map[string]store.Data, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	output := make(map[string]store.Data, len(m.data))
+	for key, data := range m.data {
+		output[key] = data
+	}
+	return output, nil
+}
+
+func (m *memLocalStore) Remove(ctx context.Context, key store.Key, sealed store.Data) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data := m.data[string(key)]
+	for date, events := range sealed {
+		var kept []audit.DataAudit
+		for _, event := range data[date] {
+			if !containsEvent(events, event) {
+				kept = append(kept, event)
+			}
+		}
+		if len(kept) == 0 {
+			delete(data, date)
+		} else {
+			data[date] = kept
+		}
+	}
+	if len(data) == 0 {
+		delete(m.data, string(key))
+	}
+	return nil
+}
+
+func (m *memLocalStore) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var total int
+	for _, data := range m.data {
+		for _, events := range data {
+			total += len(events)
+		}
+	}
+	return total
+}
+
+func containsEvent(events []audit.DataAudit, event audit.DataAudit) bool {
+	for _, existing := range events {
+		if existing.Metadata.RequestID == event.Metadata.RequestID && existing.Metadata.Key == event.Metadata.Key {
+			return true
+		}
+	}
+	return false
+}
+
+type testMember struct {
+	id        string
+	addr      raft.ServerAddress
+	node      *Node
+	transport *raft.InmemTransport
+	local     *memLocalStore
+	logs      *raft.InmemStore
+	snaps     *raft.InmemSnapshotStore
+	up        bool
+}
+
+// testCluster is an in-process raft group: in-memory transports, logs and
+// snapshots, one memLocalStore per node.
+type testCluster struct {
+	t       *testing.T
+	ctx     context.Context
+	members []*testMember
+}
+
+func newTestCluster(t *testing.T, size int) *testCluster {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tc := &testCluster{t: t, ctx: ctx}
+
+	var servers []raft.Server
+	for i := range size {
+		addr, transport := raft.NewInmemTransport("")
+		member := &testMember{
+			id:        fmt.Sprintf("node-%d", i),
+			addr:      addr,
+			transport: transport,
+			local:     newMemLocalStore(),
+			logs:      raft.NewInmemStore(),
+			snaps:     raft.NewInmemSnapshotStore(),
+		}
+		tc.members = append(tc.members, member)
+		servers = append(servers, raft.Server{ID: raft.ServerID(member.id), Address: addr})
+	}
+	tc.connect()
+
+	for _, member := range tc.members {
+		tc.start(member, servers)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		for _, member := range tc.members {
+			tc.stop(member)
+		}
+	})
+	return tc
+}
+
+func (tc *testCluster) connect() {
+	for _, from := range tc.members {
+		for _, to := range tc.members {
+			if from != to {
+				from.transport.Connect(to.addr, to.transport)
+			}
+		}
+	}
+}
+
+func (tc *testCluster) start(member *testMember, servers []raft.Server) {
+	tc.t.Helper()
+
+	conf := raftConfig(member.id, hclog.NewNullLogger())
+	conf.HeartbeatTimeout = 50 * time.Millisecond
+	conf.ElectionTimeout = 50 * time.Millisecond
+	conf.LeaderLeaseTimeout = 50 * time.Millisecond
+	conf.CommitTimeout = 5 * time.Millisecond
+	// compact everything, so a lagging node needs the snapshot
+	conf.TrailingLogs = 0
+
+	node, err := newNode(conf, NewFSM(member.local), member.logs, member.logs, member.snaps, member.transport, servers, time.Second)
+	if err != nil {
+		tc.t.Fatalf("failed to start %s: %v", member.id, err)
+	}
+	member.node, member.up = node, true
+	go node.Run(tc.ctx)
+}
+
+func (tc *testCluster) stop(member *testMember) {
+	if !member.up {
+		return
+	}
+	member.up = false
+	_ = member.node.Shutdown()
+	_ = member.transport.Close()
+}
+
+// restart brings a stopped member back with its log and snapshots, on a new
+// transport as a restarted process would have.
+func (tc *testCluster) restart(member *testMember) {
+	tc.t.Helper()
+
+	_, member.transport = raft.NewInmemTransport(member.addr)
+	tc.connect()
+	tc.start(member, nil)
+}
+
+func (tc *testCluster) leader() *testMember {
+	tc.t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, member := range tc.members {
+			if member.up && member.node.raft.State() == raft.Leader {
+				return member
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tc.t.Fatal("no leader elected")
+	return nil
+}
+
+func (tc *testCluster) followers() []*testMember {
+	leader := tc.leader()
+	var followers []*testMember
+	for _, member := range tc.members {
+		if member != leader {
+			followers = append(followers, member)
+		}
+	}
+	return followers
+}
+
+func (tc *testCluster) eventually(desc string, cond func() bool) {
+	tc.t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tc.t.Fatalf("timed out waiting until %s", desc)
+}
+
+// memberForwarder delivers forwarded audits straight to the leader's store.
+type memberForwarder struct {
+	tc *testCluster
+}
+
+func (mf memberForwarder) Forward(ctx context.Context, node string, input audit.DataAudit) error {
+	for _, member := range mf.tc.members {
+		if member.id == node {
+			return NewReplicatedStore(member.node, nil).ApplyAudit(ctx, input)
+		}
+	}
+	return fmt.Errorf("unknown node %s", node)
+}
+
+func testAudit(requestID string) audit.DataAudit {
+	return audit.DataAudit{Metadata: audit.MetadataAudit{
+		Key:       "user:123",
+		EventName: "user.created",
+		RequestID: requestID,
+		EventAt:   time.Now(),
+	}}
+}
+
+func TestReplicatedStore_AcknowledgesAfterMajority(t *testing.T) {
+	tc := newTestCluster(t, 3)
+	leader := tc.leader()
+	leaderStore := NewReplicatedStore(leader.node, memberForwarder{tc: tc})
+	ctx := context.Background()
+
+	if err := leaderStore.Upsert(ctx, testAudit("req-1")); err != nil {
+		t.Fatalf("expected audit to be acknowledged, got %v", err)
+	}
+	tc.eventually("every node applied the audit", func() bool {
+		for _, member := range tc.members {
+			if member.local.count() != 1 {
+				return false
+			}
+		}
+		return true
+	})
+
+	followers := tc.followers()
+	tc.stop(followers[0])
+	if err := leaderStore.Upsert(ctx, testAudit("req-2")); err != nil {
+		t.Fatalf("expected audit to be acknowledged by 2 of 3 nodes, got %v", err)
+	}
+	if got := leader.local.count(); got != 2 {
+		t.Errorf("expected leader to hold 2 audits, got %d", got)
+	}
+
+	tc.stop(followers[1])
+	if err := leaderStore.Upsert(ctx, testAudit("req-3")); err == nil {
+		t.Fatal("expected audit without a majority to fail")
+	}
+}
+
+func TestReplicatedStore_FollowerForwardsToLeader(t *testing.T) {
+	tc := newTestCluster(t, 3)
+	follower := tc.followers()[0]
+
+	followerStore := NewReplicatedStore(follower.node, memberForwarder{tc: tc})
+	if err := followerStore.Upsert(context.Background(), testAudit("req-1")); err != nil {
+		t.Fatalf("expected forwarded audit to be acknowledged, got %v", err)
+	}
+
+	tc.eventually("every node applied the forwarded audit", func() bool {
+		for _, member := range tc.members {
+			if member.local.count() != 1 {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestNode_ArchivedCompactsAndRestoresLaggingNode(t *testing.T) {
+	tc := newTestCluster(t, 3)
+	leader := tc.leader()
+	lagging := tc.followers()[0]
+	ctx := context.Background()
+
+	tc.stop(lagging)
+	// stale data the snapshot must replace
+	_ = lagging.local.Merge(ctx, "user:old", store.Data{"2020-1-1": {testAudit("old")}})
+
+	leaderStore := NewReplicatedStore(leader.node, nil)
+	if err := leaderStore.Upsert(ctx, testAudit("req-1")); err != nil {
+		t.Fatalf("expected audit to be acknowledged, got %v", err)
+	}
+
+	fileStore := NewReplicatedFileStore(leader.node, leader.local)
+	sealed, err := fileStore.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("expected local audits, got %v", err)
+	}
+	for key, data := range sealed {
+		if err := fileStore.Remove(ctx, store.Key(key), data); err != nil {
+			t.Fatalf("expected removal to be replicated, got %v", err)
+		}
+	}
+	if err := leader.node.Archived(ctx); err != nil {
+		t.Fatalf("expected archival to be recorded, got %v", err)
+	}
+	if err := leaderStore.Upsert(ctx, testAudit("req-2")); err != nil {
+		t.Fatalf("expected audit to be acknowledged, got %v", err)
+	}
+
+	tc.eventually("the leader compacted its log", func() bool {
+		snapshots, err := leader.snaps.List()
+		return err == nil && len(snapshots) > 0
+	})
+	if got := leader.local.count(); got != 1 {
+		t.Errorf("expected only the audit after the archival on the leader, got %d", got)
+	}
+
+	tc.restart(lagging)
+	tc.eventually("the lagging node caught up from the snapshot", func() bool {
+		data, _ := lagging.local.GetAll(ctx)
+		_, stale := data["user:old"]
+		return !stale && lagging.local.count() == 1
+	})
+}
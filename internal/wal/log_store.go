@@ -0,0 +1,345 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+const recordHeaderSize = 8
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// FileLogStore is the raft log on disk: one append-only file of checksummed
+// records, fsynced before StoreLogs returns, so an entry counted towards the
+// quorum survives a crash. Only the offsets are kept in memory.
+//
+// Raft only deletes a prefix, after a snapshot, or a suffix, when a new
+// leader overwrites uncommitted entries; the prefix case rewrites the file
+// with the remaining entries.
+type FileLogStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	size    int64
+	first   uint64
+	offsets []int64
+}
+
+func NewFileLogStore(dir string) (*FileLogStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create wal dir: %w", err)
+	}
+
+	fls := &FileLogStore{path: filepath.Join(dir, "raft.log")}
+	if err := fls.open(); err != nil {
+		return nil, err
+	}
+	return fls, nil
+}
+
+func (fls *FileLogStore) FirstIndex() (uint64, error) {
+	fls.mu.Lock()
+	defer fls.mu.Unlock()
+
+	if len(fls.offsets) == 0 {
+		return 0, nil
+	}
+	return fls.first, nil
+}
+
+func (fls *FileLogStore) LastIndex() (uint64, error) {
+	fls.mu.Lock()
+	defer fls.mu.Unlock()
+	return fls.lastInternal(), nil
+}
+
+func (fls *FileLogStore) GetLog(index uint64, log *raft.Log) error {
+	fls.mu.Lock()
+	defer fls.mu.Unlock()
+
+	if len(fls.offsets) == 0 || index < fls.first || index > fls.lastInternal() {
+		return raft.ErrLogNotFound
+	}
+
+	offset := fls.offsets[index-fls.first]
+	header := make([]byte, recordHeaderSize)
+	if _, err := fls.file.ReadAt(header, offset); err != nil {
+		return fmt.Errorf("failed to read wal record %d: %w", index, err)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[0:4]))
+	if _, err := fls.file.ReadAt(payload, offset+recordHeaderSize); err != nil {
+		return fmt.Errorf("failed to read wal record %d: %w", index, err)
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:8]) {
+		return fmt.Errorf("wal record %d is corrupt", index)
+	}
+
+	return decodeLog(payload, log)
+}
+
+func (fls *FileLogStore) StoreLog(log *raft.Log) error {
+	return fls.StoreLogs([]*raft.Log{log})
+}
+
+func (fls *FileLogStore) StoreLogs(logs []*raft.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	fls.mu.Lock()
+	defer fls.mu.Unlock()
+
+	next := logs[0].Index
+	if len(fls.offsets) > 0 && next != fls.lastInternal()+1 {
+		return fmt.Errorf("wal entry %d does not follow %d", next, fls.lastInternal())
+	}
+
+	var buf []byte
+	offsets := make([]int64, 0, len(logs))
+	for i, log := range logs {
+		if log.Index != next+uint64(i) {
+			return fmt.Errorf("wal entries are not contiguous at %d", log.Index)
+		}
+		offsets = append(offsets, fls.size+int64(len(buf)))
+		buf = appendRecord(buf, encodeLog(log))
+	}
+
+	if _, err := fls.file.WriteAt(buf, fls.size); err != nil {
+		return fmt.Errorf("failed to write wal: %w", err)
+	}
+	if err := fls.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync wal: %w", err)
+	}
+
+	if len(fls.offsets) == 0 {
+		fls.first = next
+	}
+	fls.offsets = append(fls.offsets, offsets...)
+	fls.size += int64(len(buf))
+
+	return nil
+}
+
+func (fls *FileLogStore) DeleteRange(from, to uint64) error {
+	fls.mu.Lock()
+	defer fls.mu.Unlock()
+
+	if len(fls.offsets) == 0 {
+		return nil
+	}
+	last := fls.lastInternal()
+	from = max(from, fls.first)
+	to = min(to, last)
+	if from > to {
+		return nil
+	}
+
+	switch {
+	case from == fls.first && to == last:
+		return fls.truncateInternal(0, nil)
+	case to == last:
+		// uncommitted suffix replaced by a new leader
+		return fls.truncateInternal(fls.offsets[from-fls.first], fls.offsets[:from-fls.first])
+	case from == fls.first:
+		return fls.compactInternal(to + 1)
+	default:
+		return fmt.Errorf("cannot delete wal entries %d-%d from the middle of %d-%d", from, to, fls.first, last)
+	}
+}
+
+func (fls *FileLogStore) Close() error {
+	fls.mu.Lock()
+	defer fls.mu.Unlock()
+	return fls.file.Close()
+}
+
+// open loads the record offsets, dropping a torn record left by a crash in the
+// middle of a write; it was never acknowledged.
+func (fls *FileLogStore) open() error {
+	file, err := os.OpenFile(fls.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open wal: %w", err)
+	}
+	fls.file = file
+
+	reader := bufio.NewReader(io.NewSectionReader(file, 0, 1<<62))
+	header := make([]byte, recordHeaderSize)
+	var offset int64
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			break
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[0:4]))
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			break
+		}
+		if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:8]) {
+			break
+		}
+
+		var log raft.Log
+		if err := decodeLog(payload, &log); err != nil {
+			break
+		}
+		if len(fls.offsets) == 0 {
+			fls.first = log.Index
+		}
+		fls.offsets = append(fls.offsets, offset)
+		offset += recordHeaderSize + int64(len(payload))
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat wal: %w", err)
+	}
+	if offset < info.Size() {
+		logger.Warn("dropping torn wal tail", "path", fls.path, "bytes", info.Size()-offset)
+		if err := file.Truncate(offset); err != nil {
+			return fmt.Errorf("failed to truncate wal: %w", err)
+		}
+	}
+	fls.size = offset
+
+	return nil
+}
+
+// lastInternal returns the last index without acquiring lock (for internal use when lock is already held)
+func (fls *FileLogStore) lastInternal() uint64 {
+	if len(fls.offsets) == 0 {
+		return 0
+	}
+	return fls.first + uint64(len(fls.offsets)) - 1
+}
+
+// truncateInternal cuts the file at size without acquiring lock (for internal use when lock is already held)
+func (fls *FileLogStore) truncateInternal(size int64, offsets []int64) error {
+	if err := fls.file.Truncate(size); err != nil {
+		return fmt.Errorf("failed to truncate wal: %w", err)
+	}
+	if err := fls.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync wal: %w", err)
+	}
+	fls.size = size
+	fls.offsets = offsets
+	return nil
+}
+
+// compactInternal rewrites the file from index first on without acquiring
+// lock (for internal use when lock is already held)
+func (fls *FileLogStore) compactInternal(first uint64) error {
+	start := fls.offsets[first-fls.first]
+	tail := make([]byte, fls.size-start)
+	if _, err := fls.file.ReadAt(tail, start); err != nil {
+		return fmt.Errorf("failed to read wal: %w", err)
+	}
+
+	tmpPath := fls.path + ".tmp"
+	if err := writeFileSync(tmpPath, tail); err != nil {
+		return fmt.Errorf("failed to compact wal: %w", err)
+	}
+	if err := os.Rename(tmpPath, fls.path); err != nil {
+		return fmt.Errorf("failed to compact wal: %w", err)
+	}
+
+	file, err := os.OpenFile(fls.path, os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen wal: %w", err)
+	}
+	_ = fls.file.Close()
+	fls.file = file
+
+	offsets := make([]int64, 0, len(fls.offsets)-int(first-fls.first))
+	for _, offset := range fls.offsets[first-fls.first:] {
+		offsets = append(offsets, offset-start)
+	}
+	fls.offsets = offsets
+	fls.first = first
+	fls.size -= start
+
+	return nil
+}
+
+func appendRecord(buf, payload []byte) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(payload)))
+	buf = binary.BigEndian.AppendUint32(buf, crc32.Checksum(payload, crcTable))
+	return append(buf, payload...)
+}
+
+func encodeLog(log *raft.Log) []byte {
+	buf := make([]byte, 0, 33+len(log.Data)+len(log.Extensions))
+	buf = binary.BigEndian.AppendUint64(buf, log.Index)
+	buf = binary.BigEndian.AppendUint64(buf, log.Term)
+	buf = append(buf, byte(log.Type))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(log.AppendedAt.UnixNano()))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(log.Data)))
+	buf = append(buf, log.Data...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(log.Extensions)))
+	return append(buf, log.Extensions...)
+}
+
+var errShortRecord = errors.New("short wal record")
+
+func decodeLog(payload []byte, log *raft.Log) error {
+	if len(payload) < 29 {
+		return errShortRecord
+	}
+	log.Index = binary.BigEndian.Uint64(payload[0:8])
+	log.Term = binary.BigEndian.Uint64(payload[8:16])
+	log.Type = raft.LogType(payload[16])
+	log.AppendedAt = time.Unix(0, int64(binary.BigEndian.Uint64(payload[17:25])))
+
+	rest := payload[25:]
+	data, rest, err := readBytes(rest)
+	if err != nil {
+		return err
+	}
+	extensions, _, err := readBytes(rest)
+	if err != nil {
+		return err
+	}
+	log.Data = data
+	log.Extensions = extensions
+
+	return nil
+}
+
+func readBytes(buf []byte) ([]byte, []byte, error) {
+	if len(buf) < 4 {
+		return nil, nil, errShortRecord
+	}
+	n := binary.BigEndian.Uint32(buf[0:4])
+	if uint64(len(buf)-4) < uint64(n) {
+		return nil, nil, errShortRecord
+	}
+	if n == 0 {
+		return nil, buf[4:], nil
+	}
+	return buf[4 : 4+n], buf[4+n:], nil
+}
+
+func writeFileSync(path string, payload []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(payload); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
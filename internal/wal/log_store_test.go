@@ -0,0 +1,189 @@
+package wal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/raft"
+)
+
+func storeTestLogs(t *testing.T, fls *FileLogStore, from, to uint64) {
+	t.Helper()
+	var logs []*raft.Log
+	for i := from; i <= to; i++ {
+		logs = append(logs, &raft.Log{Index: i, Term: 1, Type: raft.LogCommand, Data: []byte{byte(i)}})
+	}
+	if err := fls.StoreLogs(logs); err != nil {
+		t.Fatalf("failed to store logs: %v", err)
+	}
+}
+
+func assertRange(t *testing.T, fls *FileLogStore, first, last uint64) {
+	t.Helper()
+	gotFirst, _ := fls.FirstIndex()
+	gotLast, _ := fls.LastIndex()
+	if gotFirst != first || gotLast != last {
+		t.Errorf("expected range %d-%d, got %d-%d", first, last, gotFirst, gotLast)
+	}
+	for i := first; i <= last && last > 0; i++ {
+		var log raft.Log
+		if err := fls.GetLog(i, &log); err != nil {
+			t.Fatalf("failed to get log %d: %v", i, err)
+		}
+		if log.Index != i || len(log.Data) != 1 || log.Data[0] != byte(i) {
+			t.Errorf("unexpected log %d: %+v", i, log)
+		}
+	}
+}
+
+func TestFileLogStore(t *testing.T) {
+	tests := []struct {
+		name          string
+		run           func(t *testing.T, dir string, fls *FileLogStore) *FileLogStore
+		expectedFirst uint64
+		expectedLast  uint64
+	}{
+		{
+			name: "success - entries survive a reopen",
+			run: func(t *testing.T, dir string, fls *FileLogStore) *FileLogStore {
+				storeTestLogs(t, fls, 1, 5)
+				fls.Close()
+				reopened, err := NewFileLogStore(dir)
+				if err != nil {
+					t.Fatalf("failed to reopen: %v", err)
+				}
+				return reopened
+			},
+			expectedFirst: 1,
+			expectedLast:  5,
+		},
+		{
+			name: "success - torn tail is dropped on reopen",
+			run: func(t *testing.T, dir string, fls *FileLogStore) *FileLogStore {
+				storeTestLogs(t, fls, 1, 3)
+				fls.Close()
+				file, _ := os.OpenFile(filepath.Join(dir, "raft.log"), os.O_WRONLY|os.O_APPEND, 0644)
+				_, _ = file.Write([]byte{0, 0, 0, 40, 1, 2})
+				file.Close()
+				reopened, err := NewFileLogStore(dir)
+				if err != nil {
+					t.Fatalf("failed to reopen: %v", err)
+				}
+				storeTestLogs(t, reopened, 4, 4)
+				return reopened
+			},
+			expectedFirst: 1,
+			expectedLast:  4,
+		},
+		{
+			name: "success - compacted prefix is gone after reopen",
+			run: func(t *testing.T, dir string, fls *FileLogStore) *FileLogStore {
+				storeTestLogs(t, fls, 1, 10)
+				if err := fls.DeleteRange(1, 6); err != nil {
+					t.Fatalf("failed to delete prefix: %v", err)
+				}
+				storeTestLogs(t, fls, 11, 12)
+				fls.Close()
+				reopened, err := NewFileLogStore(dir)
+				if err != nil {
+					t.Fatalf("failed to reopen: %v", err)
+				}
+				return reopened
+			},
+			expectedFirst: 7,
+			expectedLast:  12,
+		},
+		{
+			name: "success - overwritten suffix is replaced",
+			run: func(t *testing.T, dir string, fls *FileLogStore) *FileLogStore {
+				storeTestLogs(t, fls, 1, 5)
+				if err := fls.DeleteRange(4, 5); err != nil {
+					t.Fatalf("failed to delete suffix: %v", err)
+				}
+				storeTestLogs(t, fls, 4, 6)
+				return fls
+			},
+			expectedFirst: 1,
+			expectedLast:  6,
+		},
+		{
+			name: "success - deleting everything starts over at the next entry",
+			run: func(t *testing.T, dir string, fls *FileLogStore) *FileLogStore {
+				storeTestLogs(t, fls, 1, 5)
+				if err := fls.DeleteRange(1, 5); err != nil {
+					t.Fatalf("failed to delete all: %v", err)
+				}
+				storeTestLogs(t, fls, 20, 21)
+				return fls
+			},
+			expectedFirst: 20,
+			expectedLast:  21,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fls, err := NewFileLogStore(dir)
+			if err != nil {
+				t.Fatalf("failed to open: %v", err)
+			}
+
+			fls = tt.run(t, dir, fls)
+			defer fls.Close()
+
+			assertRange(t, fls, tt.expectedFirst, tt.expectedLast)
+		})
+	}
+}
+
+func TestFileLogStore_Errors(t *testing.T) {
+	fls, err := NewFileLogStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer fls.Close()
+	storeTestLogs(t, fls, 1, 5)
+
+	var log raft.Log
+	if err := fls.GetLog(9, &log); !errors.Is(err, raft.ErrLogNotFound) {
+		t.Errorf("expected ErrLogNotFound, got %v", err)
+	}
+	if err := fls.StoreLog(&raft.Log{Index: 8}); err == nil {
+		t.Error("expected a gap in the log to fail")
+	}
+	if err := fls.DeleteRange(2, 3); err == nil {
+		t.Error("expected deleting from the middle to fail")
+	}
+}
+
+func TestFileStableStore(t *testing.T) {
+	dir := t.TempDir()
+	fss, err := NewFileStableStore(dir)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+
+	if _, err := fss.GetUint64([]byte("CurrentTerm")); err == nil || err.Error() != "not found" {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if err := fss.SetUint64([]byte("CurrentTerm"), 7); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	if err := fss.Set([]byte("LastVoteCand"), []byte("node-b")); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+
+	reopened, err := NewFileStableStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	if term, err := reopened.GetUint64([]byte("CurrentTerm")); err != nil || term != 7 {
+		t.Errorf("expected term 7, got %d (%v)", term, err)
+	}
+	if cand, err := reopened.Get([]byte("LastVoteCand")); err != nil || string(cand) != "node-b" {
+		t.Errorf("expected node-b, got %q (%v)", cand, err)
+	}
+}
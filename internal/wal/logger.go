@@ -0,0 +1,5 @@
+package wal
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("wal")
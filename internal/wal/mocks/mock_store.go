@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: store.go
+//
+// Generated by this command:
+//
+//	mockgen -source=store.go -destination=mocks/mock_store.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	wal "github.com/IsaacDSC/auditory/internal/wal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReplicator is a mock of Replicator interface.
+type MockReplicator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplicatorMockRecorder
+	isgomock struct{}
+}
+
+// MockReplicatorMockRecorder is the mock recorder for MockReplicator.
+type MockReplicatorMockRecorder struct {
+	mock *MockReplicator
+}
+
+// NewMockReplicator creates a new mock instance.
+func NewMockReplicator(ctrl *gomock.Controller) *MockReplicator {
+	mock := &MockReplicator{ctrl: ctrl}
+	mock.recorder = &MockReplicatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplicator) EXPECT() *MockReplicatorMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockReplicator) Apply(ctx context.Context, cmd wal.Command) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, cmd)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockReplicatorMockRecorder) Apply(ctx, cmd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockReplicator)(nil).Apply), ctx, cmd)
+}
+
+// Leader mocks base method.
+func (m *MockReplicator) Leader() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Leader")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Leader indicates an expected call of Leader.
+func (mr *MockReplicatorMockRecorder) Leader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Leader", reflect.TypeOf((*MockReplicator)(nil).Leader))
+}
+
+// MockForwarder is a mock of Forwarder interface.
+type MockForwarder struct {
+	ctrl     *gomock.Controller
+	recorder *MockForwarderMockRecorder
+	isgomock struct{}
+}
+
+// MockForwarderMockRecorder is the mock recorder for MockForwarder.
+type MockForwarderMockRecorder struct {
+	mock *MockForwarder
+}
+
+// NewMockForwarder creates a new mock instance.
+func NewMockForwarder(ctrl *gomock.Controller) *MockForwarder {
+	mock := &MockForwarder{ctrl: ctrl}
+	mock.recorder = &MockForwarderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockForwarder) EXPECT() *MockForwarderMockRecorder {
+	return m.recorder
+}
+
+// Forward mocks base method.
+func (m *MockForwarder) Forward(ctx context.Context, node string, input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forward", ctx, node, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Forward indicates an expected call of Forward.
+func (mr *MockForwarderMockRecorder) Forward(ctx, node, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockForwarder)(nil).Forward), ctx, node, input)
+}
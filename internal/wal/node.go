@@ -0,0 +1,219 @@
+package wal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/cfg"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var ErrNoLeader = errors.New("wal has no leader")
+
+// Node is this replica's member of the raft group replicating the log.
+type Node struct {
+	id      string
+	raft    *raft.Raft
+	fsm     *FSM
+	timeout time.Duration
+	closers []io.Closer
+}
+
+// NewNode opens the log in conf.Dir and joins the group made of this node and
+// conf.Peers. A node without state bootstraps the group; every node doing so
+// with the same members is safe, raft elects a single leader.
+func NewNode(conf cfg.WALConfig, fsm *FSM) (*Node, error) {
+	hlog := raftLogger()
+
+	logs, err := NewFileLogStore(conf.Dir)
+	if err != nil {
+		return nil, err
+	}
+	stable, err := NewFileStableStore(conf.Dir)
+	if err != nil {
+		logs.Close()
+		return nil, err
+	}
+	snaps, err := raft.NewFileSnapshotStoreWithLogger(conf.Dir, 2, hlog)
+	if err != nil {
+		logs.Close()
+		return nil, fmt.Errorf("failed to open wal snapshots: %w", err)
+	}
+
+	advertise := conf.AdvertiseAddr
+	if advertise == "" {
+		advertise = conf.BindAddr
+	}
+	advertiseAddr, err := net.ResolveTCPAddr("tcp", advertise)
+	if err != nil {
+		logs.Close()
+		return nil, fmt.Errorf("invalid wal advertise address %q: %w", advertise, err)
+	}
+	transport, err := raft.NewTCPTransportWithLogger(conf.BindAddr, advertiseAddr, 3, conf.ApplyTimeout, hlog)
+	if err != nil {
+		logs.Close()
+		return nil, fmt.Errorf("failed to listen for wal peers: %w", err)
+	}
+
+	servers := []raft.Server{{ID: raft.ServerID(conf.NodeID), Address: raft.ServerAddress(advertise)}}
+	for id, addr := range conf.Peers {
+		servers = append(servers, raft.Server{ID: raft.ServerID(id), Address: raft.ServerAddress(addr)})
+	}
+
+	node, err := newNode(raftConfig(conf.NodeID, hlog), fsm, logs, stable, snaps, transport, servers, conf.ApplyTimeout)
+	if err != nil {
+		transport.Close()
+		logs.Close()
+		return nil, err
+	}
+	node.closers = append(node.closers, transport, logs)
+
+	return node, nil
+}
+
+func newNode(raftConf *raft.Config, fsm *FSM, logs raft.LogStore, stable raft.StableStore, snaps raft.SnapshotStore, transport raft.Transport, servers []raft.Server, timeout time.Duration) (*Node, error) {
+	existing, err := raft.HasExistingState(logs, stable, snaps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wal state: %w", err)
+	}
+
+	r, err := raft.NewRaft(raftConf, fsm, logs, stable, snaps, transport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start raft: %w", err)
+	}
+
+	if !existing {
+		// the same order on every node, so they all bootstrap the same entry
+		sort.Slice(servers, func(i, j int) bool { return servers[i].ID < servers[j].ID })
+		if err := r.BootstrapCluster(raft.Configuration{Servers: servers}).Error(); err != nil && !errors.Is(err, raft.ErrCantBootstrap) {
+			_ = r.Shutdown().Error()
+			return nil, fmt.Errorf("failed to bootstrap wal group: %w", err)
+		}
+	}
+
+	return &Node{id: string(raftConf.LocalID), raft: r, fsm: fsm, timeout: timeout}, nil
+}
+
+// raftConfig turns off the snapshots raft takes on its own: the log is only
+// compacted once what it holds was archived, see Run.
+func raftConfig(id string, hlog hclog.Logger) *raft.Config {
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID(id)
+	conf.Logger = hlog
+	conf.SnapshotThreshold = math.MaxUint64
+	return conf
+}
+
+// raftLogger sends the raft library logs through the wal package logger.
+func raftLogger() hclog.Logger {
+	return hclog.FromStandardLogger(slog.NewLogLogger(logger.Handler(), slog.LevelInfo), &hclog.LoggerOptions{
+		Name:  "raft",
+		Level: hclog.Info,
+	})
+}
+
+// Apply proposes cmd and returns once a majority persisted it and this node
+// applied it. It fails with raft.ErrNotLeader on followers.
+func (n *Node) Apply(ctx context.Context, cmd Command) error {
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to marshal wal entry: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	defer prometheus.NewTimer(metrics.WALApplyDuration).ObserveDuration()
+
+	future := n.raft.Apply(payload, n.timeout)
+	done := make(chan error, 1)
+	go func() { done <- future.Error() }()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("wal entry not committed: %w", ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	}
+
+	if err, ok := future.Response().(error); ok && err != nil {
+		return err
+	}
+	return nil
+}
+
+// Archived records in the log that an archival run finished: the archived
+// events were already removed through OpRemove entries, so every replica can
+// compact its log.
+func (n *Node) Archived(ctx context.Context) error {
+	return n.Apply(ctx, Command{Op: OpArchived})
+}
+
+// Leader returns the id of the current leader, empty during an election.
+func (n *Node) Leader() string {
+	_, id := n.raft.LeaderWithID()
+	return string(id)
+}
+
+// Leading reports whether this node leads the group, with the raft term as
+// fencing token: it only grows from one leader to the next.
+func (n *Node) Leading() (uint64, bool) {
+	if n.raft.State() != raft.Leader {
+		return 0, false
+	}
+	term, err := strconv.ParseUint(n.raft.Stats()["term"], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return term, true
+}
+
+// Ping fails while the group has no leader and nothing can be acknowledged.
+func (n *Node) Ping(ctx context.Context) error {
+	if n.Leader() == "" {
+		return ErrNoLeader
+	}
+	return nil
+}
+
+// Run compacts the log into a snapshot every time an archival was applied.
+func (n *Node) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.fsm.Archived():
+			err := n.raft.Snapshot().Error()
+			switch {
+			case errors.Is(err, raft.ErrNothingNewToSnapshot):
+			case err != nil:
+				logger.ErrorContext(ctx, "failed to snapshot wal", "error", err)
+			default:
+				logger.InfoContext(ctx, "wal compacted after archival", "node", n.id)
+			}
+		}
+	}
+}
+
+func (n *Node) Shutdown() error {
+	err := n.raft.Shutdown().Error()
+	for _, closer := range n.closers {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package wal
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrKeyNotFound is matched by message inside raft, which expects exactly
+// "not found" for a stable key that was never set.
+var ErrKeyNotFound = errors.New("not found")
+
+// FileStableStore keeps the raft term and vote in {dir}/stable.json. They
+// change on elections only, so the whole file is rewritten and fsynced on
+// every Set.
+type FileStableStore struct {
+	mu     sync.Mutex
+	path   string
+	values map[string][]byte
+}
+
+func NewFileStableStore(dir string) (*FileStableStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create wal dir: %w", err)
+	}
+
+	fss := &FileStableStore{path: filepath.Join(dir, "stable.json"), values: make(map[string][]byte)}
+
+	payload, err := os.ReadFile(fss.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fss, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raft state: %w", err)
+	}
+	if err := json.Unmarshal(payload, &fss.values); err != nil {
+		return nil, fmt.Errorf("failed to decode raft state: %w", err)
+	}
+
+	return fss, nil
+}
+
+func (fss *FileStableStore) Set(key []byte, val []byte) error {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	fss.values[string(key)] = append([]byte(nil), val...)
+
+	payload, err := json.Marshal(fss.values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal raft state: %w", err)
+	}
+
+	tmpPath := fss.path + ".tmp"
+	if err := writeFileSync(tmpPath, payload); err != nil {
+		return fmt.Errorf("failed to write raft state: %w", err)
+	}
+	if err := os.Rename(tmpPath, fss.path); err != nil {
+		return fmt.Errorf("failed to write raft state: %w", err)
+	}
+
+	return nil
+}
+
+func (fss *FileStableStore) Get(key []byte) ([]byte, error) {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	val, ok := fss.values[string(key)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return val, nil
+}
+
+func (fss *FileStableStore) SetUint64(key []byte, val uint64) error {
+	return fss.Set(key, binary.BigEndian.AppendUint64(nil, val))
+}
+
+func (fss *FileStableStore) GetUint64(key []byte) (uint64, error) {
+	val, err := fss.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(val) != 8 {
+		return 0, fmt.Errorf("raft state %s is not a uint64", key)
+	}
+	return binary.BigEndian.Uint64(val), nil
+}
@@ -0,0 +1,89 @@
+package wal
+
+//go:generate mockgen -source=store.go -destination=mocks/mock_store.go -package=mocks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/metrics"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"github.com/hashicorp/raft"
+)
+
+type Replicator interface {
+	Apply(ctx context.Context, cmd Command) error
+	Leader() string
+}
+
+type Forwarder interface {
+	Forward(ctx context.Context, node string, input audit.DataAudit) error
+}
+
+// ReplicatedStore acknowledges an audit only after a majority of the nodes
+// persisted it in the log; the FSM then writes it to every local store.
+// Followers forward the audit to the leader, the only node that can append.
+type ReplicatedStore struct {
+	replicator Replicator
+	forwarder  Forwarder
+}
+
+func NewReplicatedStore(replicator Replicator, forwarder Forwarder) *ReplicatedStore {
+	return &ReplicatedStore{replicator: replicator, forwarder: forwarder}
+}
+
+func (rs *ReplicatedStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	err := rs.ApplyAudit(ctx, input)
+	if !errors.Is(err, raft.ErrNotLeader) {
+		return err
+	}
+
+	leader := rs.replicator.Leader()
+	if leader == "" {
+		return ErrNoLeader
+	}
+	metrics.WALForwarded.Inc()
+	return rs.forwarder.Forward(ctx, leader, input)
+}
+
+// ApplyAudit appends input to the log without forwarding, for audits a
+// follower already forwarded here.
+func (rs *ReplicatedStore) ApplyAudit(ctx context.Context, input audit.DataAudit) error {
+	return rs.replicator.Apply(ctx, Command{Op: OpAudit, Date: store.NewDate(clock.Now()), Audit: &input})
+}
+
+// ReplicatedFileStore is what the archival reads from and deletes through
+// when the log is enabled: it reads the local copy, which holds everything
+// the leader acknowledged, and removes the archived events on every replica
+// through the log.
+type ReplicatedFileStore struct {
+	replicator Replicator
+	local      LocalStore
+}
+
+func NewReplicatedFileStore(replicator Replicator, local LocalStore) *ReplicatedFileStore {
+	return &ReplicatedFileStore{replicator: replicator, local: local}
+}
+
+func (rfs *ReplicatedFileStore) GetAll(ctx context.Context) (map[string]store.Data, error) {
+	return rfs.local.GetAll(ctx)
+}
+
+func (rfs *ReplicatedFileStore) Remove(ctx context.Context, key store.Key, sealed store.Data) error {
+	return rfs.replicator.Apply(ctx, Command{Op: OpRemove, Key: key, Sealed: sealed})
+}
+
+// Merge appends restored events to the log under the day they were filed on,
+// so every replica gets them back.
+func (rfs *ReplicatedFileStore) Merge(ctx context.Context, key store.Key, data store.Data) error {
+	for date, events := range data {
+		for _, event := range events {
+			if err := rfs.replicator.Apply(ctx, Command{Op: OpAudit, Date: date, Audit: &event}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package wal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/store"
+	"github.com/IsaacDSC/auditory/internal/wal"
+	"github.com/IsaacDSC/auditory/internal/wal/mocks"
+	"github.com/hashicorp/raft"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReplicatedStore_Upsert(t *testing.T) {
+	input := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:123", EventName: "user.created"}}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(replicator *mocks.MockReplicator, forwarder *mocks.MockForwarder)
+		expectedErr error
+	}{
+		{
+			name: "success - leader appends the audit",
+			setupMocks: func(replicator *mocks.MockReplicator, forwarder *mocks.MockForwarder) {
+				replicator.EXPECT().Apply(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, cmd wal.Command) error {
+					if cmd.Op != wal.OpAudit || cmd.Audit.Metadata.Key != "user:123" || cmd.Date == "" {
+						t.Errorf("unexpected command %+v", cmd)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name: "success - follower forwards to the leader",
+			setupMocks: func(replicator *mocks.MockReplicator, forwarder *mocks.MockForwarder) {
+				replicator.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(raft.ErrNotLeader)
+				replicator.EXPECT().Leader().Return("node-b")
+				forwarder.EXPECT().Forward(gomock.Any(), "node-b", input).Return(nil)
+			},
+		},
+		{
+			name: "error - no leader during an election",
+			setupMocks: func(replicator *mocks.MockReplicator, forwarder *mocks.MockForwarder) {
+				replicator.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(raft.ErrNotLeader)
+				replicator.EXPECT().Leader().Return("")
+			},
+			expectedErr: wal.ErrNoLeader,
+		},
+		{
+			name: "error - forward fails",
+			setupMocks: func(replicator *mocks.MockReplicator, forwarder *mocks.MockForwarder) {
+				replicator.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(raft.ErrNotLeader)
+				replicator.EXPECT().Leader().Return("node-b")
+				forwarder.EXPECT().Forward(gomock.Any(), "node-b", input).Return(errUnreachable)
+			},
+			expectedErr: errUnreachable,
+		},
+		{
+			name: "error - no majority is not forwarded",
+			setupMocks: func(replicator *mocks.MockReplicator, forwarder *mocks.MockForwarder) {
+				replicator.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(raft.ErrLeadershipLost)
+			},
+			expectedErr: raft.ErrLeadershipLost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			replicator := mocks.NewMockReplicator(ctrl)
+			forwarder := mocks.NewMockForwarder(ctrl)
+			tt.setupMocks(replicator, forwarder)
+
+			err := wal.NewReplicatedStore(replicator, forwarder).Upsert(context.Background(), input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestReplicatedFileStore_Merge(t *testing.T) {
+	data := store.Data{"2025-1-14": {
+		{Metadata: audit.MetadataAudit{Key: "user:123", RequestID: "req-1"}},
+		{Metadata: audit.MetadataAudit{Key: "user:123", RequestID: "req-2"}},
+	}}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(replicator *mocks.MockReplicator)
+		expectedErr error
+	}{
+		{
+			name: "success - appends each event under its day",
+			setupMocks: func(replicator *mocks.MockReplicator) {
+				replicator.EXPECT().Apply(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, cmd wal.Command) error {
+					if cmd.Op != wal.OpAudit || cmd.Date != "2025-1-14" || cmd.Audit.Metadata.Key != "user:123" {
+						t.Errorf("unexpected command %+v", cmd)
+					}
+					return nil
+				}).Times(2)
+			},
+		},
+		{
+			name: "error - stops at the first failed entry",
+			setupMocks: func(replicator *mocks.MockReplicator) {
+				replicator.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(raft.ErrLeadershipLost)
+			},
+			expectedErr: raft.ErrLeadershipLost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			replicator := mocks.NewMockReplicator(ctrl)
+			tt.setupMocks(replicator)
+
+			err := wal.NewReplicatedFileStore(replicator, nil).Merge(context.Background(), "user:123", data)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+var errUnreachable = errors.New("node unreachable")
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+// DeadLetter is a delivery that exhausted its attempts or never got queued.
+type DeadLetter struct {
+	DeliveryID     string          `json:"delivery_id"`
+	SubscriptionID string          `json:"subscription_id"`
+	URL            string          `json:"url"`
+	Audit          audit.DataAudit `json:"audit"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+// FileDeadLetterStore appends dead letters to dead_letters.jsonl.
+type FileDeadLetterStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileDeadLetterStore(dir string) (*FileDeadLetterStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create webhook directory: %w", err)
+	}
+
+	return &FileDeadLetterStore{path: filepath.Join(dir, "dead_letters.jsonl")}, nil
+}
+
+func (fds *FileDeadLetterStore) Add(letter DeadLetter) error {
+	payload, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	fds.mu.Lock()
+	defer fds.mu.Unlock()
+
+	file, err := os.OpenFile(fds.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead letters: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (fds *FileDeadLetterStore) List() ([]DeadLetter, error) {
+	fds.mu.Lock()
+	defer fds.mu.Unlock()
+
+	file, err := os.Open(fds.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []DeadLetter{}, nil
+		}
+		return nil, fmt.Errorf("failed to open dead letters: %w", err)
+	}
+	defer file.Close()
+
+	output := []DeadLetter{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			// a torn last line after a crash is skipped
+			continue
+		}
+		output = append(output, letter)
+	}
+
+	return output, scanner.Err()
+}
@@ -0,0 +1,237 @@
+package webhook
+
+//go:generate mockgen -source=dispatcher.go -destination=mocks/mock_dispatcher.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+const (
+	SignatureHeader = "X-Auditory-Signature"
+	TimestampHeader = "X-Auditory-Timestamp"
+	DeliveryHeader  = "X-Auditory-Delivery"
+
+	maxBackoff = 5 * time.Minute
+)
+
+type DeadLetterStore interface {
+	Add(letter DeadLetter) error
+}
+
+// Payload is the JSON body posted to subscribers. BurstCount is set when the
+// rule has a burst and reports how many audits fell within the window.
+type Payload struct {
+	DeliveryID     string          `json:"delivery_id"`
+	SubscriptionID string          `json:"subscription_id"`
+	BurstCount     int             `json:"burst_count,omitempty"`
+	Audit          audit.DataAudit `json:"audit"`
+}
+
+type delivery struct {
+	payload Payload
+	url     string
+	secret  string
+}
+
+// DispatcherConfig bounds the deliveries; AllowedHosts may be reached even
+// on the internal addresses EgressPolicy refuses.
+type DispatcherConfig struct {
+	QueueSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	Timeout      time.Duration
+	AllowedHosts []string
+}
+
+// Dispatcher delivers payloads from a bounded queue with a pool of workers.
+// Failed deliveries are retried with exponential backoff and land in the
+// dead-letter store once MaxAttempts is reached or the queue is full.
+type Dispatcher struct {
+	queue       chan delivery
+	client      *http.Client
+	deadLetters DeadLetterStore
+	maxAttempts int
+	baseBackoff time.Duration
+	wg          sync.WaitGroup
+}
+
+func NewDispatcher(conf DispatcherConfig, deadLetters DeadLetterStore) *Dispatcher {
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = 1000
+	}
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = 5
+	}
+	if conf.BaseBackoff <= 0 {
+		conf.BaseBackoff = time.Second
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = 10 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = NewEgressPolicy(conf.AllowedHosts).DialContext(conf.Timeout)
+
+	return &Dispatcher{
+		queue:       make(chan delivery, conf.QueueSize),
+		client:      &http.Client{Timeout: conf.Timeout, Transport: transport},
+		deadLetters: deadLetters,
+		maxAttempts: conf.MaxAttempts,
+		baseBackoff: conf.BaseBackoff,
+	}
+}
+
+// Start runs the workers until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context, workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	for range workers {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-d.queue:
+					d.deliver(ctx, item)
+				}
+			}
+		}()
+	}
+}
+
+// Wait blocks until all workers returned after their context was cancelled.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Backlog returns how many deliveries wait for a worker.
+func (d *Dispatcher) Backlog() int {
+	return len(d.queue)
+}
+
+func (d *Dispatcher) Enqueue(sub Subscription, input audit.DataAudit, burstCount int) {
+	item := delivery{
+		payload: Payload{
+			DeliveryID:     randomHex(16),
+			SubscriptionID: sub.ID,
+			BurstCount:     burstCount,
+			Audit:          input,
+		},
+		url:    sub.URL,
+		secret: sub.Secret,
+	}
+
+	select {
+	case d.queue <- item:
+	default:
+		d.deadLetter(item, 0, fmt.Errorf("delivery queue is full"))
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, item delivery) {
+	body, err := json.Marshal(item.payload)
+	if err != nil {
+		d.deadLetter(item, 0, fmt.Errorf("failed to marshal payload: %w", err))
+		return
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if lastErr = d.post(ctx, item, body); lastErr == nil {
+			return
+		}
+
+		if attempt == d.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			d.deadLetter(item, attempt, lastErr)
+			return
+		case <-time.After(d.backoff(attempt)):
+		}
+	}
+
+	d.deadLetter(item, d.maxAttempts, lastErr)
+}
+
+func (d *Dispatcher) post(ctx context.Context, item delivery, body []byte) error {
+	timestamp := strconv.FormatInt(clock.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, item.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(DeliveryHeader, item.payload.DeliveryID)
+	req.Header.Set(SignatureHeader, Sign(item.secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// backoff doubles the base delay per attempt, capped, with up to 20% jitter
+// so retries from many deliveries do not line up.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.baseBackoff << (attempt - 1)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay + time.Duration(rand.Int64N(int64(delay)/5+1))
+}
+
+func (d *Dispatcher) deadLetter(item delivery, attempts int, cause error) {
+	letter := DeadLetter{
+		DeliveryID:     item.payload.DeliveryID,
+		SubscriptionID: item.payload.SubscriptionID,
+		URL:            item.url,
+		Audit:          item.payload.Audit,
+		Attempts:       attempts,
+		LastError:      cause.Error(),
+		FailedAt:       clock.Now(),
+	}
+
+	if err := d.deadLetters.Add(letter); err != nil {
+		logger.Error("failed to store dead letter", "delivery_id", letter.DeliveryID, "error", err)
+	}
+}
+
+// Sign returns the signature header value: sha256= followed by the hex
+// HMAC-SHA256 of "{timestamp}.{body}" keyed with the subscription secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
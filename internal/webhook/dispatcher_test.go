@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+type memDeadLetters struct {
+	letters chan DeadLetter
+}
+
+func (m *memDeadLetters) Add(letter DeadLetter) error {
+	m.letters <- letter
+	return nil
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcher := NewDispatcher(DispatcherConfig{AllowedHosts: []string{"127.0.0.1"}}, &memDeadLetters{letters: make(chan DeadLetter, 1)})
+	dispatcher.Start(ctx, 1)
+
+	sub := Subscription{ID: "sub1", URL: server.URL, Secret: "s3cret"}
+	input := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42", EventName: "user.deleted"}}
+	dispatcher.Enqueue(sub, input, 0)
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	body := <-bodies
+
+	expected := Sign("s3cret", req.Header.Get(TimestampHeader), body)
+	if got := req.Header.Get(SignatureHeader); got != expected {
+		t.Errorf("expected signature %s, got %s", expected, got)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.SubscriptionID != "sub1" || payload.Audit.Metadata.EventName != "user.deleted" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if req.Header.Get(DeliveryHeader) != payload.DeliveryID {
+		t.Errorf("delivery header does not match payload id")
+	}
+}
+
+func TestDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deadLetters := &memDeadLetters{letters: make(chan DeadLetter, 1)}
+	dispatcher := NewDispatcher(DispatcherConfig{MaxAttempts: 3, BaseBackoff: time.Millisecond, AllowedHosts: []string{"127.0.0.1"}}, deadLetters)
+	dispatcher.Start(ctx, 1)
+
+	dispatcher.Enqueue(Subscription{ID: "sub1", URL: server.URL}, audit.DataAudit{}, 0)
+
+	select {
+	case letter := <-deadLetters.letters:
+		if letter.Attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", letter.Attempts)
+		}
+		if letter.SubscriptionID != "sub1" || letter.LastError == "" {
+			t.Errorf("unexpected dead letter: %+v", letter)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was not dead-lettered")
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestDispatcher_RefusesInternalAddresses(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deadLetters := &memDeadLetters{letters: make(chan DeadLetter, 1)}
+	dispatcher := NewDispatcher(DispatcherConfig{MaxAttempts: 1}, deadLetters)
+	dispatcher.Start(ctx, 1)
+
+	dispatcher.Enqueue(Subscription{ID: "sub1", URL: server.URL}, audit.DataAudit{}, 0)
+
+	select {
+	case letter := <-deadLetters.letters:
+		if !strings.Contains(letter.LastError, ErrForbiddenAddress.Error()) {
+			t.Errorf("expected the loopback address to be refused, got %+v", letter)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was not dead-lettered")
+	}
+
+	if got := calls.Load(); got != 0 {
+		t.Errorf("expected no call to reach the server, got %d", got)
+	}
+}
+
+func TestDispatcher_FullQueueDeadLetters(t *testing.T) {
+	deadLetters := &memDeadLetters{letters: make(chan DeadLetter, 1)}
+	dispatcher := NewDispatcher(DispatcherConfig{QueueSize: 1}, deadLetters)
+
+	// no workers: the second delivery finds the queue full
+	dispatcher.Enqueue(Subscription{ID: "sub1", URL: "http://localhost"}, audit.DataAudit{}, 0)
+	dispatcher.Enqueue(Subscription{ID: "sub2", URL: "http://localhost"}, audit.DataAudit{}, 0)
+
+	select {
+	case letter := <-deadLetters.letters:
+		if letter.SubscriptionID != "sub2" || letter.Attempts != 0 {
+			t.Errorf("unexpected dead letter: %+v", letter)
+		}
+	default:
+		t.Fatal("expected the overflowing delivery to be dead-lettered")
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	dispatcher := NewDispatcher(DispatcherConfig{BaseBackoff: time.Second}, nil)
+
+	for attempt, base := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 20: maxBackoff} {
+		got := dispatcher.backoff(attempt)
+		if got < base || got > base+base/5 {
+			t.Errorf("attempt %d: expected backoff in [%s, %s], got %s", attempt, base, base+base/5, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// EgressPolicy keeps webhooks away from the hosts around the plane: loopback,
+// private, link-local (e.g. the 169.254.169.254 metadata service) and
+// unspecified addresses are refused unless their host is allowed explicitly.
+type EgressPolicy struct {
+	allowed map[string]bool
+}
+
+func NewEgressPolicy(allowedHosts []string) *EgressPolicy {
+	ep := &EgressPolicy{allowed: make(map[string]bool, len(allowedHosts))}
+	for _, host := range allowedHosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			ep.allowed[host] = true
+		}
+	}
+	return ep
+}
+
+// CheckURL refuses a url whose host is an internal address or name. Names
+// resolving to an internal address are refused when dialing.
+func (ep *EgressPolicy) CheckURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if ep.allowed[host] {
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && forbiddenAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// DialContext dials like net.Dialer but refuses, after resolution, the
+// internal addresses of hosts not allowed.
+func (ep *EgressPolicy) DialContext(timeout time.Duration) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialer := &net.Dialer{Timeout: timeout}
+		if host, _, err := net.SplitHostPort(address); err != nil || !ep.allowed[strings.ToLower(host)] {
+			dialer.Control = func(_, resolved string, _ syscall.RawConn) error {
+				addrPort, err := netip.ParseAddrPort(resolved)
+				if err != nil {
+					return err
+				}
+				if forbiddenAddr(addrPort.Addr()) {
+					return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, address, addrPort.Addr())
+				}
+				return nil
+			}
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+}
+
+func forbiddenAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsUnspecified()
+}
@@ -0,0 +1,122 @@
+package webhook
+
+//go:generate mockgen -source=engine.go -destination=mocks/mock_engine.go -package=mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+type SubscriptionLister interface {
+	List() ([]Subscription, error)
+}
+
+type Notifier interface {
+	Enqueue(sub Subscription, input audit.DataAudit, burstCount int)
+}
+
+// Engine evaluates the rule of every subscription of the audit's tenant
+// against incoming audits. Plain rules notify on each match; burst rules keep
+// the match times of the last window per subscription and notify once the
+// count is reached.
+type Engine struct {
+	subs     SubscriptionLister
+	notifier Notifier
+
+	mu     sync.Mutex
+	bursts map[string][]time.Time
+}
+
+func NewEngine(subs SubscriptionLister, notifier Notifier) *Engine {
+	return &Engine{
+		subs:     subs,
+		notifier: notifier,
+		bursts:   make(map[string][]time.Time),
+	}
+}
+
+func (e *Engine) Evaluate(input audit.DataAudit) error {
+	subs, err := e.subs.List()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		if sub.Tenant != input.Metadata.Tenant || !sub.Rule.Match(input) {
+			continue
+		}
+
+		if sub.Rule.Burst == nil {
+			e.notifier.Enqueue(sub, input, 0)
+			continue
+		}
+
+		// the window slides on arrival time, since data-plane exchanges carry
+		// no event_at of their own
+		if count, fired := e.observe(sub.ID, *sub.Rule.Burst, clock.Now()); fired {
+			e.notifier.Enqueue(sub, input, count)
+		}
+	}
+
+	return nil
+}
+
+func (e *Engine) observe(subID string, burst Burst, at time.Time) (int, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	since := at.Add(-time.Duration(burst.Window))
+	seen := e.bursts[subID][:0]
+	for _, t := range e.bursts[subID] {
+		if t.After(since) {
+			seen = append(seen, t)
+		}
+	}
+	seen = append(seen, at)
+
+	if len(seen) < burst.Count {
+		e.bursts[subID] = seen
+		return 0, false
+	}
+
+	delete(e.bursts, subID)
+	return len(seen), true
+}
+
+type AuditStore interface {
+	Upsert(ctx context.Context, input audit.DataAudit) error
+}
+
+type Evaluator interface {
+	Evaluate(input audit.DataAudit) error
+}
+
+// NotifyingStore evaluates webhook rules for every audit the wrapped store
+// accepted. Rule errors never fail the upsert.
+type NotifyingStore struct {
+	store     AuditStore
+	evaluator Evaluator
+}
+
+func NewNotifyingStore(store AuditStore, evaluator Evaluator) *NotifyingStore {
+	return &NotifyingStore{
+		store:     store,
+		evaluator: evaluator,
+	}
+}
+
+func (ns *NotifyingStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	if err := ns.store.Upsert(ctx, input); err != nil {
+		return err
+	}
+
+	if err := ns.evaluator.Evaluate(input); err != nil {
+		logger.ErrorContext(ctx, "failed to evaluate rules", "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+	"github.com/IsaacDSC/auditory/internal/webhook"
+	"github.com/IsaacDSC/auditory/internal/webhook/mocks"
+	"github.com/IsaacDSC/auditory/pkg/clock"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEngine_Evaluate(t *testing.T) {
+	deleted := webhook.Subscription{ID: "deleted", Rule: webhook.Rule{
+		Conditions: []webhook.Condition{{Field: "event_name", Op: webhook.OpEq, Value: "user.deleted"}},
+	}}
+	acmeDeleted := webhook.Subscription{ID: "acme-deleted", Tenant: "acme", Rule: deleted.Rule}
+	errors5xx := webhook.Subscription{ID: "5xx", Rule: webhook.Rule{
+		Conditions: []webhook.Condition{{Field: "data.response.status_code", Op: webhook.OpGte, Value: "500"}},
+		Burst:      &webhook.Burst{Count: 3, Window: webhook.Duration(time.Minute)},
+	}}
+
+	// data-plane exchanges carry no event at, the window follows arrivals
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	failure := audit.DataAudit{
+		Metadata: audit.MetadataAudit{EventName: audit.HttpAuditEvent},
+		Data:     audit.HttpAudit{Response: audit.ResponseAudit{StatusCode: 502}},
+	}
+	defer func() {
+		clock.Now = func() time.Time { return time.Now().UTC() }
+	}()
+
+	tests := []struct {
+		name      string
+		inputs    []audit.DataAudit
+		arrivals  []time.Duration
+		setupMock func(m *mocks.MockNotifier)
+	}{
+		{
+			name:   "success - plain rule notifies on every match",
+			inputs: []audit.DataAudit{{Metadata: audit.MetadataAudit{EventName: "user.deleted"}}, {Metadata: audit.MetadataAudit{EventName: "user.created"}}},
+			setupMock: func(m *mocks.MockNotifier) {
+				m.EXPECT().Enqueue(deleted, gomock.Any(), 0).Times(1)
+			},
+		},
+		{
+			name:   "success - subscribers only get the audits of their tenant",
+			inputs: []audit.DataAudit{{Metadata: audit.MetadataAudit{Tenant: "acme", EventName: "user.deleted"}}, {Metadata: audit.MetadataAudit{Tenant: "globex", EventName: "user.deleted"}}},
+			setupMock: func(m *mocks.MockNotifier) {
+				m.EXPECT().Enqueue(acmeDeleted, gomock.Any(), 0).Times(1)
+			},
+		},
+		{
+			name:     "success - burst fires once count is reached within window",
+			inputs:   []audit.DataAudit{failure, failure, failure},
+			arrivals: []time.Duration{0, 10 * time.Second, 20 * time.Second},
+			setupMock: func(m *mocks.MockNotifier) {
+				m.EXPECT().Enqueue(errors5xx, gomock.Any(), 3).Times(1)
+			},
+		},
+		{
+			name:      "success - burst ignores matches outside window",
+			inputs:    []audit.DataAudit{failure, failure, failure},
+			arrivals:  []time.Duration{0, 2 * time.Minute, 4 * time.Minute},
+			setupMock: func(m *mocks.MockNotifier) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLister := mocks.NewMockSubscriptionLister(ctrl)
+			mockLister.EXPECT().List().Return([]webhook.Subscription{deleted, acmeDeleted, errors5xx}, nil).AnyTimes()
+			mockNotifier := mocks.NewMockNotifier(ctrl)
+			tt.setupMock(mockNotifier)
+
+			engine := webhook.NewEngine(mockLister, mockNotifier)
+			for i, input := range tt.inputs {
+				clock.SetNow(base)
+				if i < len(tt.arrivals) {
+					clock.SetNow(base.Add(tt.arrivals[i]))
+				}
+				if err := engine.Evaluate(input); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyingStore_Upsert(t *testing.T) {
+	input := audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42"}}
+
+	tests := []struct {
+		name      string
+		setupMock func(s *mocks.MockAuditStore, e *mocks.MockEvaluator)
+		wantErr   bool
+	}{
+		{
+			name: "success - evaluates after store",
+			setupMock: func(s *mocks.MockAuditStore, e *mocks.MockEvaluator) {
+				gomock.InOrder(
+					s.EXPECT().Upsert(gomock.Any(), input).Return(nil),
+					e.EXPECT().Evaluate(input).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success - evaluation error does not fail upsert",
+			setupMock: func(s *mocks.MockAuditStore, e *mocks.MockEvaluator) {
+				s.EXPECT().Upsert(gomock.Any(), input).Return(nil)
+				e.EXPECT().Evaluate(input).Return(errors.New("subscriptions unreadable"))
+			},
+		},
+		{
+			name: "error - store fails and nothing is evaluated",
+			setupMock: func(s *mocks.MockAuditStore, e *mocks.MockEvaluator) {
+				s.EXPECT().Upsert(gomock.Any(), input).Return(errors.New("disk full"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mocks.NewMockAuditStore(ctrl)
+			mockEvaluator := mocks.NewMockEvaluator(ctrl)
+			tt.setupMock(mockStore, mockEvaluator)
+
+			err := webhook.NewNotifyingStore(mockStore, mockEvaluator).Upsert(context.Background(), input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
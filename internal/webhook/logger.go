@@ -0,0 +1,5 @@
+package webhook
+
+import "github.com/IsaacDSC/auditory/internal/logging"
+
+var logger = logging.For("webhook")
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/webhook/dispatcher.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/webhook/dispatcher.go -destination=internal/webhook/mocks/mock_dispatcher.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	webhook "github.com/IsaacDSC/auditory/internal/webhook"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeadLetterStore is a mock of DeadLetterStore interface.
+type MockDeadLetterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterStoreMockRecorder is the mock recorder for MockDeadLetterStore.
+type MockDeadLetterStoreMockRecorder struct {
+	mock *MockDeadLetterStore
+}
+
+// NewMockDeadLetterStore creates a new mock instance.
+func NewMockDeadLetterStore(ctrl *gomock.Controller) *MockDeadLetterStore {
+	mock := &MockDeadLetterStore{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterStore) EXPECT() *MockDeadLetterStoreMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockDeadLetterStore) Add(letter webhook.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", letter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockDeadLetterStoreMockRecorder) Add(letter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockDeadLetterStore)(nil).Add), letter)
+}
@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/webhook/engine.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/webhook/engine.go -destination=internal/webhook/mocks/mock_engine.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/IsaacDSC/auditory/internal/audit"
+	webhook "github.com/IsaacDSC/auditory/internal/webhook"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSubscriptionLister is a mock of SubscriptionLister interface.
+type MockSubscriptionLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionListerMockRecorder
+	isgomock struct{}
+}
+
+// MockSubscriptionListerMockRecorder is the mock recorder for MockSubscriptionLister.
+type MockSubscriptionListerMockRecorder struct {
+	mock *MockSubscriptionLister
+}
+
+// NewMockSubscriptionLister creates a new mock instance.
+func NewMockSubscriptionLister(ctrl *gomock.Controller) *MockSubscriptionLister {
+	mock := &MockSubscriptionLister{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionLister) EXPECT() *MockSubscriptionListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockSubscriptionLister) List() ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSubscriptionListerMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSubscriptionLister)(nil).List))
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockNotifier) Enqueue(sub webhook.Subscription, input audit.DataAudit, burstCount int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Enqueue", sub, input, burstCount)
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockNotifierMockRecorder) Enqueue(sub, input, burstCount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockNotifier)(nil).Enqueue), sub, input, burstCount)
+}
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockAuditStore) Upsert(ctx context.Context, input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockAuditStoreMockRecorder) Upsert(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockAuditStore)(nil).Upsert), ctx, input)
+}
+
+// MockEvaluator is a mock of Evaluator interface.
+type MockEvaluator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEvaluatorMockRecorder
+	isgomock struct{}
+}
+
+// MockEvaluatorMockRecorder is the mock recorder for MockEvaluator.
+type MockEvaluatorMockRecorder struct {
+	mock *MockEvaluator
+}
+
+// NewMockEvaluator creates a new mock instance.
+func NewMockEvaluator(ctrl *gomock.Controller) *MockEvaluator {
+	mock := &MockEvaluator{ctrl: ctrl}
+	mock.recorder = &MockEvaluatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvaluator) EXPECT() *MockEvaluatorMockRecorder {
+	return m.recorder
+}
+
+// Evaluate mocks base method.
+func (m *MockEvaluator) Evaluate(input audit.DataAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evaluate", input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockEvaluatorMockRecorder) Evaluate(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockEvaluator)(nil).Evaluate), input)
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+const (
+	OpEq     = "eq"
+	OpNeq    = "neq"
+	OpGt     = "gt"
+	OpGte    = "gte"
+	OpLt     = "lt"
+	OpLte    = "lte"
+	OpPrefix = "prefix"
+	OpExists = "exists"
+)
+
+// Condition compares one field of the audit. Field is a metadata name (key,
+// event_name, request_id, correlation_id) or a dotted path into the data,
+// e.g. data.response.status_code.
+type Condition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value string `json:"value,omitempty"`
+}
+
+// Burst turns a rule into a rate trigger: it fires once Count matching audits
+// were seen within Window, then starts counting again.
+type Burst struct {
+	Count  int      `json:"count"`
+	Window Duration `json:"window"`
+}
+
+type Rule struct {
+	Conditions []Condition `json:"conditions"`
+	Burst      *Burst      `json:"burst,omitempty"`
+}
+
+func (r Rule) Validate() error {
+	if len(r.Conditions) == 0 {
+		return fmt.Errorf("rule requires at least one condition")
+	}
+
+	for _, c := range r.Conditions {
+		if c.Field == "" {
+			return fmt.Errorf("condition field is required")
+		}
+		switch c.Op {
+		case OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpPrefix, OpExists:
+		default:
+			return fmt.Errorf("unknown condition op: %s", c.Op)
+		}
+	}
+
+	if r.Burst != nil && (r.Burst.Count <= 0 || r.Burst.Window <= 0) {
+		return fmt.Errorf("burst requires positive count and window")
+	}
+
+	return nil
+}
+
+// Match reports whether every condition holds for the audit.
+func (r Rule) Match(input audit.DataAudit) bool {
+	var data any
+	for _, c := range r.Conditions {
+		var value string
+		var found bool
+
+		switch c.Field {
+		case "key":
+			value, found = input.Metadata.Key, true
+		case "event_name":
+			value, found = input.Metadata.EventName, true
+		case "request_id":
+			value, found = input.Metadata.RequestID, true
+		case "correlation_id":
+			value, found = input.Metadata.CorrelationID, true
+		default:
+			path, ok := strings.CutPrefix(c.Field, "data.")
+			if !ok {
+				return false
+			}
+			if data == nil {
+				data = normalize(input.Data)
+			}
+			value, found = lookup(data, strings.Split(path, "."))
+		}
+
+		if !c.holds(value, found) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Condition) holds(value string, found bool) bool {
+	if c.Op == OpExists {
+		return found
+	}
+	if !found {
+		return c.Op == OpNeq
+	}
+
+	switch c.Op {
+	case OpEq:
+		return value == c.Value
+	case OpNeq:
+		return value != c.Value
+	case OpPrefix:
+		return strings.HasPrefix(value, c.Value)
+	}
+
+	actual, err1 := strconv.ParseFloat(value, 64)
+	expected, err2 := strconv.ParseFloat(c.Value, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	switch c.Op {
+	case OpGt:
+		return actual > expected
+	case OpGte:
+		return actual >= expected
+	case OpLt:
+		return actual < expected
+	case OpLte:
+		return actual <= expected
+	}
+	return false
+}
+
+func lookup(data any, path []string) (string, bool) {
+	for _, segment := range path {
+		object, ok := data.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if data, ok = object[segment]; !ok {
+			return "", false
+		}
+	}
+
+	switch v := data.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case nil:
+		return "", false
+	default:
+		payload, _ := json.Marshal(v)
+		return string(payload), true
+	}
+}
+
+// normalize converts typed payloads (structs, typed maps) to plain JSON values.
+func normalize(data any) any {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+
+	var output any
+	_ = json.Unmarshal(payload, &output)
+	return output
+}
+
+// Duration reads and writes durations as strings such as "1m30s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(payload []byte) error {
+	var raw string
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+func TestRule_Match(t *testing.T) {
+	input := audit.DataAudit{
+		Metadata: audit.MetadataAudit{Key: "user:42", EventName: audit.HttpAuditEvent},
+		Data: audit.HttpAudit{
+			Request:  audit.RequestAudit{Method: "POST", Path: "/orders"},
+			Response: audit.ResponseAudit{StatusCode: 503},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions []Condition
+		expected   bool
+	}{
+		{
+			name:       "success - metadata equality",
+			conditions: []Condition{{Field: "event_name", Op: OpEq, Value: audit.HttpAuditEvent}},
+			expected:   true,
+		},
+		{
+			name: "success - 5xx status range on data path",
+			conditions: []Condition{
+				{Field: "data.response.status_code", Op: OpGte, Value: "500"},
+				{Field: "data.response.status_code", Op: OpLt, Value: "600"},
+			},
+			expected: true,
+		},
+		{
+			name:       "success - prefix on key",
+			conditions: []Condition{{Field: "key", Op: OpPrefix, Value: "user:"}},
+			expected:   true,
+		},
+		{
+			name:       "success - neq on missing field",
+			conditions: []Condition{{Field: "data.missing", Op: OpNeq, Value: "x"}},
+			expected:   true,
+		},
+		{
+			name:       "error - exists on missing field",
+			conditions: []Condition{{Field: "data.missing", Op: OpExists}},
+			expected:   false,
+		},
+		{
+			name: "error - one condition fails",
+			conditions: []Condition{
+				{Field: "event_name", Op: OpEq, Value: audit.HttpAuditEvent},
+				{Field: "data.request.method", Op: OpEq, Value: "GET"},
+			},
+			expected: false,
+		},
+		{
+			name:       "error - numeric op on text",
+			conditions: []Condition{{Field: "data.request.method", Op: OpGt, Value: "1"}},
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := Rule{Conditions: tt.conditions}
+			if got := rule.Match(input); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{
+			name: "success - valid burst rule",
+			rule: Rule{
+				Conditions: []Condition{{Field: "key", Op: OpExists}},
+				Burst:      &Burst{Count: 5, Window: Duration(time.Minute)},
+			},
+		},
+		{
+			name:    "error - no conditions",
+			rule:    Rule{},
+			wantErr: true,
+		},
+		{
+			name:    "error - unknown op",
+			rule:    Rule{Conditions: []Condition{{Field: "key", Op: "like"}}},
+			wantErr: true,
+		},
+		{
+			name: "error - burst without window",
+			rule: Rule{
+				Conditions: []Condition{{Field: "key", Op: OpExists}},
+				Burst:      &Burst{Count: 5},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	var burst Burst
+	if err := json.Unmarshal([]byte(`{"count":3,"window":"1m30s"}`), &burst); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if time.Duration(burst.Window) != 90*time.Second {
+		t.Errorf("expected 1m30s, got %s", time.Duration(burst.Window))
+	}
+
+	payload, _ := json.Marshal(burst)
+	if string(payload) != `{"count":3,"window":"1m30s"}` {
+		t.Errorf("unexpected encoding: %s", payload)
+	}
+}
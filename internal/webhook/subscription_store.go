@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/auditory/pkg/clock"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidSubscription  = errors.New("invalid subscription")
+)
+
+// Subscription receives the audits of its Tenant only; the empty tenant is the
+// shared namespace.
+type Subscription struct {
+	ID        string    `json:"id"`
+	Tenant    string    `json:"tenant,omitempty"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Rule      Rule      `json:"rule"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s Subscription) Validate() error {
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) url", ErrInvalidSubscription)
+	}
+	if err := s.Rule.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSubscription, err)
+	}
+	return nil
+}
+
+// FileSubscriptionStore keeps subscriptions in a single JSON file. The data
+// plane reads the same file, so List reloads it whenever it changed on disk.
+type FileSubscriptionStore struct {
+	path    string
+	egress  *EgressPolicy
+	mu      sync.RWMutex
+	subs    map[string]Subscription
+	modTime time.Time
+}
+
+func NewFileSubscriptionStore(dir string) (*FileSubscriptionStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create webhook directory: %w", err)
+	}
+
+	fss := &FileSubscriptionStore{
+		path:   filepath.Join(dir, "subscriptions.json"),
+		egress: NewEgressPolicy(nil),
+		subs:   make(map[string]Subscription),
+	}
+
+	if err := fss.reload(); err != nil {
+		return nil, err
+	}
+
+	return fss, nil
+}
+
+// WithAllowedHosts accepts urls on hosts refused by the EgressPolicy.
+func (fss *FileSubscriptionStore) WithAllowedHosts(hosts []string) *FileSubscriptionStore {
+	fss.egress = NewEgressPolicy(hosts)
+	return fss
+}
+
+func (fss *FileSubscriptionStore) validate(sub Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	if err := fss.egress.CheckURL(sub.URL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSubscription, err)
+	}
+	return nil
+}
+
+func (fss *FileSubscriptionStore) reload() error {
+	info, err := os.Stat(fss.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat subscriptions: %w", err)
+	}
+
+	fss.mu.RLock()
+	unchanged := info.ModTime().Equal(fss.modTime)
+	fss.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	payload, err := os.ReadFile(fss.path)
+	if err != nil {
+		return fmt.Errorf("failed to read subscriptions: %w", err)
+	}
+
+	subs := make(map[string]Subscription)
+	if err := json.Unmarshal(payload, &subs); err != nil {
+		return fmt.Errorf("failed to decode subscriptions: %w", err)
+	}
+
+	fss.mu.Lock()
+	fss.subs = subs
+	fss.modTime = info.ModTime()
+	fss.mu.Unlock()
+
+	return nil
+}
+
+// persist writes the subscriptions without acquiring lock (for internal use when lock is already held)
+func (fss *FileSubscriptionStore) persist() error {
+	payload, err := json.MarshalIndent(fss.subs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode subscriptions: %w", err)
+	}
+
+	tmpPath := fss.path + ".tmp"
+	if err := os.WriteFile(tmpPath, payload, 0600); err != nil {
+		return fmt.Errorf("failed to write subscriptions: %w", err)
+	}
+	if err := os.Rename(tmpPath, fss.path); err != nil {
+		return fmt.Errorf("failed to write subscriptions: %w", err)
+	}
+
+	if info, err := os.Stat(fss.path); err == nil {
+		fss.modTime = info.ModTime()
+	}
+
+	return nil
+}
+
+func (fss *FileSubscriptionStore) Create(sub Subscription) (Subscription, error) {
+	if err := fss.validate(sub); err != nil {
+		return Subscription{}, err
+	}
+
+	sub.ID = randomHex(8)
+	if sub.Secret == "" {
+		sub.Secret = randomHex(32)
+	}
+	sub.CreatedAt = clock.Now()
+	sub.UpdatedAt = sub.CreatedAt
+
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	fss.subs[sub.ID] = sub
+	if err := fss.persist(); err != nil {
+		delete(fss.subs, sub.ID)
+		return Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+func (fss *FileSubscriptionStore) Get(id string) (Subscription, error) {
+	if err := fss.reload(); err != nil {
+		return Subscription{}, err
+	}
+
+	fss.mu.RLock()
+	defer fss.mu.RUnlock()
+
+	sub, ok := fss.subs[id]
+	if !ok {
+		return Subscription{}, ErrSubscriptionNotFound
+	}
+
+	return sub, nil
+}
+
+func (fss *FileSubscriptionStore) List() ([]Subscription, error) {
+	if err := fss.reload(); err != nil {
+		return nil, err
+	}
+
+	fss.mu.RLock()
+	defer fss.mu.RUnlock()
+
+	output := make([]Subscription, 0, len(fss.subs))
+	for _, sub := range fss.subs {
+		output = append(output, sub)
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].CreatedAt.Before(output[j].CreatedAt)
+	})
+
+	return output, nil
+}
+
+// Update replaces url and rule; the secret is only rotated when a new one is given.
+func (fss *FileSubscriptionStore) Update(id string, sub Subscription) (Subscription, error) {
+	if err := fss.validate(sub); err != nil {
+		return Subscription{}, err
+	}
+
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	existing, ok := fss.subs[id]
+	if !ok {
+		return Subscription{}, ErrSubscriptionNotFound
+	}
+
+	existing.URL = sub.URL
+	existing.Rule = sub.Rule
+	if sub.Secret != "" {
+		existing.Secret = sub.Secret
+	}
+	existing.UpdatedAt = clock.Now()
+
+	previous := fss.subs[id]
+	fss.subs[id] = existing
+	if err := fss.persist(); err != nil {
+		fss.subs[id] = previous
+		return Subscription{}, err
+	}
+
+	return existing, nil
+}
+
+func (fss *FileSubscriptionStore) Delete(id string) error {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	previous, ok := fss.subs[id]
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+
+	delete(fss.subs, id)
+	if err := fss.persist(); err != nil {
+		fss.subs[id] = previous
+		return err
+	}
+
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
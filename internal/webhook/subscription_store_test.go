@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/auditory/internal/audit"
+)
+
+func validSubscription() Subscription {
+	return Subscription{
+		URL:  "https://hooks.example.com/audit",
+		Rule: Rule{Conditions: []Condition{{Field: "event_name", Op: OpEq, Value: "user.deleted"}}},
+	}
+}
+
+func TestFileSubscriptionStore_CRUD(t *testing.T) {
+	dir := t.TempDir()
+	fss, err := NewFileSubscriptionStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	created, err := fss.Create(validSubscription())
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	if created.ID == "" || created.Secret == "" {
+		t.Fatalf("expected generated id and secret, got %+v", created)
+	}
+
+	update := validSubscription()
+	update.URL = "https://hooks.example.com/v2"
+	updated, err := fss.Update(created.ID, update)
+	if err != nil {
+		t.Fatalf("failed to update subscription: %v", err)
+	}
+	if updated.URL != update.URL || updated.Secret != created.Secret {
+		t.Errorf("expected url updated and secret kept, got %+v", updated)
+	}
+
+	// a second store over the same directory sees the change, as the data plane does
+	other, err := NewFileSubscriptionStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	subs, err := other.List()
+	if err != nil || len(subs) != 1 || subs[0].URL != update.URL {
+		t.Fatalf("expected shared subscription, got %+v (%v)", subs, err)
+	}
+
+	if err := fss.Delete(created.ID); err != nil {
+		t.Fatalf("failed to delete subscription: %v", err)
+	}
+	if _, err := fss.Get(created.ID); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+	if err := fss.Delete(created.ID); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+}
+
+func TestFileSubscriptionStore_Reload(t *testing.T) {
+	dir := t.TempDir()
+	reader, _ := NewFileSubscriptionStore(dir)
+	writer, _ := NewFileSubscriptionStore(dir)
+
+	if _, err := writer.Create(validSubscription()); err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	// make sure the mtime moves even on coarse-grained filesystems
+	future := time.Now().Add(time.Second)
+	_ = os.Chtimes(filepath.Join(dir, "subscriptions.json"), future, future)
+
+	subs, err := reader.List()
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("expected reader to reload 1 subscription, got %d (%v)", len(subs), err)
+	}
+}
+
+func TestFileSubscriptionStore_Invalid(t *testing.T) {
+	fss, _ := NewFileSubscriptionStore(t.TempDir())
+
+	tests := []struct {
+		name string
+		sub  Subscription
+	}{
+		{name: "error - relative url", sub: Subscription{URL: "/hook", Rule: validSubscription().Rule}},
+		{name: "error - empty rule", sub: Subscription{URL: "https://hooks.example.com"}},
+		{name: "error - loopback url", sub: Subscription{URL: "http://127.0.0.1:8080/hook", Rule: validSubscription().Rule}},
+		{name: "error - localhost url", sub: Subscription{URL: "http://localhost/hook", Rule: validSubscription().Rule}},
+		{name: "error - metadata service url", sub: Subscription{URL: "http://169.254.169.254/latest/meta-data", Rule: validSubscription().Rule}},
+		{name: "error - private url", sub: Subscription{URL: "https://10.0.0.8/hook", Rule: validSubscription().Rule}},
+		{name: "error - mapped private url", sub: Subscription{URL: "https://[::ffff:192.168.0.1]/hook", Rule: validSubscription().Rule}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fss.Create(tt.sub); !errors.Is(err, ErrInvalidSubscription) {
+				t.Errorf("expected ErrInvalidSubscription, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFileSubscriptionStore_AllowedHosts(t *testing.T) {
+	fss, _ := NewFileSubscriptionStore(t.TempDir())
+	fss.WithAllowedHosts([]string{"10.0.0.8"})
+
+	sub := validSubscription()
+	sub.URL = "https://10.0.0.8/hook"
+	if _, err := fss.Create(sub); err != nil {
+		t.Errorf("expected an allowed host to be accepted, got %v", err)
+	}
+}
+
+func TestFileDeadLetterStore(t *testing.T) {
+	fds, err := NewFileDeadLetterStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	letters, err := fds.List()
+	if err != nil || len(letters) != 0 {
+		t.Fatalf("expected no dead letters, got %d (%v)", len(letters), err)
+	}
+
+	letter := DeadLetter{
+		DeliveryID: "d1",
+		Audit:      audit.DataAudit{Metadata: audit.MetadataAudit{Key: "user:42"}},
+		Attempts:   5,
+		LastError:  "webhook responded with status 503",
+	}
+	if err := fds.Add(letter); err != nil {
+		t.Fatalf("failed to add dead letter: %v", err)
+	}
+
+	letters, err = fds.List()
+	if err != nil || len(letters) != 1 || letters[0].DeliveryID != "d1" || letters[0].Audit.Metadata.Key != "user:42" {
+		t.Fatalf("unexpected dead letters: %+v (%v)", letters, err)
+	}
+}
@@ -0,0 +1,18 @@
+package ctxkey
+
+import "context"
+
+type auditKeyKey struct{}
+
+var AuditKeyCtxKey = auditKeyKey{}
+
+func SetAuditKey(ctx context.Context, auditKey string) context.Context {
+	return context.WithValue(ctx, AuditKeyCtxKey, auditKey)
+}
+
+// AuditKey returns the metadata key of the audit being handled and whether
+// one was set.
+func AuditKey(ctx context.Context) (string, bool) {
+	auditKey, ok := ctx.Value(AuditKeyCtxKey).(string)
+	return auditKey, ok
+}
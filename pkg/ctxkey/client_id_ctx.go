@@ -2,12 +2,16 @@ package ctxkey
 
 import "context"
 
-var ClientIDCtxKey = struct{}{}
+type clientIDKey struct{}
+
+var ClientIDCtxKey = clientIDKey{}
 
 func SetClientID(ctx context.Context, clientID string) context.Context {
 	return context.WithValue(ctx, ClientIDCtxKey, clientID)
 }
 
-func ClientID(ctx context.Context) string {
-	return ctx.Value(ClientIDCtxKey).(string)
+// ClientID returns the client id in ctx and whether one was set.
+func ClientID(ctx context.Context) (string, bool) {
+	clientID, ok := ctx.Value(ClientIDCtxKey).(string)
+	return clientID, ok
 }
@@ -2,12 +2,16 @@ package ctxkey
 
 import "context"
 
-var CorrelationIDCtxKey = struct{}{}
+type correlationIDKey struct{}
+
+var CorrelationIDCtxKey = correlationIDKey{}
 
 func SetCorrelationID(ctx context.Context, correlationID string) context.Context {
 	return context.WithValue(ctx, CorrelationIDCtxKey, correlationID)
 }
 
-func CorrelationID(ctx context.Context) string {
-	return ctx.Value(CorrelationIDCtxKey).(string)
+// CorrelationID returns the correlation id in ctx and whether one was set.
+func CorrelationID(ctx context.Context) (string, bool) {
+	correlationID, ok := ctx.Value(CorrelationIDCtxKey).(string)
+	return correlationID, ok
 }
@@ -0,0 +1,18 @@
+package ctxkey
+
+import "context"
+
+type fencingTokenKey struct{}
+
+var FencingTokenCtxKey = fencingTokenKey{}
+
+func SetFencingToken(ctx context.Context, token uint64) context.Context {
+	return context.WithValue(ctx, FencingTokenCtxKey, token)
+}
+
+// FencingToken returns the leader lease token the archival write runs under
+// and whether one was set.
+func FencingToken(ctx context.Context) (uint64, bool) {
+	token, ok := ctx.Value(FencingTokenCtxKey).(uint64)
+	return token, ok
+}
@@ -2,12 +2,16 @@ package ctxkey
 
 import "context"
 
-var RequestIDCtxKey = struct{}{}
+type requestIDKey struct{}
+
+var RequestIDCtxKey = requestIDKey{}
 
 func SetRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, RequestIDCtxKey, requestID)
 }
 
-func RequestID(ctx context.Context) string {
-	return ctx.Value(RequestIDCtxKey).(string)
+// RequestID returns the request id in ctx and whether one was set.
+func RequestID(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(RequestIDCtxKey).(string)
+	return requestID, ok
 }
@@ -0,0 +1,17 @@
+package ctxkey
+
+import "context"
+
+type tenantKey struct{}
+
+var TenantCtxKey = tenantKey{}
+
+func SetTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, TenantCtxKey, tenant)
+}
+
+// Tenant returns the tenant the request acts for and whether one was set.
+func Tenant(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(TenantCtxKey).(string)
+	return tenant, ok
+}